@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/jalandis/elm-protobuf/pkg/elm"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// apiEntry - one definition of the generated Elm API, e.g. a record field,
+// keyed by its kind and qualified Elm name
+type apiEntry struct {
+	Key         string
+	Description string
+}
+
+// readDescriptorSet reads a FileDescriptorSet, as written by protoc
+// --descriptor_set_out.
+func readDescriptorSet(path string) ([]*descriptorpb.FileDescriptorProto, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal descriptor set %s", path)
+	}
+
+	return set.GetFile(), nil
+}
+
+// apiDiff writes the changes to the generated Elm API between the descriptor
+// sets at oldPath and newPath, for the --api-diff command.  Both are
+// described with the same parameters, so that only changes to the protos are
+// reported.
+func apiDiff(w io.Writer, oldPath string, newPath string, p parameters) error {
+	oldFiles, err := readDescriptorSet(oldPath)
+	if err != nil {
+		return err
+	}
+	newFiles, err := readDescriptorSet(newPath)
+	if err != nil {
+		return err
+	}
+
+	oldAPI, err := elmAPI(oldFiles, p)
+	if err != nil {
+		return errors.Wrapf(err, "invalid descriptor set %s", oldPath)
+	}
+	newAPI, err := elmAPI(newFiles, p)
+	if err != nil {
+		return errors.Wrapf(err, "invalid descriptor set %s", newPath)
+	}
+
+	var keys []string
+	for key := range oldAPI {
+		keys = append(keys, key)
+	}
+	for key := range newAPI {
+		if _, ok := oldAPI[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	added, removed, changed := 0, 0, 0
+	for _, key := range keys {
+		before, inOld := oldAPI[key]
+		after, inNew := newAPI[key]
+		switch {
+		case !inOld:
+			fmt.Fprintf(w, "+ %s: %s\n", key, after)
+			added++
+		case !inNew:
+			fmt.Fprintf(w, "- %s: %s\n", key, before)
+			removed++
+		case before != after:
+			fmt.Fprintf(w, "~ %s: %s -> %s\n", key, before, after)
+			changed++
+		}
+	}
+
+	fmt.Fprintf(w, "%d added, %d removed, %d changed\n", added, removed, changed)
+	return nil
+}
+
+// elmAPI describes the definitions generated for every non-excluded file,
+// using the same type resolution as the generated code.
+func elmAPI(inFiles []*descriptorpb.FileDescriptorProto, p parameters) (map[string]string, error) {
+	p, err := resolveFiles(inFiles, p)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]string{}
+	for _, inFile := range inFiles {
+		if excludedFiles[inFile.GetName()] {
+			continue
+		}
+
+		p.module = p.moduleFor(inFile)
+		p.pkg = inFile.GetPackage()
+		p.scope.Qualified = collidingTypes(inFile, p)
+		pbMessages, err := messages([]string{}, inFile.GetMessageType(), p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid file %s", inFile.GetName())
+		}
+
+		var entries []apiEntry
+		entries = append(entries, enumEntries(enumsToCustomTypes([]string{}, inFile.GetEnumType(), p))...)
+		entries = append(entries, messageEntries(pbMessages)...)
+		for _, entry := range entries {
+			result[p.module+"."+entry.Key] = entry.Description
+		}
+	}
+
+	return result, nil
+}
+
+// enumEntries describes enums and their variants.
+func enumEntries(enums []elm.EnumCustomType) []apiEntry {
+	var result []apiEntry
+	for _, enum := range enums {
+		result = append(result, apiEntry{Key: string(enum.Name), Description: "enum"})
+		for _, variant := range enum.Variants {
+			result = append(result, apiEntry{
+				Key:         string(variant.Name),
+				Description: fmt.Sprintf("variant of %s = %d", enum.Name, variant.Value),
+			})
+		}
+		if enum.Unrecognized != "" {
+			result = append(result, apiEntry{
+				Key:         string(enum.Unrecognized),
+				Description: fmt.Sprintf("variant of %s for unrecognized values", enum.Name),
+			})
+		}
+	}
+
+	return result
+}
+
+// messageEntries describes records, their fields and their oneofs, along with
+// the definitions nested in them.
+func messageEntries(pbMessages []pbMessage) []apiEntry {
+	var result []apiEntry
+	for _, m := range pbMessages {
+		if m.Wrapper != nil {
+			result = append(result, apiEntry{
+				Key:         string(m.Wrapper.Name),
+				Description: fmt.Sprintf("wrapper of %s", m.Wrapper.Type),
+			})
+		} else {
+			result = append(result, apiEntry{Key: string(m.TypeAlias.Name), Description: "record"})
+			for _, field := range m.TypeAlias.Fields {
+				description := fmt.Sprintf("%s : %s", field.Name, field.Type)
+				if field.Number != 0 {
+					description += fmt.Sprintf(" = %d", field.Number)
+				}
+				result = append(result, apiEntry{
+					Key:         fmt.Sprintf("%s.%s", m.TypeAlias.Name, field.Name),
+					Description: description,
+				})
+			}
+		}
+
+		for _, oneof := range m.OneOfCustomTypes {
+			result = append(result, apiEntry{Key: string(oneof.Name), Description: "oneof"})
+			for _, variant := range oneof.Variants {
+				result = append(result, apiEntry{
+					Key:         string(variant.Name),
+					Description: fmt.Sprintf("variant of %s with %s = %d", oneof.Name, variant.Type, variant.Num),
+				})
+			}
+		}
+		result = append(result, enumEntries(m.EnumCustomTypes)...)
+		result = append(result, messageEntries(m.NestedMessages)...)
+	}
+
+	return result
+}
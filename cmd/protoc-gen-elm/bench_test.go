@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// largeEnumFile returns a proto3 file declaring an enum with the given number
+// of values and a message holding it.
+func largeEnumFile(values int) *descriptorpb.FileDescriptorProto {
+	enum := &descriptorpb.EnumDescriptorProto{Name: proto.String("Large")}
+	for i := 0; i < values; i++ {
+		enum.Value = append(enum.Value, &descriptorpb.EnumValueDescriptorProto{
+			Name:   proto.String(fmt.Sprintf("LARGE_VALUE_%d", i)),
+			Number: proto.Int32(int32(i)),
+		})
+	}
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:     proto.String("large_enum.proto"),
+		Syntax:   proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{enum},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Holder"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("large"),
+				JsonName: proto.String("large"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(),
+				TypeName: proto.String(".Large"),
+			}},
+		}},
+	}
+}
+
+// multiFileRequest returns a shared proto3 file and the given number of files
+// importing it, each with a message referencing the shared enum and message.
+func multiFileRequest(files int) []*descriptorpb.FileDescriptorProto {
+	shared := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("shared.proto"),
+		Package: proto.String("shared"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STATUS_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("STATUS_ACTIVE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Audit"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				scalarField("author", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				scalarField("revision", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+			},
+		}},
+	}
+
+	inFiles := []*descriptorpb.FileDescriptorProto{shared}
+	for i := 0; i < files; i++ {
+		status := scalarField("status", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM)
+		status.TypeName = proto.String(".shared.Status")
+		audit := scalarField("audit", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE)
+		audit.TypeName = proto.String(".shared.Audit")
+		tags := scalarField("tags", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+		tags.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+		inFiles = append(inFiles, &descriptorpb.FileDescriptorProto{
+			Name:       proto.String(fmt.Sprintf("record%d.proto", i)),
+			Package:    proto.String("records"),
+			Syntax:     proto.String("proto3"),
+			Dependency: []string{"shared.proto"},
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String(fmt.Sprintf("Record%d", i)),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					scalarField("id", 1, descriptorpb.FieldDescriptorProto_TYPE_UINT64),
+					scalarField("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					status,
+					audit,
+					tags,
+				},
+			}},
+		})
+	}
+
+	return inFiles
+}
+
+func scalarField(name string, number int32, fieldType descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     fieldType.Enum(),
+	}
+}
+
+func benchmarkGenerate(b *testing.B, inFiles []*descriptorpb.FileDescriptorProto, parameter string) {
+	p, err := parseParameters(proto.String(parameter))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		files, err := generateFiles(inFiles, p)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if i == 0 {
+			size := 0
+			for _, file := range files {
+				size += len(file.GetContent())
+			}
+			b.ReportMetric(float64(size), "generated-bytes")
+		}
+	}
+}
+
+// BenchmarkEnumDict compares generating a large enum decoded with a case (the
+// default) and with the enum-dict parameter, reporting the size of the
+// generated code alongside the time taken.
+func BenchmarkEnumDict(b *testing.B) {
+	inFiles := []*descriptorpb.FileDescriptorProto{largeEnumFile(500)}
+	b.Run("case", func(b *testing.B) {
+		benchmarkGenerate(b, inFiles, "")
+	})
+	b.Run("dict", func(b *testing.B) {
+		benchmarkGenerate(b, inFiles, "enum-dict=1")
+	})
+}
+
+// BenchmarkCompiledTemplate compares cloning the cached file template, as
+// each generated file does, with parsing the template set from scratch.
+func BenchmarkCompiledTemplate(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := compiledTemplate(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("parsed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := parseTemplates(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkGenerateFiles generates a request with many files, which are
+// templated concurrently. Run it with -race to check the workers don't share
+// state.
+func BenchmarkGenerateFiles(b *testing.B) {
+	benchmarkGenerate(b, multiFileRequest(50), "")
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// elmPackagesUsed are the packages that generated code may import, depending
+// on the plugin parameters.
+var elmPackagesUsed = []string{
+	"elm/time",
+	"elm/bytes",
+	"jweir/elm-iso8601",
+	"NoRedInk/elm-json-decode-pipeline",
+	"miniBill/elm-codec",
+	"eriktim/elm-protocol-buffers",
+}
+
+// compileSkipped lists the diff tests whose expected output imports modules
+// that the project using the plugin is expected to provide.
+var compileSkipped = map[string]string{
+	"runtime_module": "imports the Acme.Protobuf runtime module",
+	"scalar_map":     "imports the user supplied Decimal module",
+}
+
+// TestGeneratedElmCompiles compiles the expected output of every diff test
+// with elm make, so that template mistakes that still generate (and so still
+// pass the diff tests) are caught. The diff tests check that the plugin
+// generates exactly the expected output, so together they cover the generated
+// code.
+func TestGeneratedElmCompiles(t *testing.T) {
+	elm := lookupElm(t)
+	root := repoRoot(t)
+	project := newElmProject(t, elm, filepath.Join(root, "elm-project", "src"))
+
+	tests, err := ioutil.ReadDir(filepath.Join(root, "test-diffs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if !test.IsDir() {
+			continue
+		}
+		name := test.Name()
+		t.Run(name, func(t *testing.T) {
+			if reason, ok := compileSkipped[name]; ok {
+				t.Skip(reason)
+			}
+
+			modules := project.copyModules(t, filepath.Join(root, "test-diffs", name, "expected_output"))
+			if len(modules) == 0 {
+				t.Skip("no generated Elm")
+			}
+
+			cmd := exec.Command(elm, append([]string{"make", "--output=/dev/null"}, modules...)...)
+			cmd.Dir = project.dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("generated Elm does not compile: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+// lookupElm returns the path of the elm compiler, skipping the test when it
+// isn't installed or slow tests aren't wanted.
+func lookupElm(t *testing.T) string {
+	if testing.Short() {
+		t.Skip("compiling generated Elm is slow")
+	}
+	elm, err := exec.LookPath("elm")
+	if err != nil {
+		t.Skip("elm is not installed")
+	}
+
+	return elm
+}
+
+func repoRoot(t *testing.T) string {
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return root
+}
+
+type elmProject struct {
+	dir string
+	src string
+}
+
+// newElmProject creates an Elm application with the runtime and the packages
+// generated code may import. elm install picks versions that work together, so
+// the packages are only resolved once.
+func newElmProject(t *testing.T, elm, runtimeDir string) elmProject {
+	dir, err := ioutil.TempDir("", "elm-protobuf-compile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	elmJSON := `{
+    "type": "application",
+    "source-directories": [
+        "src",
+        "` + runtimeDir + `"
+    ],
+    "elm-version": "0.19.1",
+    "dependencies": {
+        "direct": {
+            "elm/core": "1.0.5",
+            "elm/json": "1.1.3"
+        },
+        "indirect": {}
+    },
+    "test-dependencies": {
+        "direct": {},
+        "indirect": {}
+    }
+}
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "elm.json"), []byte(elmJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pkg := range elmPackagesUsed {
+		cmd := exec.Command(elm, "install", pkg)
+		cmd.Dir = dir
+		cmd.Stdin = strings.NewReader("y\n")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("elm install %s: %v\n%s", pkg, err, out)
+		}
+	}
+
+	return elmProject{dir: dir, src: filepath.Join(dir, "src")}
+}
+
+// copyModules replaces the project's sources with the Elm files in dir and
+// returns their new paths.
+func (p elmProject) copyModules(t *testing.T, dir string) []string {
+	p.clear(t)
+
+	var modules []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".elm") {
+			return err
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		modules = append(modules, p.writeModule(t, string(content)))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return modules
+}
+
+// clear removes the project's sources and build artifacts.
+func (p elmProject) clear(t *testing.T) {
+	for _, path := range []string{p.src, filepath.Join(p.dir, "elm-stuff")} {
+		if err := os.RemoveAll(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// writeModule adds an Elm module to the project's sources and returns its
+// path. The file is placed at the path elm make expects for its module name,
+// since parameters such as module-prefix, file-suffix and flatten-output
+// generate files that don't follow it.
+func (p elmProject) writeModule(t *testing.T, content string) string {
+	module := declaredModule(content)
+	if module == "" {
+		t.Fatalf("no module declaration in:\n%s", content)
+	}
+
+	path := filepath.Join(p.src, filepath.FromSlash(strings.ReplaceAll(module, ".", "/"))+".elm")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// declaredModule returns the module name from the module declaration of an
+// Elm file, or an empty string when there is none.
+func declaredModule(content string) string {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && fields[0] == "port" {
+			fields = fields[1:]
+		}
+		if len(fields) >= 2 && fields[0] == "module" {
+			return fields[1]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jalandis/elm-protobuf/pkg/elm"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// nullsFile returns a proto3 file with a Holder message that has a field of
+// each kind decoded from its own slot of the javascript array format.
+func nullsFile() *descriptorpb.FileDescriptorProto {
+	values := scalarField("values", 3, descriptorpb.FieldDescriptorProto_TYPE_INT32)
+	values.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	counts := scalarField("counts", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE)
+	counts.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	counts.TypeName = proto.String(".Holder.CountsEntry")
+	limit := scalarField("limit", 5, descriptorpb.FieldDescriptorProto_TYPE_INT32)
+	limit.Proto3Optional = proto.Bool(true)
+	limit.OneofIndex = proto.Int32(0)
+	inner := scalarField("inner", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE)
+	inner.TypeName = proto.String(".Inner")
+	inners := scalarField("inners", 7, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE)
+	inners.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	inners.TypeName = proto.String(".Inner")
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:   proto.String("nulls.proto"),
+		Syntax: proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Holder"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				scalarField("count", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+				scalarField("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				values,
+				counts,
+				limit,
+				inner,
+				inners,
+			},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_limit")}},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name:    proto.String("CountsEntry"),
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				Field: []*descriptorpb.FieldDescriptorProto{
+					scalarField("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					scalarField("value", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+				},
+			}},
+		}, {
+			Name: proto.String("Inner"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				scalarField("n", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+			},
+		}},
+	}
+}
+
+// decodeCase - JSON in the javascript array format, and the Elm expression for
+// the Holder it should decode to
+type decodeCase struct {
+	name string
+	json string
+	want string
+}
+
+// TestGeneratedDecodersExplicitNulls decodes messages with the port decoders
+// the plugin generates, checking that explicit nulls, which some javascript
+// bridges write for unset fields, decode to the field's default.
+func TestGeneratedDecodersExplicitNulls(t *testing.T) {
+	elmPath := lookupElm(t)
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+	restoreGlobals(t)
+
+	cases := []decodeCase{
+		{
+			name: "absent slots",
+			json: `[]`,
+			want: `defaultHolder`,
+		},
+		{
+			name: "null scalar, list and map slots",
+			json: `[null, null, null, null, null]`,
+			want: `defaultHolder`,
+		},
+		{
+			name: "null slots beside values",
+			json: `[3, null, [1, 2], null, 4]`,
+			want: `{ defaultHolder | count = 3, values = [ 1, 2 ], limit = Just 4 }`,
+		},
+		{
+			name: "values beside null slots",
+			json: `[null, "x", null, [["a", 1]], null]`,
+			want: `{ defaultHolder | name = "x", counts = Dict.fromList [ ( "a", 1 ) ] }`,
+		},
+		{
+			name: "null message slots",
+			json: `[5, "x", null, null, null, null, null]`,
+			want: `{ defaultHolder | count = 5, name = "x" }`,
+		},
+		{
+			name: "message values beside null slots",
+			json: `[null, null, null, null, null, [1], [[2], [3]]]`,
+			want: `{ defaultHolder | inner = Just { n = 1 }, inners = [ { n = 2 }, { n = 3 } ] }`,
+		},
+	}
+
+	p, err := parseParameters(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := generateFiles([]*descriptorpb.FileDescriptorProto{nullsFile()}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project := newElmProject(t, elmPath, filepath.Join(repoRoot(t), "elm-project", "src"))
+	for _, file := range files {
+		project.writeModule(t, file.GetContent())
+	}
+	main := project.writeModule(t, decodeProgram(cases))
+
+	cmd := exec.Command(elmPath, "make", main, "--output=main.js")
+	cmd.Dir = project.dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("decoding program does not compile: %v\n%s", err, out)
+	}
+
+	runner := filepath.Join(project.dir, "run.js")
+	if err := ioutil.WriteFile(runner, []byte(decodeRunner), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd = exec.Command(node, runner)
+	cmd.Dir = project.dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("running decoding program: %v\n%s", err, out)
+	}
+
+	var results []string
+	if err := json.Unmarshal(out, &results); err != nil {
+		t.Fatalf("reading results %q: %v", out, err)
+	}
+	if len(results) != len(cases) {
+		t.Fatalf("got %d results for %d cases: %q", len(results), len(cases), results)
+	}
+	for i, result := range results {
+		if result != "ok" {
+			t.Errorf("%s: %s", cases[i].name, result)
+		}
+	}
+}
+
+// decodeProgram returns an Elm worker that decodes each case with
+// holderPortDecoder when it receives a message on its run port, and sends back
+// "ok" or the reason for each case that failed.
+func decodeProgram(cases []decodeCase) string {
+	checks := make([]string, len(cases))
+	for i, c := range cases {
+		checks[i] = fmt.Sprintf("check %s (%s)", elm.StringLiteral(c.json), c.want)
+	}
+
+	return `port module Main exposing (main)
+
+import Dict
+import Json.Decode as JD
+import Nulls exposing (..)
+
+
+port run : (JD.Value -> msg) -> Sub msg
+
+
+port results : List String -> Cmd msg
+
+
+check : String -> Holder -> String
+check json want =
+    case JD.decodeString holderPortDecoder json of
+        Ok got ->
+            if got == want then
+                "ok"
+
+            else
+                "decoded " ++ Debug.toString got
+
+        Err err ->
+            JD.errorToString err
+
+
+main : Program () () JD.Value
+main =
+    Platform.worker
+        { init = \_ -> ( (), Cmd.none )
+        , update =
+            \_ model ->
+                ( model
+                , results
+                    [ ` + strings.Join(checks, "\n                    , ") + `
+                    ]
+                )
+        , subscriptions = \_ -> run identity
+        }
+`
+}
+
+// decodeRunner runs the compiled decoding program with node, printing its
+// results as JSON.
+const decodeRunner = `const { Elm } = require("./main.js");
+
+const app = Elm.Main.init();
+app.ports.results.subscribe((results) => {
+  console.log(JSON.stringify(results));
+});
+app.ports.run.send(null);
+`
@@ -2,18 +2,27 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
+	"unicode"
 
 	"github.com/jalandis/elm-protobuf/pkg/stringextras"
 	"github.com/jalandis/elm-protobuf/pkg/elm"
+	"github.com/jalandis/elm-protobuf/pkg/options"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/pluginpb"
@@ -23,76 +32,756 @@ const (
 	version = "0.0.2"
 	docUrl  = "https://github.com/jalandis/elm-protobuf"
 
-	extension = ".elm"
+	defaultExtension = ".elm"
+
+	defaultRuntimeModule = "Protobuf"
+
+	manifestName = "manifest.json"
+
+	dependenciesName = "dependencies.json"
+
+	// Messages are encoded for ports as javascript arrays indexed by field
+	// number, so sparse field numbers leave runs of empty slots that the
+	// encoder has to fill with nulls.  Past sparseFieldsWarning empty slots we
+	// warn, and past sparseFieldsLimit the generated code is unusable.
+	sparseFieldsWarning = 256
+	sparseFieldsLimit   = 65536
+
+	// Editions support is newer than the pluginpb package, which lacks the
+	// feature bit and the response fields for the supported edition range, so
+	// they use the numbers from plugin.proto.
+	featureSupportsEditions     = 2
+	responseMinimumEditionField = 3
+	responseMaximumEditionField = 4
 )
 
-var excludedFiles = map[string]bool{
-	"google/protobuf/timestamp.proto":  true,
-	"google/protobuf/wrappers.proto":   true,
-	"google/protobuf/descriptor.proto": true,
+var defaultBanner = []string{
+	"DO NOT EDIT",
+	"AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER",
+	"https://github.com/tiziano88/elm-protobuf",
+}
+
+var excludedFiles = defaultExcludedFiles()
+
+// defaultExcludedFiles are the files no Elm modules are generated for: the
+// well known types, which are handled by the runtime helpers, and the
+// descriptors custom options are defined with.
+func defaultExcludedFiles() map[string]bool {
+	result := map[string]bool{
+		"google/protobuf/descriptor.proto": true,
+		options.File:                       true,
+		options.ValidateFile:               true,
+	}
+	for name := range elm.WellKnownTypeFiles {
+		result[name] = true
+	}
+
+	return result
 }
 
 type parameters struct {
 	Version          bool
 	Debug            bool
 	RemoveDeprecated bool
+	StripEnumPrefix  bool
+	FlattenOutput    bool
+	OmitDefaults     bool
+	KeepUnknownEnums bool
+	StringHelpers    bool
+	Manifest         bool
+	Dependencies     bool
+	DryRun           bool
+	Merge            bool
+	LenientLists     bool
+	MaybeOneofs      bool
+	ArrayHelpers     bool
+	StreamHelpers    bool
+	Format           bool
+	Builders         bool
+	Validators       bool
+	OneofAccessors   bool
+	EnumStrings      bool
+	ExplicitExposing bool
+	IgnoreUnknown    bool
+	enumDict         int
 	modPrefix        string
+	modulesFromPkg   bool
+	runtimeModule    string
+	sharedHelpers    string
+	registry         string
+	backend          elm.Backend
+	json             elm.JSONFormat
+	jsonEncoder      elm.JSONFormat
+	decoderStyle     elm.DecoderStyle
+	repeated         elm.Repeated
+	logLevel         logLevel
+	includes         []string
+	wrapTypes        map[string]elm.Type
+	scalarTypes      map[string]elm.ScalarType
+	timestamp        string
+	enumDefault      string
+	renameOption     string
+	fileSuffix       string
+	banner           []string
+	// scope.Qualified is set for each file, alongside module and pkg.
+	scope elm.Scope
+
+	// Set by generateFiles and templateFile rather than by the user.
+	files          map[string]*descriptorpb.FileDescriptorProto
+	enumModules    map[string]string
+	enums          map[string]enumDefinition
+	typeModules    map[string]string
+	modulePrefixes map[string]string
+	included       map[string]bool
+	module         string
+	pkg            string
 }
 
 func parseParameters(input *string) (parameters, error) {
-	var result parameters
+	result := parameters{
+		runtimeModule: defaultRuntimeModule,
+		backend:       elm.PortsBackend,
+		json:          elm.ArrayFormat,
+		decoderStyle:  elm.ChainStyle,
+		repeated:      elm.ListRepeated,
+		logLevel:      warnLevel,
+		jsonEncoder:   elm.ArrayFormat,
+		fileSuffix:    defaultExtension,
+		banner:        defaultBanner,
+	}
 	var err error
+	// Unknown parameters are only reported once they have all been read,
+	// since ignore-unknown-params may come after them.
+	var unknown []string
 
 	if input == nil {
 		return result, nil
 	}
 
-	for _, v := range strings.Split(*input, ",") {
-		parts := strings.Split(v, "=")
-		name := parts[0]
-		v := parts[1:]
+	for _, param := range strings.Split(*input, ",") {
+		// Only the first invalid parameter is reported.
+		if err != nil {
+			break
+		}
+
+		// Tolerate empty parameters, e.g. from `--elm_opt=` or a trailing comma.
+		param = strings.TrimSpace(param)
+		if param == "" {
+			continue
+		}
+
+		parts := strings.SplitN(param, "=", 2)
+		name := strings.TrimSpace(parts[0])
+		value := ""
+		if len(parts) > 1 {
+			value = strings.TrimSpace(parts[1])
+		}
 		switch name {
 		case "remove-deprecated":
 			result.RemoveDeprecated = true
 		case "debug":
 			result.Debug = true
+		case "verbose":
+			result.logLevel = debugLevel
+		case "log-level":
+			level, ok := logLevels[value]
+			if !ok {
+				err = fmt.Errorf("unknown log-level: \"%s\", expected error, warn, info or debug", value)
+				continue
+			}
+			result.logLevel = level
+		case "strip-enum-prefix":
+			result.StripEnumPrefix = true
+		case "flatten-output":
+			result.FlattenOutput = true
+		case "omit-defaults":
+			result.OmitDefaults = true
+		case "keep-unknown-enums":
+			result.KeepUnknownEnums = true
+		case "string-helpers":
+			result.StringHelpers = true
+		case "manifest":
+			result.Manifest = true
+		case "dependencies":
+			result.Dependencies = true
+		case "dry-run":
+			result.DryRun = true
+		case "merge":
+			result.Merge = true
+		case "lenient-lists":
+			result.LenientLists = true
+		case "maybe-oneofs":
+			result.MaybeOneofs = true
+		case "array-helpers":
+			result.ArrayHelpers = true
+		case "stream-helpers":
+			result.StreamHelpers = true
+		case "format":
+			result.Format = true
+		case "builders":
+			result.Builders = true
+		case "validators":
+			result.Validators = true
+		case "oneof-accessors":
+			result.OneofAccessors = true
+		case "enum-strings":
+			result.EnumStrings = true
+		case "explicit-exposing":
+			result.ExplicitExposing = true
+		case "max-nested-name-length":
+			n, convErr := strconv.Atoi(value)
+			if convErr != nil || n < 1 {
+				err = fmt.Errorf("invalid max-nested-name-length: \"%s\"", value)
+				continue
+			}
+			result.scope.MaxNestedNameLength = n
+		case "enum-dict":
+			n, convErr := strconv.Atoi(value)
+			if convErr != nil || n < 1 {
+				err = fmt.Errorf("invalid enum-dict: \"%s\"", value)
+				continue
+			}
+			result.enumDict = n
+		case "enum-default":
+			switch value {
+			case "first", "zero":
+				result.enumDefault = value
+			default:
+				err = fmt.Errorf("unknown enum default: \"%s\", expected first or zero", value)
+				continue
+			}
 		case "module-prefix":
-			result.modPrefix = v[0]
+			prefix, prefixErr := normalizeModulePrefix(value)
+			if prefixErr != nil {
+				err = prefixErr
+				continue
+			}
+			result.modPrefix = prefix
+		case "module-from":
+			switch value {
+			case "path":
+				result.modulesFromPkg = false
+			case "package":
+				result.modulesFromPkg = true
+			default:
+				err = fmt.Errorf("unknown module-from: \"%s\", expected path or package", value)
+				continue
+			}
+		case "runtime-module":
+			if value == "" {
+				err = fmt.Errorf("runtime-module requires a module name")
+				continue
+			}
+			result.runtimeModule = value
+		case "codec-prefix":
+			if !isVariableName(value) {
+				err = fmt.Errorf("invalid codec-prefix: \"%s\", expected a lowercase Elm name such as pb", value)
+				continue
+			}
+			result.scope.CodecPrefix = value
+		case "shared-helpers":
+			if value == "" {
+				err = fmt.Errorf("shared-helpers requires a module name")
+				continue
+			}
+			result.sharedHelpers = value
+		case "registry":
+			if value == "" {
+				err = fmt.Errorf("registry requires a module name")
+				continue
+			}
+			result.registry = value
+		case "backend":
+			switch b := elm.Backend(value); b {
+			case elm.PortsBackend, elm.CodecBackend, elm.BinaryBackend:
+				result.backend = b
+			default:
+				err = fmt.Errorf("unknown backend: \"%s\"", value)
+				continue
+			}
+			elm.SelectedBackend = result.backend
+		case "json":
+			switch f := elm.JSONFormat(value); f {
+			case elm.ArrayFormat, elm.BothFormats:
+				result.json = f
+			default:
+				err = fmt.Errorf("unknown json format: \"%s\", expected array or both", value)
+				continue
+			}
+		case "json-encoder":
+			switch f := elm.JSONFormat(value); f {
+			case elm.ArrayFormat, elm.ObjectFormat:
+				result.jsonEncoder = f
+			default:
+				err = fmt.Errorf("unknown json-encoder format: \"%s\", expected array or object", value)
+				continue
+			}
+		case "decoder-style":
+			switch s := elm.DecoderStyle(value); s {
+			case elm.ChainStyle, elm.PipelineStyle:
+				result.decoderStyle = s
+			default:
+				err = fmt.Errorf("unknown decoder-style: \"%s\", expected chain or pipeline", value)
+				continue
+			}
+			elm.SelectedDecoderStyle = result.decoderStyle
+		case "repeated":
+			switch r := elm.Repeated(value); r {
+			case elm.ListRepeated, elm.ArrayRepeated:
+				result.repeated = r
+			default:
+				err = fmt.Errorf("unknown repeated type: \"%s\", expected list or array", value)
+				continue
+			}
+			elm.SelectedRepeated = result.repeated
+		case "wrap-type":
+			parts := strings.SplitN(value, ":", 2)
+			if len(parts) != 2 || parts[1] == "" {
+				err = fmt.Errorf("invalid wrap-type: \"%s\", expected .pkg.Message:ElmType", value)
+				continue
+			}
+			if result.wrapTypes == nil {
+				result.wrapTypes = map[string]elm.Type{}
+			}
+			result.wrapTypes["."+strings.TrimPrefix(parts[0], ".")] = elm.Type(parts[1])
+		case "scalar-map":
+			parts := strings.Split(value, ":")
+			if len(parts) < 4 || len(parts) > 5 {
+				err = fmt.Errorf("invalid scalar-map: \"%s\", expected type:ElmType:decoder:encoder[:default]", value)
+				continue
+			}
+			if result.scalarTypes == nil {
+				result.scalarTypes = map[string]elm.ScalarType{}
+			}
+			t := elm.ScalarType{
+				Type:    elm.Type(parts[1]),
+				Decoder: elm.VariableName(parts[2]),
+				Encoder: elm.VariableName(parts[3]),
+			}
+			if len(parts) == 5 {
+				t.Default = parts[4]
+			}
+			result.scalarTypes[parts[0]] = t
+		case "timestamp":
+			switch value {
+			case "rfc3339", "millis", "posix":
+				result.timestamp = value
+			default:
+				err = fmt.Errorf("unknown timestamp format: \"%s\", expected rfc3339, millis or posix", value)
+				continue
+			}
+		case "file-suffix":
+			if value == "" {
+				err = fmt.Errorf("file-suffix requires a suffix")
+				continue
+			}
+			result.fileSuffix = value
+		case "banner":
+			// An empty banner removes the header comment entirely.
+			result.banner = nil
+			if value != "" {
+				result.banner = []string{value}
+			}
+		case "rename-option":
+			if value == "" {
+				err = fmt.Errorf("rename-option requires an extension name")
+				continue
+			}
+			result.renameOption = "." + strings.TrimPrefix(value, ".")
+		case "include":
+			if value == "" {
+				err = fmt.Errorf("include requires a type name")
+				continue
+			}
+			result.includes = append(result.includes, "."+strings.TrimPrefix(value, "."))
 		case "exclude":
-			excludedFiles[v[0]] = true
+			if value == "" {
+				err = fmt.Errorf("exclude requires a file name")
+				continue
+			}
+			excludedFiles[value] = true
+		case "ignore-unknown-params":
+			result.IgnoreUnknown = true
 		default:
-			err = fmt.Errorf("unknown parameter: \"%s\"", name)
+			unknown = append(unknown, name)
+		}
+	}
+
+	if err == nil && len(unknown) > 0 && !result.IgnoreUnknown {
+		err = fmt.Errorf("unknown parameter: \"%s\"", unknown[0])
+	}
+
+	if err == nil && result.StringHelpers && result.backend == elm.BinaryBackend {
+		err = fmt.Errorf("string-helpers is not supported by the binary backend")
+	}
+	if err == nil && len(result.wrapTypes) > 0 && result.backend == elm.BinaryBackend {
+		err = fmt.Errorf("wrap-type is not supported by the binary backend")
+	}
+	if err == nil && result.json == elm.BothFormats && result.backend != elm.PortsBackend {
+		err = fmt.Errorf("json=both is only supported by the ports backend")
+	}
+	if err == nil && result.json == elm.BothFormats && len(result.wrapTypes) > 0 {
+		err = fmt.Errorf("wrap-type is not supported with json=both")
+	}
+	if err == nil && result.jsonEncoder == elm.ObjectFormat && result.json != elm.BothFormats {
+		err = fmt.Errorf("json-encoder=object requires json=both")
+	}
+	if err == nil && result.ArrayHelpers && result.backend == elm.BinaryBackend {
+		err = fmt.Errorf("array-helpers is not supported by the binary backend")
+	}
+	if err == nil && result.ArrayHelpers && result.jsonEncoder == elm.ObjectFormat {
+		err = fmt.Errorf("array-helpers is not supported with json-encoder=object")
+	}
+	if err == nil && result.registry != "" && result.backend == elm.BinaryBackend {
+		err = fmt.Errorf("registry is not supported by the binary backend")
+	}
+	if err == nil && result.StreamHelpers && result.backend == elm.BinaryBackend {
+		err = fmt.Errorf("stream-helpers is not supported by the binary backend")
+	}
+	if err == nil && result.MaybeOneofs && result.backend == elm.BinaryBackend {
+		err = fmt.Errorf("maybe-oneofs is not supported by the binary backend")
+	}
+	if err == nil && len(result.scalarTypes) > 0 && result.backend == elm.BinaryBackend {
+		err = fmt.Errorf("scalar-map is not supported by the binary backend")
+	}
+	if err == nil && result.decoderStyle == elm.PipelineStyle && result.backend == elm.BinaryBackend {
+		err = fmt.Errorf("decoder-style=pipeline is not supported by the binary backend")
+	}
+	if err == nil && result.repeated == elm.ArrayRepeated && result.backend == elm.BinaryBackend {
+		err = fmt.Errorf("repeated=array is not supported by the binary backend")
+	}
+	if err == nil && result.timestamp != "" && result.timestamp != "rfc3339" && result.backend == elm.BinaryBackend {
+		err = fmt.Errorf("timestamp=%s is not supported by the binary backend", result.timestamp)
+	}
+	switch result.timestamp {
+	case "millis":
+		result.scope.SetWellKnownType(".google.protobuf.Timestamp", elm.TimestampMillisType)
+	case "posix":
+		result.scope.SetWellKnownType(".google.protobuf.Timestamp", elm.TimestampPosixType)
+	}
+	for pbType, name := range result.wrapTypes {
+		result.scope.RegisterWrapperType(pbType, name)
+	}
+	for pbType, t := range result.scalarTypes {
+		if registerErr := result.scope.RegisterScalarType(pbType, t); registerErr != nil && err == nil {
+			err = errors.Wrap(registerErr, "invalid scalar-map")
 		}
 	}
 
+	selectedLogLevel = result.logLevel
+	if result.IgnoreUnknown {
+		for _, name := range unknown {
+			warnf("unknown parameter \"%s\" is ignored", name)
+		}
+	}
 	return result, err
 }
 
+// logLevel - how much is logged to stderr, which protoc passes through
+type logLevel int
+
+const (
+	errorLevel logLevel = iota
+	warnLevel
+	infoLevel
+	debugLevel
+)
+
+var logLevels = map[string]logLevel{
+	"error": errorLevel,
+	"warn":  warnLevel,
+	"info":  infoLevel,
+	"debug": debugLevel,
+}
+
+// selectedLogLevel - the most detailed level that is logged
+var selectedLogLevel = warnLevel
+
+func logf(level logLevel, label string, format string, v ...interface{}) {
+	if level <= selectedLogLevel {
+		log.Printf(label+": "+format, v...)
+	}
+}
+
+// fatalf logs an error and exits, whatever the log level.
+func fatalf(format string, v ...interface{}) {
+	log.Fatalf("error: "+format, v...)
+}
+
+// warnf logs problems that don't stop generation, which are logged by default.
+func warnf(format string, v ...interface{}) {
+	logf(warnLevel, "warning", format, v...)
+}
+
+// infof logs progress, with log-level=info or more.
+func infof(format string, v ...interface{}) {
+	logf(infoLevel, "info", format, v...)
+}
+
+// debugf logs generation details, with log-level=debug or verbose.
+func debugf(format string, v ...interface{}) {
+	logf(debugLevel, "debug", format, v...)
+}
+
+// isIncluded reports whether a message or enum is selected by the include
+// parameters.  Everything is included when there are none.
+func (p parameters) isIncluded(preface []string, name string) bool {
+	if p.included == nil {
+		return true
+	}
+
+	return p.included[fullTypeName(p.pkg, append(append([]string(nil), preface...), name))]
+}
+
+// moduleFor returns the name of the module generated for inFile, which is
+// prefixed by its (elm.module_prefix) instead of module-prefix when it has one.
+func (p parameters) moduleFor(inFile *descriptorpb.FileDescriptorProto) string {
+	prefix := p.modPrefix
+	if filePrefix, ok := p.modulePrefixes[inFile.GetName()]; ok {
+		prefix = filePrefix
+	}
+
+	return moduleName(prefix, p.modulePath(inFile))
+}
+
+// modulePath returns the path that the module and file names of inFile are
+// derived from: the proto file path, or with module-from=package, the file's
+// package followed by its base name.  Files without a package always use their
+// path.
+func (p parameters) modulePath(inFile *descriptorpb.FileDescriptorProto) string {
+	if !p.modulesFromPkg || inFile.GetPackage() == "" {
+		return inFile.GetName()
+	}
+
+	return strings.ReplaceAll(inFile.GetPackage(), ".", "/") + "/" + filepath.Base(inFile.GetName())
+}
+
+// fullTypeName returns the fully qualified PB name of a definition, in the
+// form used by field type names (e.g. `.pkg.Outer.Inner`).
+func fullTypeName(pkg string, path []string) string {
+	if pkg != "" {
+		path = append([]string{pkg}, path...)
+	}
+
+	return "." + strings.Join(path, ".")
+}
+
+// fieldOptionNumber finds the field number of the named string extension of
+// google.protobuf.FieldOptions among the input files.
+func fieldOptionNumber(inFiles []*descriptorpb.FileDescriptorProto, name string) (int32, error) {
+	var found *descriptorpb.FieldDescriptorProto
+
+	var search func(prefix []string, pkg string, extensions []*descriptorpb.FieldDescriptorProto, messagePbs []*descriptorpb.DescriptorProto)
+	search = func(prefix []string, pkg string, extensions []*descriptorpb.FieldDescriptorProto, messagePbs []*descriptorpb.DescriptorProto) {
+		for _, ext := range extensions {
+			if fullTypeName(pkg, append(append([]string(nil), prefix...), ext.GetName())) == name {
+				found = ext
+			}
+		}
+		for _, messagePb := range messagePbs {
+			path := append(append([]string(nil), prefix...), messagePb.GetName())
+			search(path, pkg, messagePb.GetExtension(), messagePb.GetNestedType())
+		}
+	}
+	for _, inFile := range inFiles {
+		search(nil, inFile.GetPackage(), inFile.GetExtension(), inFile.GetMessageType())
+	}
+
+	switch {
+	case found == nil:
+		return 0, fmt.Errorf("extension %s is not defined in any imported file", name)
+	case found.GetExtendee() != ".google.protobuf.FieldOptions":
+		return 0, fmt.Errorf("extension %s extends %s, not google.protobuf.FieldOptions", name, found.GetExtendee())
+	case found.GetType() != descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return 0, fmt.Errorf("extension %s is a %s, not a string", name, found.GetType())
+	}
+
+	return found.GetNumber(), nil
+}
+
+// includedTypes returns the fully qualified names of the included messages
+// and enums along with every type they depend on.  Nested definitions are
+// generated along with the messages containing them, so the parents of
+// included types are included as well.
+func includedTypes(inFiles []*descriptorpb.FileDescriptorProto, includes []string) (map[string]bool, error) {
+	messageTypes := map[string]*descriptorpb.DescriptorProto{}
+	enumTypes := map[string]bool{}
+
+	var addMessages func(prefix []string, pkg string, messagePbs []*descriptorpb.DescriptorProto)
+	addMessages = func(prefix []string, pkg string, messagePbs []*descriptorpb.DescriptorProto) {
+		for _, messagePb := range messagePbs {
+			path := append(append([]string(nil), prefix...), messagePb.GetName())
+			messageTypes[fullTypeName(pkg, path)] = messagePb
+			for _, enumPb := range messagePb.GetEnumType() {
+				enumTypes[fullTypeName(pkg, append(path, enumPb.GetName()))] = true
+			}
+			addMessages(path, pkg, messagePb.GetNestedType())
+		}
+	}
+	for _, inFile := range inFiles {
+		for _, enumPb := range inFile.GetEnumType() {
+			enumTypes[fullTypeName(inFile.GetPackage(), []string{enumPb.GetName()})] = true
+		}
+		addMessages(nil, inFile.GetPackage(), inFile.GetMessageType())
+	}
+
+	included := map[string]bool{}
+	var include func(name string)
+	include = func(name string) {
+		if included[name] {
+			return
+		}
+		included[name] = true
+
+		if parent := name[:strings.LastIndex(name, ".")]; messageTypes[parent] != nil {
+			include(parent)
+		}
+
+		for _, fieldPb := range messageTypes[name].GetField() {
+			if fieldPb.GetTypeName() != "" {
+				include(fieldPb.GetTypeName())
+			}
+		}
+	}
+
+	for _, name := range includes {
+		if messageTypes[name] == nil && !enumTypes[name] {
+			return nil, fmt.Errorf("included type %s is not defined", name)
+		}
+		include(name)
+	}
+
+	return included, nil
+}
+
+// parameterDocs describes each parameter understood by parseParameters, for
+// --help.
+var parameterDocs = []struct {
+	Name  string
+	Usage string
+}{
+	{"remove-deprecated", "skip deprecated messages, fields, enums and enum values"},
+	{"debug", "log the raw request received from protoc"},
+	{"verbose", "log each generated file, message and enum (same as log-level=debug)"},
+	{"log-level=error|warn|info|debug", "how much to log to stderr (default warn)"},
+	{"strip-enum-prefix", "strip the enum name from the start of enum value names"},
+	{"flatten-output", "write every file directly into the output directory"},
+	{"omit-defaults", "encode zero values, empty lists and empty maps as null"},
+	{"keep-unknown-enums", "decode unknown enum values to an UnrecognizedFoo Int variant"},
+	{"string-helpers", "generate encodeFoo and decodeFoo JSON string helpers"},
+	{"array-helpers", "generate fooToArray and fooFromArray port helpers"},
+	{"stream-helpers", "generate fooListPortDecoder and helpers decoding newline-delimited JSON"},
+	{"manifest", "also write manifest.json listing the generated modules"},
+	{"dependencies", "also write dependencies.json listing the Elm packages the generated code needs"},
+	{"format", "run generated files through elm-format when it is on the PATH"},
+	{"dry-run", "report the files that would be generated without writing them"},
+	{"merge", "generate mergeFoo functions overlaying non-default fields"},
+	{"lenient-lists", "decode a single value in place of a repeated field's list"},
+	{"maybe-oneofs", "generate oneof fields as a Maybe, Nothing when absent"},
+	{"builders", "generate withFooBar setters to build messages from defaultFoo"},
+	{"validators", "generate validateFoo functions checking a subset of (validate.rules)"},
+	{"oneof-accessors", "generate getFoo and mapFoo accessors for oneof variants"},
+	{"enum-strings", "generate fooToString and fooFromString using enum value names"},
+	{"explicit-exposing", "expose only the generated definitions, not the helpers"},
+	{"ignore-unknown-params", "log a warning for unknown parameters instead of failing"},
+	{"max-nested-name-length=N", "shorten nested definition names longer than N"},
+	{"codec-prefix=prefix", "prepend prefix to generated decoder, encoder and codec names"},
+	{"enum-dict=N", "decode enums with at least N values through a Dict instead of a case"},
+	{"enum-default=first|zero", "default enums to their first declared or their zero-valued variant (default first)"},
+	{"module-prefix=Prefix", "prepend Prefix to every module name"},
+	{"module-from=path|package", "name modules after the proto file path or package (default path)"},
+	{"runtime-module=Module", "import the runtime helpers from Module (default " + defaultRuntimeModule + ")"},
+	{"shared-helpers=Module", "generate the helper functions once, into Module"},
+	{"registry=Module", "generate Module mapping each message's full proto name to a decoder"},
+	{"backend=ports|elm-codec|binary", "choose the generated encoders and decoders (default ports)"},
+	{"json=array|both", "also decode the canonical JSON object format (default array)"},
+	{"json-encoder=array|object", "encode messages as arrays or canonical JSON objects"},
+	{"decoder-style=chain|pipeline", "chain decoders with the runtime or elm-json-decode-pipeline"},
+	{"repeated=list|array", "Elm type of repeated fields (default list)"},
+	{"timestamp=rfc3339|millis|posix", "encode Timestamp as an RFC 3339 string or epoch millis, or decode RFC 3339 strings to Time.Posix"},
+	{"scalar-map=double:Decimal:dec:enc", "use a custom Elm type, decoder and encoder for a scalar type"},
+	{"wrap-type=.pkg.Message:ElmType", "generate a single field message as an opaque type"},
+	{"file-suffix=.gen.elm", "suffix of generated file names (default " + defaultExtension + ")"},
+	{"banner=TEXT", "replace the generated header comment, or remove it if empty"},
+	{"rename-option=pkg.option", "read record field names from a string field option"},
+	{"include=pkg.Message", "only generate the given type and its dependencies"},
+	{"exclude=path/to/file.proto", "skip generating the given file"},
+}
+
+// supportedFeatures lists what --version reports the plugin can generate.
+var supportedFeatures = []string{
+	"proto2 and proto3 syntax, including proto3 optional fields",
+	"edition 2023, using the field_presence feature",
+	"messages, nested messages, enums, oneofs and maps",
+	"proto2 extensions, kept as raw values by field number",
+	"proto2 groups, as nested messages",
+	"well known types: Timestamp, the wrapper types, Struct, Value, ListValue and NullValue",
+	"custom options: (elm.field_name), (elm.default) and (elm.module_prefix)",
+}
+
+func printVersion() {
+	fmt.Fprintf(os.Stdout, "%v %v\n\nSupported features:\n", filepath.Base(os.Args[0]), version)
+	for _, feature := range supportedFeatures {
+		fmt.Fprintf(os.Stdout, "  %s\n", feature)
+	}
+
+	fmt.Fprintf(os.Stdout, "\nBackends:\n")
+	for _, backend := range []elm.Backend{elm.PortsBackend, elm.CodecBackend, elm.BinaryBackend} {
+		fmt.Fprintf(os.Stdout, "  %s\n", backend)
+	}
+}
+
+func printHelp() {
+	fmt.Fprintf(os.Stdout, "Usage: protoc --elm_out=DIR [--elm_opt=PARAM[,PARAM...]] FILE.proto...\n")
+	fmt.Fprintf(os.Stdout, "       %s --api-diff OLD.pb NEW.pb [PARAM[,PARAM...]]\n\nParameters:\n", filepath.Base(os.Args[0]))
+	for _, doc := range parameterDocs {
+		fmt.Fprintf(os.Stdout, "  %-34s %s\n", doc.Name, doc.Usage)
+	}
+	fmt.Fprintf(os.Stdout, "\nSee "+docUrl+" for more information.\n")
+}
+
 func main() {
+	log.SetFlags(0)
+	log.SetPrefix("protoc-gen-elm: ")
+
 	if len(os.Args) == 2 && os.Args[1] == "--version" {
-		fmt.Fprintf(os.Stdout, "%v %v\n", filepath.Base(os.Args[0]), version)
+		printVersion()
 		os.Exit(0)
 	}
 	if len(os.Args) == 2 && os.Args[1] == "--help" {
-		fmt.Fprintf(os.Stdout, "See "+docUrl+" for usage information.\n")
+		printHelp()
+		os.Exit(0)
+	}
+	if (len(os.Args) == 4 || len(os.Args) == 5) && os.Args[1] == "--api-diff" {
+		var parameter *string
+		if len(os.Args) == 5 {
+			parameter = &os.Args[4]
+		}
+		parameters, err := parseParameters(parameter)
+		if err != nil {
+			fatalf("Failed to parse parameters: %v", err)
+		}
+		if err := apiDiff(os.Stdout, os.Args[2], os.Args[3], parameters); err != nil {
+			fatalf("Could not compare descriptor sets: %v", err)
+		}
 		os.Exit(0)
 	}
 
 	data, err := ioutil.ReadAll(os.Stdin)
 	if err != nil {
-		log.Fatalf("Could not read request from STDIN: %v", err)
+		fatalf("Could not read request from STDIN: %v", err)
 	}
 
 	req := &pluginpb.CodeGeneratorRequest{}
 
 	err = proto.Unmarshal(data, req)
 	if err != nil {
-		log.Fatalf("Could not unmarshal request: %v", err)
+		fatalf("Could not unmarshal request: %v", err)
 	}
 
 	parameters, err := parseParameters(req.Parameter)
 	if err != nil {
-		log.Fatalf("Failed to parse parameters: %v", err)
+		fatalf("Failed to parse parameters: %v", err)
 	}
 
 	if parameters.Debug {
@@ -103,42 +792,569 @@ func main() {
 
 		result, err := proto.Marshal(req)
 		if err != nil {
-			log.Fatalf("Failed to marshal request: %v", err)
+			fatalf("Failed to marshal request: %v", err)
 		}
 
 		log.Printf("Input data: %s", result)
 	}
 
-	resp := &pluginpb.CodeGeneratorResponse{}
-	for _, inFile := range req.GetProtoFile() {
-		log.Printf("Processing file %s", inFile.GetName())
+	checkCompilerVersion(req.GetCompilerVersion(), req.GetProtoFile(), parameters)
+
+	resp := &pluginpb.CodeGeneratorResponse{
+		SupportedFeatures: proto.Uint64(uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL) | featureSupportsEditions),
+	}
+	unknown := protowire.AppendTag(nil, responseMinimumEditionField, protowire.VarintType)
+	unknown = protowire.AppendVarint(unknown, uint64(options.EditionProto2))
+	unknown = protowire.AppendTag(unknown, responseMaximumEditionField, protowire.VarintType)
+	unknown = protowire.AppendVarint(unknown, uint64(options.Edition2023))
+	resp.ProtoReflect().SetUnknown(unknown)
+
+	resp.File, err = generateFiles(req.GetProtoFile(), parameters)
+	if err != nil {
+		fatalf("Could not template file: %v", err)
+	}
+	if parameters.Format {
+		formatFiles(resp.File, parameters.fileSuffix)
+	}
+	if parameters.DryRun {
+		reportDryRun(resp.File)
+		resp.File = nil
+	}
+
+	data, err = proto.Marshal(resp)
+	if err != nil {
+		fatalf("Could not marshal response: %v [%v]", err, resp)
+	}
+
+	_, err = os.Stdout.Write(data)
+	if err != nil {
+		fatalf("Could not write response to STDOUT: %v", err)
+	}
+}
+
+// checkCompilerVersion warns about input files using features that the
+// version of protoc generating them only supports experimentally, since
+// experimental descriptors may not be what the generator expects.  Versions of
+// protoc that don't report their version are not checked.
+func checkCompilerVersion(version *pluginpb.Version, inFiles []*descriptorpb.FileDescriptorProto, p parameters) {
+	if version == nil {
+		return
+	}
+
+	name := fmt.Sprintf("%d.%d.%d", version.GetMajor(), version.GetMinor(), version.GetPatch())
+	if version.GetSuffix() != "" {
+		name += "-" + version.GetSuffix()
+	}
+	debugf("Generating for protoc %s", name)
+
+	for _, inFile := range inFiles {
+		if excludedFiles[inFile.GetName()] {
+			continue
+		}
+
+		// Editions became stable in protoc 27.0, which reports itself as 5.27.
+		if _, ok := options.FileEdition(inFile); ok && !compilerAtLeast(version, 5, 27) {
+			warnf("%s uses editions, which protoc %s only supports experimentally (editions are supported from protoc 27.0)", inFile.GetName(), name)
+		}
+		if hasProto3Optional(inFile.GetMessageType()) && !compilerAtLeast(version, 3, 15) {
+			warnf("%s has proto3 optional fields, which protoc %s only supports experimentally (proto3 optional fields are supported from protoc 3.15)", inFile.GetName(), name)
+		}
+	}
+}
+
+// compilerAtLeast reports whether version is major.minor or newer.
+func compilerAtLeast(version *pluginpb.Version, major int32, minor int32) bool {
+	if version.GetMajor() != major {
+		return version.GetMajor() > major
+	}
+
+	return version.GetMinor() >= minor
+}
+
+// hasProto3Optional reports whether any field of messagePbs is a proto3
+// optional field.
+func hasProto3Optional(messagePbs []*descriptorpb.DescriptorProto) bool {
+	for _, messagePb := range messagePbs {
+		for _, fieldPb := range messagePb.GetField() {
+			if fieldPb.GetProto3Optional() {
+				return true
+			}
+		}
+		if hasProto3Optional(messagePb.GetNestedType()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatFiles runs the generated Elm files through elm-format, for the format
+// parameter.  Files are left as generated when elm-format isn't on the PATH or
+// fails.
+func formatFiles(files []*pluginpb.CodeGeneratorResponse_File, suffix string) {
+	path, err := exec.LookPath("elm-format")
+	if err != nil {
+		warnf("elm-format was not found, so generated files are not formatted")
+		return
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.GetName(), suffix) {
+			continue
+		}
+
+		cmd := exec.Command(path, "--stdin", "--elm-version=0.19")
+		cmd.Stdin = strings.NewReader(file.GetContent())
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			warnf("elm-format failed on %s, so it is not formatted: %v %s", file.GetName(), err, strings.TrimSpace(stderr.String()))
+			continue
+		}
+
+		content := string(out)
+		file.Content = &content
+	}
+}
+
+// reportDryRun logs the files that would have been generated, for the dry-run
+// parameter. The report is logged alongside warnings, so only log-level=error
+// hides it.
+func reportDryRun(files []*pluginpb.CodeGeneratorResponse_File) {
+	lines := 0
+	for _, file := range files {
+		n := strings.Count(file.GetContent(), "\n")
+		logf(warnLevel, "dry-run", "would generate %s (%d lines)", file.GetName(), n)
+		lines += n
+	}
+
+	logf(warnLevel, "dry-run", "%d files, %d lines would be generated", len(files), lines)
+}
+
+// generateFiles templates every non-excluded input file across a pool of
+// workers bounded by GOMAXPROCS.  The returned files are in the same order as
+// the input files.
+func generateFiles(inFiles []*descriptorpb.FileDescriptorProto, p parameters) ([]*pluginpb.CodeGeneratorResponse_File, error) {
+	var toGenerate []*descriptorpb.FileDescriptorProto
+	for _, inFile := range inFiles {
+		infof("Processing file %s", inFile.GetName())
 		// Well Known Types.
 		if excludedFiles[inFile.GetName()] {
-			log.Printf("Skipping well known type")
+			infof("Skipping excluded file %s", inFile.GetName())
 			continue
 		}
 
-		name := fileName(inFile.GetName())
-		content, err := templateFile(inFile, parameters)
+		toGenerate = append(toGenerate, inFile)
+	}
+
+	p, err := resolveFiles(inFiles, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(toGenerate) == 0 {
+		infof("No files to generate")
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(toGenerate) {
+		workers = len(toGenerate)
+	}
+
+	names := outputNames(toGenerate, p)
+	if err := checkDuplicateNames(toGenerate, names); err != nil {
+		return nil, err
+	}
+	if err := checkImportCycles(toGenerate, p.files); err != nil {
+		return nil, err
+	}
+
+	files := make([]*pluginpb.CodeGeneratorResponse_File, len(toGenerate))
+	errs := make([]error, len(toGenerate))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				inFile := toGenerate[i]
+				name := names[i]
+				content, err := templateFile(inFile, p)
+				if err != nil {
+					errs[i] = errors.Wrapf(err, "failed to template %s", inFile.GetName())
+					continue
+				}
+
+				debugf("Generated %s from %s", name, inFile.GetName())
+				files[i] = &pluginpb.CodeGeneratorResponse_File{
+					Name:    &name,
+					Content: &content,
+				}
+			}
+		}()
+	}
+
+	for i := range toGenerate {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.sharedHelpers != "" {
+		helpers, err := helpersFile(toGenerate, names, p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, helpers)
+	}
+
+	if p.registry != "" {
+		registry, err := registryFile(toGenerate, names, p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, registry)
+	}
+
+	if p.Dependencies {
+		deps, err := dependenciesFile(files)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, deps)
+	}
+
+	if p.Manifest {
+		manifest, err := manifestFile(toGenerate, names, p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, manifest)
+	}
+
+	return files, nil
+}
+
+// resolveFiles indexes every input file, generated or not, so that types
+// can be resolved across files.
+func resolveFiles(inFiles []*descriptorpb.FileDescriptorProto, p parameters) (parameters, error) {
+	p.files = map[string]*descriptorpb.FileDescriptorProto{}
+	p.modulePrefixes = map[string]string{}
+	for _, inFile := range inFiles {
+		if err := checkFileName(inFile); err != nil {
+			return p, err
+		}
+		normalizeEditions(inFile)
+		p.files[inFile.GetName()] = inFile
+		if value, ok := options.ModulePrefix(inFile.GetOptions()); ok {
+			prefix, err := normalizeModulePrefix(value)
+			if err != nil {
+				return p, errors.Wrapf(err, "invalid (elm.module_prefix) in %s", inFile.GetName())
+			}
+			p.modulePrefixes[inFile.GetName()] = prefix
+		}
+	}
+
+	p.enumModules = map[string]string{}
+	p.enums = map[string]enumDefinition{}
+	p.typeModules = map[string]string{}
+	for _, inFile := range inFiles {
+		addEnumModules(p.enumModules, inFile, p.moduleFor(inFile))
+		addEnumDefinitions(p.enums, inFile)
+		addTypeModules(p.typeModules, inFile, p.moduleFor(inFile))
+	}
+
+	if p.renameOption != "" {
+		number, err := fieldOptionNumber(inFiles, p.renameOption)
+		if err != nil {
+			return p, errors.Wrap(err, "invalid rename-option")
+		}
+		p.scope.RenameExtension = protowire.Number(number)
+	}
+
+	if len(p.includes) > 0 {
+		var err error
+		p.included, err = includedTypes(inFiles, p.includes)
+		if err != nil {
+			return p, err
+		}
+	}
+
+	return p, nil
+}
+
+// helpersFile generates the shared-helpers module, which defines the helper
+// functions that generated modules otherwise each define for themselves.
+// Helpers that depend on the contents of a file are included when any file
+// needs them.
+func helpersFile(inFiles []*descriptorpb.FileDescriptorProto, names []string, p parameters) (*pluginpb.CodeGeneratorResponse_File, error) {
+	name := strings.Replace(p.sharedHelpers, ".", "/", -1) + p.fileSuffix
+	if p.FlattenOutput {
+		name = strings.Replace(p.sharedHelpers, ".", "_", -1) + p.fileSuffix
+	}
+	for i, other := range names {
+		if other == name {
+			return nil, fmt.Errorf("shared-helpers module %s has the same file name as the module generated for %s", p.sharedHelpers, inFiles[i].GetName())
+		}
+	}
+
+	extensions := false
+	maps := false
+	for _, inFile := range inFiles {
+		if hasExtensionRanges(inFile.GetMessageType()) {
+			extensions = p.backend != elm.BinaryBackend
+		}
+		if hasMapEntries(inFile) {
+			maps = p.json == elm.BothFormats
+		}
+	}
+
+	t, err := compiledTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	buff := &bytes.Buffer{}
+	if err := t.ExecuteTemplate(buff, "helpers-module", struct {
+		Banner          []string
+		ModuleName      string
+		RuntimeModule   string
+		ImportDict      bool
+		ObjectMaps      bool
+		OmitDefaults    bool
+		Codecs          bool
+		Binary          bool
+		BothFormats     bool
+		Merge           bool
+		LenientLists    bool
+		MaybeOneofs     bool
+		StreamHelpers   bool
+		TimestampMillis bool
+		TimestampPosix  bool
+		Extensions      bool
+		Validators      bool
+	}{
+		Banner:          p.banner,
+		ModuleName:      p.sharedHelpers,
+		RuntimeModule:   p.runtimeModule,
+		ImportDict:      extensions || maps,
+		ObjectMaps:      maps,
+		OmitDefaults:    p.OmitDefaults,
+		Codecs:          p.backend == elm.CodecBackend,
+		Binary:          p.backend == elm.BinaryBackend,
+		BothFormats:     p.json == elm.BothFormats,
+		Merge:           p.Merge,
+		LenientLists:    p.LenientLists,
+		MaybeOneofs:     p.MaybeOneofs,
+		StreamHelpers:   p.StreamHelpers,
+		TimestampMillis: p.timestamp == "millis",
+		TimestampPosix:  p.timestamp == "posix",
+		Extensions:      extensions,
+		Validators:      p.Validators,
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to template shared-helpers module")
+	}
+
+	content := buff.String()
+	debugf("Generated %s with the shared helpers", name)
+	return &pluginpb.CodeGeneratorResponse_File{
+		Name:    &name,
+		Content: &content,
+	}, nil
+}
+
+type registryEntry struct {
+	Name    string
+	Decoder string
+	Encoder string
+}
+
+// registryFile generates the registry module, which maps the full proto name
+// of every generated message to a decoder of the message re-encoded as a
+// JE.Value, so that generic code can pick a decoder by name at runtime.
+func registryFile(inFiles []*descriptorpb.FileDescriptorProto, names []string, p parameters) (*pluginpb.CodeGeneratorResponse_File, error) {
+	name := strings.Replace(p.registry, ".", "/", -1) + p.fileSuffix
+	if p.FlattenOutput {
+		name = strings.Replace(p.registry, ".", "_", -1) + p.fileSuffix
+	}
+	for i, other := range names {
+		if other == name {
+			return nil, fmt.Errorf("registry module %s has the same file name as the module generated for %s", p.registry, inFiles[i].GetName())
+		}
+	}
+
+	var imports []string
+	var entries []registryEntry
+	for _, inFile := range inFiles {
+		p.module = p.moduleFor(inFile)
+		p.pkg = inFile.GetPackage()
+		p.scope.Qualified = collidingTypes(inFile, p)
+		pbMessages, err := messages([]string{}, inFile.GetMessageType(), p)
 		if err != nil {
-			log.Fatalf("Could not template file: %v", err)
+			return nil, errors.Wrapf(err, "invalid file %s", inFile.GetName())
+		}
+
+		fileEntries := registryEntries(pbMessages, p)
+		if len(fileEntries) > 0 {
+			imports = append(imports, p.module)
+			entries = append(entries, fileEntries...)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	t, err := compiledTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	buff := &bytes.Buffer{}
+	if err := t.ExecuteTemplate(buff, "registry-module", struct {
+		Banner     []string
+		ModuleName string
+		Codecs     bool
+		Imports    []string
+		Entries    []registryEntry
+	}{
+		Banner:     p.banner,
+		ModuleName: p.registry,
+		Codecs:     p.backend == elm.CodecBackend,
+		Imports:    imports,
+		Entries:    entries,
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to template registry module")
+	}
+
+	content := buff.String()
+	debugf("Generated %s with the registry of %d messages", name, len(entries))
+	return &pluginpb.CodeGeneratorResponse_File{
+		Name:    &name,
+		Content: &content,
+	}, nil
+}
+
+// registryEntries returns the decoder and encoder, qualified with p.module, of
+// each message and nested message.  Map entries are left out, since they are
+// not messages of their own.
+func registryEntries(pbMessages []pbMessage, p parameters) []registryEntry {
+	var result []registryEntry
+	for _, m := range pbMessages {
+		if !m.MapEntry {
+			entry := registryEntry{Name: strings.TrimPrefix(m.FullName, ".")}
+			switch {
+			case m.Wrapper != nil:
+				entry.Decoder = p.module + "." + string(m.Wrapper.Decoder)
+				entry.Encoder = p.module + "." + string(m.Wrapper.Encoder)
+			case m.TypeAlias.Codec != "":
+				entry.Decoder = fmt.Sprintf("(Codec.decoder %s.%s)", p.module, m.TypeAlias.Codec)
+				entry.Encoder = fmt.Sprintf("(Codec.encoder %s.%s)", p.module, m.TypeAlias.Codec)
+			default:
+				entry.Decoder = p.module + "." + string(m.TypeAlias.Decoder)
+				entry.Encoder = p.module + "." + string(m.TypeAlias.Encoder)
+			}
+			result = append(result, entry)
 		}
+		result = append(result, registryEntries(m.NestedMessages, p)...)
+	}
+
+	return result
+}
 
-		resp.File = append(resp.File, &pluginpb.CodeGeneratorResponse_File{
-			Name:    &name,
-			Content: &content,
+type manifestEntry struct {
+	Source string `json:"source"`
+	Module string `json:"module"`
+	Path   string `json:"path"`
+}
+
+// manifestFile lists the Elm module and path generated for each source proto,
+// so that build tools don't have to scan the output directory.
+func manifestFile(inFiles []*descriptorpb.FileDescriptorProto, names []string, p parameters) (*pluginpb.CodeGeneratorResponse_File, error) {
+	entries := []manifestEntry{}
+	for i, inFile := range inFiles {
+		entries = append(entries, manifestEntry{
+			Source: inFile.GetName(),
+			Module: p.moduleFor(inFile),
+			Path:   names[i],
 		})
 	}
 
-	data, err = proto.Marshal(resp)
+	content, err := json.MarshalIndent(struct {
+		Files []manifestEntry `json:"files"`
+	}{entries}, "", "  ")
 	if err != nil {
-		log.Fatalf("Could not marshal response: %v [%v]", err, resp)
+		return nil, errors.Wrap(err, "failed to encode manifest")
 	}
 
-	_, err = os.Stdout.Write(data)
+	return &pluginpb.CodeGeneratorResponse_File{
+		Name:    proto.String(manifestName),
+		Content: proto.String(string(content) + "\n"),
+	}, nil
+}
+
+// elmPackages maps the modules that generated code may import to the Elm
+// package providing them.  Modules that aren't listed are generated, or come
+// from the Elm project itself (e.g. a runtime-module or scalar-map module).
+var elmPackages = map[string]string{
+	"Array":                "elm/core",
+	"Dict":                 "elm/core",
+	"Json.Decode":          "elm/json",
+	"Json.Encode":          "elm/json",
+	"Json.Decode.Pipeline": "NoRedInk/elm-json-decode-pipeline",
+	"Time":                 "elm/time",
+	"ISO8601":              "jweir/elm-iso8601",
+	"Bytes":                "elm/bytes",
+	"Bytes.Decode":         "elm/bytes",
+	"Bytes.Encode":         "elm/bytes",
+	"Codec":                "miniBill/elm-codec",
+	"Protobuf.Decode":      "eriktim/elm-protocol-buffers",
+	"Protobuf.Encode":      "eriktim/elm-protocol-buffers",
+	defaultRuntimeModule:   "tiziano88/elm-protobuf",
+}
+
+// dependenciesFile lists the Elm packages that the generated files import
+// modules from, so that they can be kept in sync with elm.json.  elm/core is
+// always needed.
+func dependenciesFile(files []*pluginpb.CodeGeneratorResponse_File) (*pluginpb.CodeGeneratorResponse_File, error) {
+	packages := map[string]bool{"elm/core": true}
+	for _, file := range files {
+		for _, line := range strings.Split(file.GetContent(), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 || fields[0] != "import" {
+				continue
+			}
+			if pkg, ok := elmPackages[fields[1]]; ok {
+				packages[pkg] = true
+			}
+		}
+	}
+
+	dependencies := []string{}
+	for pkg := range packages {
+		dependencies = append(dependencies, pkg)
+	}
+	sort.Strings(dependencies)
+
+	content, err := json.MarshalIndent(struct {
+		Dependencies []string `json:"dependencies"`
+	}{dependencies}, "", "  ")
 	if err != nil {
-		log.Fatalf("Could not write response to STDOUT: %v", err)
+		return nil, errors.Wrap(err, "failed to encode dependencies")
 	}
+
+	return &pluginpb.CodeGeneratorResponse_File{
+		Name:    proto.String(dependenciesName),
+		Content: proto.String(string(content) + "\n"),
+	}, nil
 }
 
 func hasMapEntries(inFile *descriptorpb.FileDescriptorProto) bool {
@@ -151,6 +1367,17 @@ func hasMapEntries(inFile *descriptorpb.FileDescriptorProto) bool {
 	return false
 }
 
+// hasExtensionRanges reports whether any message of inFile can be extended.
+func hasExtensionRanges(messagePbs []*descriptorpb.DescriptorProto) bool {
+	for _, m := range messagePbs {
+		if len(m.GetExtensionRange()) > 0 || hasExtensionRanges(m.GetNestedType()) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func hasMapEntriesInMessage(inMessage *descriptorpb.DescriptorProto) bool {
 	if inMessage.GetOptions().GetMapEntry() {
 		return true
@@ -165,41 +1392,82 @@ func hasMapEntriesInMessage(inMessage *descriptorpb.DescriptorProto) bool {
 	return false
 }
 
-func templateFile(inFile *descriptorpb.FileDescriptorProto, p parameters) (string, error) {
+// hasValueLookups reports whether any enum is decoded through a Dict, for the
+// enum-dict parameter.
+func hasValueLookups(enums []elm.EnumCustomType, pbMessages []pbMessage) bool {
+	for _, enum := range enums {
+		if enum.ValueLookup != "" {
+			return true
+		}
+	}
+	for _, m := range pbMessages {
+		if hasValueLookups(m.EnumCustomTypes, m.NestedMessages) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	fileTemplateOnce sync.Once
+	fileTemplate     *template.Template
+	fileTemplateErr  error
+)
+
+// compiledTemplate parses the file template set once and returns a clone that
+// is safe to execute independently of any other file.
+func compiledTemplate() (*template.Template, error) {
+	fileTemplateOnce.Do(func() {
+		fileTemplate, fileTemplateErr = parseTemplates()
+	})
+	if fileTemplateErr != nil {
+		return nil, fileTemplateErr
+	}
+
+	return fileTemplate.Clone()
+}
+
+func parseTemplates() (*template.Template, error) {
 	t := template.New("t").Funcs(template.FuncMap{
-		"fieldSeq": func(from int, to elm.ProtobufFieldNumber) []int {
-			var l []int
-			for i := from; i < int(to); i++ {
-				l = append(l, i)
-			}
-			return l
-		},
-		"nextFieldNum": func(n elm.ProtobufFieldNumber) int {
-			return int(n) + 1
+		"padding": func(n int) []struct{} {
+			return make([]struct{}, n)
 		},
 		"toJSIdx": func(n elm.ProtobufFieldNumber) int {
 			return int(n) - 1
 		},
+		// argument parenthesizes an expression for use as a function argument.
+		"argument": func(expr string) string {
+			if strings.HasPrefix(expr, "-") || strings.Contains(expr, " ") && !strings.ContainsAny(expr[:1], `"[`) {
+				return "(" + expr + ")"
+			}
+			return expr
+		},
 	})
 
 	t, err := elm.EnumCustomTypeTemplate(t)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to parse enum custom type template")
+		return nil, errors.Wrap(err, "failed to parse enum custom type template")
 	}
 
 	t, err = elm.OneOfCustomTypeTemplate(t)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to parse one-of custom type template")
+		return nil, errors.Wrap(err, "failed to parse one-of custom type template")
 	}
 
 	t, err = elm.TypeAliasTemplate(t)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to parse type alias template")
+		return nil, errors.Wrap(err, "failed to parse type alias template")
+	}
+
+	t, err = elm.WrapperTypeTemplate(t)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse wrapper type template")
 	}
 
 	t, err = t.Parse(`
 {{- define "nested-message" -}}
-{{ template "type-alias" .TypeAlias }}
+{{ if .Wrapper }}{{ template "wrapper-type" .Wrapper }}{{ else }}{{ template "type-alias" .TypeAlias }}{{ end }}
 {{- range .OneOfCustomTypes }}
 
 
@@ -219,26 +1487,37 @@ func templateFile(inFile *descriptorpb.FileDescriptorProto, p parameters) (strin
 `)
 
 	if err != nil {
-		return "", errors.Wrap(err, "failed to parse nested PB message template")
+		return nil, errors.Wrap(err, "failed to parse nested PB message template")
 	}
 
-	t, err = t.Parse(`module {{ .ModuleName }} exposing (..)
+	t, err = t.Parse(`
+{{- define "helpers" -}}
+{{- if .Binary }}
 
--- DO NOT EDIT
--- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
--- https://github.com/tiziano88/elm-protobuf
--- source file: {{ .SourceFile }}
 
-import Protobuf exposing (..)
+bytesDecoder : Decode.Decoder Bytes
+bytesDecoder =
+    Decode.map bytesToList Decode.bytes
 
-import Json.Decode as JD
-import Json.Encode as JE
-{{- if .ImportDict }}
-import Dict
-{{- end }}
-{{- range .AdditionalImports }}
-import {{ . }} exposing (..)
-{{ end }}
+
+bytesEncoder : Bytes -> Encode.Encoder
+bytesEncoder v =
+    Encode.bytes (BE.encode (BE.sequence (List.map BE.unsignedInt8 v)))
+
+
+bytesToList : Bytes.Bytes -> Bytes
+bytesToList b =
+    let
+        step ( n, acc ) =
+            if n <= 0 then
+                BD.succeed (BD.Done (List.reverse acc))
+
+            else
+                BD.map (\x -> BD.Loop ( n - 1, x :: acc )) BD.unsignedInt8
+    in
+    BD.decode (BD.loop ( Bytes.width b, [] ) step) b
+        |> Maybe.withDefault []
+{{- else }}
 
 
 -- noop is here because I don't know elm well enough to know how to provide
@@ -258,19 +1537,276 @@ custom =
     JD.map2 (|>)
 
 
-idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
-idxWithDefault idx decoder default =
-    JD.map2 (|>) (JD.oneOf [ JD.index idx decoder, JD.succeed default ])
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+{{- if .Extensions }}
+
+
+{- extensionFields decodes the object that javascript keeps after the last
+field of messages with extensions, which maps extension field numbers to their
+values.
+-}
+extensionFields : JD.Decoder (Dict.Dict Int JD.Value)
+extensionFields =
+    JD.list JD.value
+        |> JD.map
+            (\values ->
+                case List.reverse values of
+                    last :: _ ->
+                        JD.decodeValue (JD.keyValuePairs JD.value) last
+                            |> Result.withDefault []
+                            |> List.filterMap (\( key, value ) -> Maybe.map (\n -> ( n, value )) (String.toInt key))
+                            |> Dict.fromList
+
+                    [] ->
+                        Dict.empty
+            )
+
+
+extensionObject : Dict.Dict Int JE.Value -> JE.Value
+extensionObject extensions =
+    if Dict.isEmpty extensions then
+        JE.null
+
+    else
+        JE.object (List.map (\( n, value ) -> ( String.fromInt n, value )) (Dict.toList extensions))
+{{- end }}
+{{- if .TimestampMillis }}
+
+
+timestampMillisDecoder : JD.Decoder Time.Posix
+timestampMillisDecoder =
+    JD.map Time.millisToPosix JD.int
+
+
+timestampMillisEncoder : Time.Posix -> JE.Value
+timestampMillisEncoder v =
+    JE.int (Time.posixToMillis v)
+{{- end }}
+{{- if .TimestampPosix }}
+
+
+timestampPosixDecoder : JD.Decoder Time.Posix
+timestampPosixDecoder =
+    JD.string
+        |> JD.andThen
+            (\v ->
+                case ISO8601.fromString v of
+                    Ok t ->
+                        JD.succeed (ISO8601.toPosix t)
+
+                    Err e ->
+                        JD.fail e
+            )
+
+
+timestampPosixEncoder : Time.Posix -> JE.Value
+timestampPosixEncoder v =
+    JE.string (ISO8601.toString (ISO8601.fromPosix v))
+{{- end }}
+{{- if .OmitDefaults }}
+
+
+{- omitWhen encodes null in place of default values.  The javascript
+message constructor treats empty slots in the backing array as unset,
+which keeps them out of the serialized message.
+-}
+omitWhen : (a -> Bool) -> (a -> JE.Value) -> a -> JE.Value
+omitWhen isDefault enc v =
+    if isDefault v then
+        JE.null
+
+    else
+        enc v
+{{- end }}
+{{- if .LenientLists }}
+
+
+{- lenientList decodes a list, or a single value as a list of one value, since
+some javascript serializers leave out the array for repeated fields with a
+single value.
+-}
+lenientList : JD.Decoder a -> JD.Decoder (List a)
+lenientList decoder =
+    JD.oneOf [ JD.list decoder, JD.map List.singleton decoder ]
+{{- end }}
+{{- if .StreamHelpers }}
+
+
+{- decodeStreamLine decodes one line of a newline-delimited JSON stream and
+adds it to the front of the values decoded so far, for folding over the lines
+of each chunk as it arrives.  Blank lines are skipped, and the first error is
+kept.
+-}
+decodeStreamLine : JD.Decoder a -> String -> Result JD.Error (List a) -> Result JD.Error (List a)
+decodeStreamLine decoder line decoded =
+    if String.isEmpty (String.trim line) then
+        decoded
+
+    else
+        decoded
+            |> Result.andThen (\values -> Result.map (\v -> v :: values) (JD.decodeString decoder line))
+
+
+{- decodeStream decodes every line of a newline-delimited JSON stream, in
+order.
+-}
+decodeStream : JD.Decoder a -> String -> Result JD.Error (List a)
+decodeStream decoder stream =
+    List.foldl (decodeStreamLine decoder) (Ok []) (String.lines stream)
+        |> Result.map List.reverse
+{{- end }}
+{{- if .MaybeOneofs }}
+
+
+{- maybeOneof decodes a oneof as Nothing when the array ends before the oneof's
+first field index, idx, rather than as its Unspecified variant.
+-}
+maybeOneof : Int -> JD.Decoder a -> JD.Decoder (Maybe a)
+maybeOneof idx decoder =
+    JD.list JD.value
+        |> JD.andThen
+            (\values ->
+                if List.length values > idx then
+                    JD.map Just decoder
+
+                else
+                    JD.succeed Nothing
+            )
+{{- if .BothFormats }}
+
+
+{- maybeOneofField decodes a oneof as Nothing when none of its fields' names
+are keys of the object.
+-}
+maybeOneofField : List String -> JD.Decoder a -> JD.Decoder (Maybe a)
+maybeOneofField names decoder =
+    JD.keyValuePairs JD.value
+        |> JD.andThen
+            (\pairs ->
+                if List.any (\( key, _ ) -> List.member key names) pairs then
+                    JD.map Just decoder
+
+                else
+                    JD.succeed Nothing
+            )
+{{- end }}
+{{- end }}
+{{- if .BothFormats }}
+
+
+{- arrayMessage and objectMessage only run a message decoder on a value of
+the matching shape.  Otherwise a decoder for one format would succeed on the
+other, with every field missing and so set to its default.
+-}
+arrayMessage : JD.Decoder a -> JD.Decoder a
+arrayMessage decoder =
+    JD.list JD.value |> JD.andThen (\_ -> decoder)
+
+
+objectMessage : JD.Decoder a -> JD.Decoder a
+objectMessage decoder =
+    JD.keyValuePairs JD.value |> JD.andThen (\_ -> decoder)
+
+
+{- fieldWithDefault is the object format counterpart of idxWithDefault.
+-}
+fieldWithDefault : String -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+fieldWithDefault name decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.field name (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeField is the object format counterpart of maybeIdx.
+-}
+maybeField : String -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeField name decoder =
+    JD.map2 (|>)
+        (JD.maybe (JD.field name JD.value)
+            |> JD.andThen
+                (\value ->
+                    case value of
+                        Just _ ->
+                            JD.field name (JD.nullable decoder)
+
+                        Nothing ->
+                            JD.succeed Nothing
+                )
+        )
+{{- if .ObjectMaps }}
+
+
+{- intKeyDict decodes a map field with integer keys from the object format,
+where keys are always strings.
+-}
+intKeyDict : JD.Decoder a -> JD.Decoder (Dict.Dict Int a)
+intKeyDict decoder =
+    JD.keyValuePairs decoder
+        |> JD.andThen
+            (\pairs ->
+                List.foldr
+                    (\( key, value ) result ->
+                        case String.toInt key of
+                            Just n ->
+                                JD.map (Dict.insert n value) result
+
+                            Nothing ->
+                                JD.fail ("invalid map key " ++ key)
+                    )
+                    (JD.succeed Dict.empty)
+                    pairs
+            )
+{{- end }}
+{{- end }}
+{{- if .Codecs }}
+
+
+intCodec : Codec Int
+intCodec =
+    Codec.build JE.int intDecoder
 
 
-maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
-maybeEncoder enc v =
-    case v of
-        Nothing ->
-            JE.null
+int64Codec : Codec Int
+int64Codec =
+    Codec.build numericStringEncoder intDecoder
 
-        Just av ->
-            enc av
+
+bytesCodec : Codec Bytes
+bytesCodec =
+    Codec.build bytesFieldEncoder bytesFieldDecoder
+{{- end }}
 
 
 type Field a
@@ -302,6 +1838,199 @@ failOnNull decoder =
           Present fv ->
             JD.succeed fv
       )
+{{- end }}
+{{- if .Validators }}
+
+
+{- validated collects the errors of the checks that failed.
+-}
+validated : a -> List ( Bool, String ) -> Result (List String) a
+validated value checks =
+    let
+        failed ( ok, error ) =
+            if ok then
+                Nothing
+
+            else
+                Just error
+    in
+    case List.filterMap failed checks of
+        [] ->
+            Ok value
+
+        errors ->
+            Err errors
+{{- end }}
+{{- if .Merge }}
+
+
+{- mergeField keeps the left value unless the right one differs from the
+field's default.
+-}
+mergeField : a -> a -> a -> a
+mergeField default left right =
+    if right == default then
+        left
+
+    else
+        right
+
+
+{- mergeMaybe keeps the left value unless the right one is set.  It doesn't
+compare the values, so it also merges the JD.Value of Struct, Value and
+ListValue fields, which (==) doesn't support.
+-}
+mergeMaybe : Maybe a -> Maybe a -> Maybe a
+mergeMaybe left right =
+    case right of
+        Just _ ->
+            right
+
+        Nothing ->
+            left
+
+
+{- mergeUnless keeps the left value if the right one is empty, like mergeMaybe
+for collections.
+-}
+mergeUnless : (a -> Bool) -> a -> a -> a
+mergeUnless isEmpty left right =
+    if isEmpty right then
+        left
+
+    else
+        right
+{{- end }}
+{{- end -}}
+
+{{- define "helpers-module" -}}
+module {{ .ModuleName }} exposing (..)
+{{ if .Banner }}
+{{- range .Banner }}
+-- {{ . }}
+{{- end }}
+{{ end }}
+import {{ .RuntimeModule }} exposing (..)
+
+{{ if .Binary -}}
+import Bytes
+import Bytes.Decode as BD
+import Bytes.Encode as BE
+import Protobuf.Decode as Decode
+import Protobuf.Encode as Encode
+{{- else -}}
+import Json.Decode as JD
+import Json.Encode as JE
+{{- end }}
+{{- if .Codecs }}
+import Codec exposing (Codec)
+{{- end }}
+{{- if or .TimestampMillis .TimestampPosix }}
+import Time
+{{- end }}
+{{- if .TimestampPosix }}
+import ISO8601
+{{- end }}
+{{- if .ImportDict }}
+import Dict
+{{- end }}
+{{- template "helpers" . }}
+{{ end -}}
+
+{{- define "registry-module" -}}
+module {{ .ModuleName }} exposing (messageDecoder, messageDecoders)
+{{ if .Banner }}
+{{- range .Banner }}
+-- {{ . }}
+{{- end }}
+{{ end }}
+{{- if .Codecs }}
+import Codec
+{{- end }}
+import Dict
+import Json.Decode as JD
+import Json.Encode as JE
+{{- range .Imports }}
+import {{ . }}
+{{- end }}
+
+
+{- messageDecoders maps the full proto name of each message to a decoder that
+checks that a value is the message, returning it re-encoded.
+-}
+messageDecoders : Dict.Dict String (JD.Decoder JE.Value)
+messageDecoders =
+    Dict.fromList
+{{- range $i, $v := .Entries }}
+        {{ if $i }},{{ else }}[{{ end }} ( "{{ .Name }}", JD.map {{ .Encoder }} {{ .Decoder }} )
+{{- end }}
+{{- if .Entries }}
+        ]
+{{- else }}
+        []
+{{- end }}
+
+
+{- messageDecoder looks up the decoder of a message by its full proto name,
+e.g. "pkg.Foo".
+-}
+messageDecoder : String -> Maybe (JD.Decoder JE.Value)
+messageDecoder name =
+    Dict.get name messageDecoders
+{{ end -}}
+`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse helpers template")
+	}
+
+	t, err = t.Parse(`module {{ .ModuleName }} exposing (..)
+{{ if .Banner }}
+{{- range .Banner }}
+-- {{ . }}
+{{- end }}
+-- source file: {{ .SourceFile }}
+{{ end }}
+import {{ .RuntimeModule }} exposing (..)
+
+{{ if .Binary -}}
+import Bytes
+import Bytes.Decode as BD
+import Bytes.Encode as BE
+import Protobuf.Decode as Decode
+import Protobuf.Encode as Encode
+{{- else -}}
+import Json.Decode as JD
+import Json.Encode as JE
+{{- end }}
+{{- if .Pipeline }}
+import Json.Decode.Pipeline as Pipeline
+{{- end }}
+{{- if .Codecs }}
+import Codec exposing (Codec)
+{{- end }}
+{{- if or .TimestampMillis .TimestampPosix }}
+import Time
+{{- end }}
+{{- if and .TimestampPosix (not .SharedHelpers) }}
+import ISO8601
+{{- end }}
+{{- if .ImportArray }}
+import Array
+{{- end }}
+{{- range .ScalarImports }}
+import {{ . }}
+{{- end }}
+{{- if .ImportDict }}
+import Dict
+{{- end }}
+{{- range .AdditionalImports }}
+import {{ . }} exposing (..)
+{{ end }}
+{{- if .SharedHelpers }}
+import {{ .SharedHelpers }} exposing (..)
+{{- else }}
+{{- template "helpers" . }}
+{{- end }}
 
 
 {{- range .TopEnums }}
@@ -315,33 +2044,231 @@ failOnNull decoder =
 {{ template "nested-message" . }}
 {{- end }}
 `)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse file template")
+	}
+
+	return t, nil
+}
+
+func templateFile(inFile *descriptorpb.FileDescriptorProto, p parameters) (string, error) {
+	p.module = p.moduleFor(inFile)
+	p.pkg = inFile.GetPackage()
+	p.scope.Qualified = collidingTypes(inFile, p)
+
+	t, err := compiledTemplate()
 	if err != nil {
 		return "", err
 	}
 
-	buff := &bytes.Buffer{}
-	if err = t.Execute(buff, struct {
+	pbMessages, err := messages([]string{}, inFile.GetMessageType(), p)
+	if err != nil {
+		return "", err
+	}
+
+	topEnums := enumsToCustomTypes([]string{}, inFile.GetEnumType(), p)
+	if err := checkTypeNames(topEnums, pbMessages); err != nil {
+		return "", errors.Wrapf(err, "invalid file %s", inFile.GetName())
+	}
+
+	extensions := p.backend != elm.BinaryBackend && hasExtensionRanges(inFile.GetMessageType())
+
+	data := struct {
 		SourceFile        string
+		Banner            []string
 		ModuleName        string
+		RuntimeModule     string
 		ImportDict        bool
+		ObjectMaps        bool
+		ImportArray       bool
+		OmitDefaults      bool
+		Codecs            bool
+		Binary            bool
+		BothFormats       bool
+		Pipeline          bool
+		Merge             bool
+		LenientLists      bool
+		MaybeOneofs       bool
+		StreamHelpers     bool
+		TimestampMillis   bool
+		TimestampPosix    bool
+		Extensions        bool
+		Validators        bool
+		SharedHelpers     string
 		AdditionalImports []string
+		ScalarImports     []string
 		TopEnums          []elm.EnumCustomType
 		Messages          []pbMessage
 	}{
 		SourceFile:        inFile.GetName(),
-		ModuleName:        moduleName(p.modPrefix, inFile.GetName()),
-		ImportDict:        hasMapEntries(inFile),
-		AdditionalImports: additionalImports(p.modPrefix, inFile.GetDependency()),
-		TopEnums:          enumsToCustomTypes([]string{}, inFile.GetEnumType(), p),
-		Messages:          messages([]string{}, inFile.GetMessageType(), p),
-	}); err != nil {
+		Banner:            p.banner,
+		ModuleName:        p.module,
+		RuntimeModule:     p.runtimeModule,
+		ImportDict:        hasMapEntries(inFile) || extensions || hasValueLookups(topEnums, pbMessages),
+		ObjectMaps:        hasMapEntries(inFile) && p.json == elm.BothFormats,
+		ImportArray:       p.repeated == elm.ArrayRepeated,
+		OmitDefaults:      p.OmitDefaults,
+		Codecs:            p.backend == elm.CodecBackend,
+		Binary:            p.backend == elm.BinaryBackend,
+		BothFormats:       p.json == elm.BothFormats,
+		Pipeline:          p.decoderStyle == elm.PipelineStyle,
+		Merge:             p.Merge,
+		LenientLists:      p.LenientLists,
+		MaybeOneofs:       p.MaybeOneofs,
+		StreamHelpers:     p.StreamHelpers,
+		TimestampMillis:   p.timestamp == "millis",
+		TimestampPosix:    p.timestamp == "posix",
+		Extensions:        extensions,
+		Validators:        p.Validators,
+		SharedHelpers:     p.sharedHelpers,
+		AdditionalImports: additionalImports(dependencies(inFile, p.files), p),
+		ScalarImports:     elm.ScalarTypeImports(p.scope),
+		TopEnums:          topEnums,
+		Messages:          pbMessages,
+	}
+
+	buff := &bytes.Buffer{}
+	if err = t.Execute(buff, data); err != nil {
 		return "", err
 	}
+	content := buff.String()
+
+	if p.ExplicitExposing {
+		// Helpers defined in the file are left out, which is simpler than
+		// keeping a list of them in sync with the helpers template.
+		helpers := &bytes.Buffer{}
+		if data.SharedHelpers == "" {
+			if err = t.ExecuteTemplate(helpers, "helpers", data); err != nil {
+				return "", err
+			}
+		}
+		content = exposeDefinitions(content, definitionNames(helpers.String()))
+	}
+
+	return content, nil
+}
+
+// exposeDefinitions replaces the exposing (..) of a generated module with the
+// list of its top-level definitions, less the helpers.  Modules that would
+// expose nothing keep exposing (..), since Elm requires the list to be
+// non-empty.
+func exposeDefinitions(content string, helpers []string) string {
+	skip := map[string]bool{}
+	for _, name := range helpers {
+		skip[name] = true
+	}
+
+	var exposed []string
+	for _, name := range definitionNames(content) {
+		if !skip[name] {
+			exposed = append(exposed, name)
+		}
+	}
+	if len(exposed) == 0 {
+		return content
+	}
+
+	return strings.Replace(content, "exposing (..)", "exposing\n    ( "+strings.Join(exposed, "\n    , ")+"\n    )", 1)
+}
+
+// definitionNames returns the top-level types and annotated values of Elm
+// code, in order.  Custom types are exposed with their constructors.
+func definitionNames(content string) []string {
+	var result []string
+	comment := false
+	for _, line := range strings.Split(content, "\n") {
+		if comment {
+			comment = !strings.Contains(line, "-}")
+			continue
+		}
+		if strings.HasPrefix(line, "{-") {
+			comment = !strings.Contains(line, "-}")
+			continue
+		}
+
+		if line == "" || !unicode.IsLower(rune(line[0])) {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 3 && fields[0] == "type" && fields[1] == "alias":
+			result = append(result, fields[2])
+		case len(fields) >= 2 && fields[0] == "type":
+			result = append(result, fields[1]+"(..)")
+		case len(fields) >= 2 && fields[1] == ":":
+			result = append(result, fields[0])
+		}
+	}
+
+	return result
+}
+
+// checkTypeNames returns an error when two definitions of a file generate the
+// same Elm type or constructor name.  Nested names are flattened with
+// underscores, so a top-level Foo_Bar collides with Bar nested in Foo.
+// Record type aliases define a constructor with the same name as the type.
+func checkTypeNames(topEnums []elm.EnumCustomType, pbMessages []pbMessage) error {
+	types := map[string]string{}
+	constructors := map[string]string{}
+	var collisions []string
+	add := func(names map[string]string, name string, kind string) {
+		if other, ok := names[name]; ok {
+			collisions = append(collisions, fmt.Sprintf("%s (%s and %s)", name, other, kind))
+			return
+		}
+		names[name] = kind
+	}
+
+	addEnums := func(enums []elm.EnumCustomType) {
+		for _, enum := range enums {
+			add(types, string(enum.Name), "enum")
+			for _, variant := range enum.Variants {
+				add(constructors, string(variant.Name), "variant of enum "+string(enum.Name))
+			}
+			if enum.Unrecognized != "" {
+				add(constructors, string(enum.Unrecognized), "variant of enum "+string(enum.Name))
+			}
+		}
+	}
+
+	var addMessages func(pbMessages []pbMessage)
+	addMessages = func(pbMessages []pbMessage) {
+		for _, m := range pbMessages {
+			if m.Wrapper != nil {
+				add(types, string(m.Wrapper.Name), "message")
+				add(constructors, string(m.Wrapper.Name), "message")
+			} else {
+				add(types, string(m.TypeAlias.Name), "message")
+				add(constructors, string(m.TypeAlias.Name), "message")
+			}
+			for _, oneof := range m.OneOfCustomTypes {
+				add(types, string(oneof.Name), "oneof")
+				add(constructors, string(oneof.Name)+"Unspecified", "variant of oneof "+string(oneof.Name))
+				for _, variant := range oneof.Variants {
+					add(constructors, string(variant.Name), "variant of oneof "+string(oneof.Name))
+				}
+			}
+			addEnums(m.EnumCustomTypes)
+			addMessages(m.NestedMessages)
+		}
+	}
+
+	addEnums(topEnums)
+	addMessages(pbMessages)
+	if len(collisions) > 0 {
+		return fmt.Errorf("definitions generate the same Elm names: %s", strings.Join(collisions, "; "))
+	}
 
-	return buff.String(), nil
+	return nil
 }
 
 type pbMessage struct {
+	// FullName is the fully qualified PB name, e.g. .pkg.Foo.Bar.
+	FullName string
+	MapEntry bool
+	// Wrapper replaces TypeAlias for messages selected by wrap-type.
+	Wrapper          *elm.WrapperType
 	TypeAlias        elm.TypeAlias
 	OneOfCustomTypes []elm.OneOfCustomType
 	EnumCustomTypes  []elm.EnumCustomType
@@ -370,30 +2297,140 @@ func enumsToCustomTypes(preface []string, enumPbs []*descriptorpb.EnumDescriptor
 			continue
 		}
 
-		var values []elm.EnumVariant
-		for _, value := range enumPb.GetValue() {
-			if isDeprecated(value.Options) && p.RemoveDeprecated {
-				continue
-			}
-
-			values = append(values, elm.EnumVariant{
-				Name:  elm.NestedVariantName(value.GetName(), preface),
-				Value: elm.ProtobufFieldNumber(value.GetNumber()),
-			})
+		if !p.isIncluded(preface, enumPb.GetName()) {
+			continue
 		}
 
-		enumType := elm.NestedType(enumPb.GetName(), preface)
+		enumType := elm.NestedType(enumPb.GetName(), preface, p.scope)
+
+		values := enumVariants(enumPb, enumType, preface, true, p)
+
+		debugf("Enum %s: %d values", enumType, len(values))
 
-		result = append(result, elm.EnumCustomType{
+		customType := elm.EnumCustomType{
 			Name:                   enumType,
-			Decoder:                elm.DecoderName(enumType),
-			Encoder:                elm.EncoderName(enumType),
+			Decoder:                elm.DecoderName(enumType, p.scope),
+			Encoder:                elm.EncoderName(enumType, p.scope),
 			DefaultVariantVariable: elm.EnumDefaultVariantVariableName(enumType),
-			DefaultVariantValue:    values[0].Name,
+			DefaultVariantValue:    defaultVariant(values, p),
 			Variants:               values,
+			Backend:                p.backend,
+		}
+		if p.KeepUnknownEnums {
+			customType.Unrecognized = elm.UnrecognizedVariantName(enumType)
+		}
+		if p.backend == elm.CodecBackend {
+			customType.Codec = elm.CodecName(enumType, p.scope)
+		}
+		if p.EnumStrings {
+			customType.ToString = elm.EnumToStringName(enumType)
+			customType.FromString = elm.EnumFromStringName(enumType)
+		}
+		if p.enumDict > 0 && len(values) >= p.enumDict {
+			customType.ValueLookup = elm.EnumValueLookupName(enumType)
+		}
+		result = append(result, customType)
+	}
+
+	return result
+}
+
+// enumVariants returns the variants generated for the values of an enum,
+// warning about values that are renamed to tell them apart when warn is set.
+func enumVariants(enumPb *descriptorpb.EnumDescriptorProto, enumType elm.Type, preface []string, warn bool, p parameters) []elm.EnumVariant {
+	var values []elm.EnumVariant
+	seen := map[elm.VariantName]string{}
+	for _, value := range enumPb.GetValue() {
+		if isDeprecated(value.Options) && p.RemoveDeprecated {
+			continue
+		}
+
+		valueName := value.GetName()
+		if p.StripEnumPrefix {
+			valueName = stripEnumPrefix(enumPb.GetName(), valueName)
+		}
+
+		// Variant names are normalized, so values whose names differ only in
+		// case or underscores (e.g. FOO_BAR and FOO_Bar) end up with the same
+		// name.  Later ones are told apart by their number.
+		name := elm.NestedVariantName(valueName, preface, p.scope)
+		if other, ok := seen[name]; ok {
+			suffix := "_" + strings.Replace(strconv.Itoa(int(value.GetNumber())), "-", "Neg", 1)
+			for ok {
+				name += elm.VariantName(suffix)
+				_, ok = seen[name]
+			}
+			if warn {
+				warnf("enum %s values %s and %s have the same Elm variant name, so %s is generated as %s", enumType, other, value.GetName(), value.GetName(), name)
+			}
+		}
+		seen[name] = value.GetName()
+
+		values = append(values, elm.EnumVariant{
+			Name:      name,
+			Value:     elm.ProtobufFieldNumber(value.GetNumber()),
+			ProtoName: value.GetName(),
 		})
 	}
 
+	return values
+}
+
+// defaultVariant is the variant enum fields default to.  proto2 enums may
+// declare a non-zero value first, which is their default, so with
+// enum-default=zero the zero-valued variant is used instead when there is
+// one.
+func defaultVariant(values []elm.EnumVariant, p parameters) elm.VariantName {
+	if p.enumDefault == "zero" {
+		for _, value := range values {
+			if value.Value == 0 {
+				return value.Name
+			}
+		}
+	}
+
+	return values[0].Name
+}
+
+// stripEnumPrefix removes the SCREAMING_SNAKE_CASE form of the enum name from
+// the start of a value name (e.g. COLOR_RED in enum Color becomes RED).  The
+// value name is left alone if stripping would not leave a valid identifier.
+func stripEnumPrefix(enumName, valueName string) string {
+	prefix := stringextras.ScreamingSnakeCase(enumName) + "_"
+	if !strings.HasPrefix(strings.ToUpper(valueName), prefix) {
+		return valueName
+	}
+
+	stripped := valueName[len(prefix):]
+	if stripped == "" || !unicode.IsLetter(rune(stripped[0])) {
+		return valueName
+	}
+
+	return stripped
+}
+
+// oneofFields returns the fields of the oneof at oneofIndex, sorted by field
+// number.  The decoder tries each variant in order and the first one that
+// succeeds wins, so sorting gives a stable precedence that doesn't depend on
+// declaration order.
+func oneofFields(messagePb *descriptorpb.DescriptorProto, oneofIndex int, p parameters) []*descriptorpb.FieldDescriptorProto {
+	var result []*descriptorpb.FieldDescriptorProto
+	for _, inField := range messagePb.GetField() {
+		if isDeprecated(inField.Options) && p.RemoveDeprecated {
+			continue
+		}
+
+		if inField.OneofIndex == nil || inField.GetOneofIndex() != int32(oneofIndex) {
+			continue
+		}
+
+		result = append(result, inField)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].GetNumber() < result[j].GetNumber()
+	})
+
 	return result
 }
 
@@ -405,99 +2442,333 @@ func oneOfsToCustomTypes(preface []string, messagePb *descriptorpb.DescriptorPro
 	}
 
 	for oneofIndex, oneOfPb := range messagePb.GetOneofDecl() {
+		if isSyntheticOneof(messagePb, oneofIndex) {
+			continue
+		}
+
 		var variants []elm.OneOfVariant
-		for _, inField := range messagePb.GetField() {
-			if isDeprecated(inField.Options) && p.RemoveDeprecated {
-				continue
+		for _, inField := range oneofFields(messagePb, oneofIndex, p) {
+			variant := elm.OneOfVariant{
+				Name:     elm.NestedVariantName(inField.GetName(), preface, p.scope),
+				Type:     elm.BasicFieldType(inField, p.scope),
+				Num:      elm.ProtobufFieldNumber(inField.GetNumber()),
+				Decoder:  elm.BasicFieldDecoder(inField, p.scope),
+				Encoder:  elm.BasicFieldEncoder(inField, p.scope),
+				JSONName: elm.JSONName(inField),
 			}
-
-			if inField.OneofIndex == nil || inField.GetOneofIndex() != int32(oneofIndex) {
-				continue
+			if p.OneofAccessors {
+				variant.Getter = elm.OneOfGetterName(variant.Name)
+				variant.Mapper = elm.OneOfMapperName(variant.Name)
 			}
-
-			variants = append(variants, elm.OneOfVariant{
-				Name:    elm.NestedVariantName(inField.GetName(), preface),
-				Type:    elm.BasicFieldType(inField),
-				Num:     elm.ProtobufFieldNumber(inField.GetNumber()),
-				Decoder: elm.BasicFieldDecoder(inField),
-				Encoder: elm.BasicFieldEncoder(inField),
-			})
+			variants = append(variants, variant)
 		}
 
-		name := elm.NestedType(oneOfPb.GetName(), preface)
-		result = append(result, elm.OneOfCustomType{
+		name := elm.NestedType(oneOfPb.GetName(), preface, p.scope)
+		customType := elm.OneOfCustomType{
 			Name:     name,
-			Decoder:  elm.DecoderName(name),
-			Encoder:  elm.EncoderName(name),
+			Decoder:  elm.DecoderName(name, p.scope),
+			Encoder:  elm.EncoderName(name, p.scope),
 			Variants: variants,
-		})
+			Backend:  p.backend,
+		}
+		if p.json == elm.BothFormats {
+			customType.ObjectDecoder = elm.ObjectDecoderName(name, p.scope)
+		}
+		if p.jsonEncoder == elm.ObjectFormat {
+			customType.ObjectEncoder = elm.ObjectEncoderName(name, p.scope)
+		}
+		result = append(result, customType)
 	}
 
 	return result
 }
 
-func fieldDefault(field *descriptorpb.FieldDescriptorProto) string {
+// zeroValue is the Elm zero value of a field.  Defaults of enums defined in
+// other modules are qualified so that they resolve even when they are only
+// reachable through a public import, or when another import exposes a
+// definition with the same name.
+func zeroValue(field *descriptorpb.FieldDescriptorProto, p parameters) string {
+	zero := elm.BasicFieldDefaultValue(field, p.scope)
+	if elm.IsWellKnownType(field.GetTypeName(), p.scope) {
+		return zero
+	}
+	if _, ok := p.scope.Qualified[field.GetTypeName()]; ok {
+		return zero
+	}
+	if module, ok := p.enumModules[field.GetTypeName()]; ok && module != p.module {
+		return module + "." + zero
+	}
+
+	return zero
+}
+
+// fieldDefault is the value of a field in its message's default record: its
+// (elm.default), its proto2 default or its zero value.
+func fieldDefault(field *descriptorpb.FieldDescriptorProto, p parameters) (string, error) {
+	_, mapped := p.scope.ScalarTypes[field.GetType()]
+	if defV, ok := options.Default(field.GetOptions()); ok {
+		if mapped {
+			return "", fmt.Errorf("(elm.default) \"%s\" can't be converted to the type chosen by scalar-map", defV)
+		}
+		value, err := defaultLiteral(field, defV, p)
+		if err != nil {
+			return "", fmt.Errorf("(elm.default) %v", err)
+		}
+		return value, nil
+	}
+
 	defV := field.GetDefaultValue()
+	// Custom defaults can't be converted to the types chosen by scalar-map.
+	if defV == "" || mapped {
+		return zeroValue(field, p), nil
+	}
+
+	return defaultLiteral(field, defV, p)
+}
 
+// defaultLiteral converts a default value written the way protoc expects
+// proto2 defaults (e.g. `hello`, `true`, `-5` or `COLOR_RED`) to the Elm value
+// of the field's type.
+func defaultLiteral(field *descriptorpb.FieldDescriptorProto, defV string, p parameters) (string, error) {
+	invalid := fmt.Errorf("\"%s\" is not a valid %s", defV, strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_")))
 	switch field.GetType() {
 	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
-		defV = fmt.Sprintf(`"%s"`, strings.Replace(defV, `"`, `\"`, -1))
+		return elm.StringLiteral(defV), nil
 	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
 		// Elm uses 'False' and 'True' but golang libraries will decode
 		// these as 'false' and 'true'.
-		defV = strings.ToUpper(defV[:1])+defV[1:]
+		switch defV {
+		case "true":
+			return "True", nil
+		case "false":
+			return "False", nil
+		}
+		return "", invalid
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		// protoc C-escapes bytes defaults, but Elm represents bytes as a
+		// list of ints.
+		var values []string
+		for _, b := range stringextras.CUnescape(defV) {
+			values = append(values, strconv.Itoa(int(b)))
+		}
+		return fmt.Sprintf("[ %s ]", strings.Join(values, ", ")), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+		descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		f, err := strconv.ParseFloat(defV, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return "", invalid
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		n, err := strconv.ParseInt(defV, 10, intBits(field))
+		if err != nil {
+			return "", invalid
+		}
+		return intLiteral(field, strconv.FormatInt(n, 10)), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64:
+		n, err := strconv.ParseUint(defV, 10, intBits(field))
+		if err != nil {
+			return "", invalid
+		}
+		return intLiteral(field, strconv.FormatUint(n, 10)), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return enumDefault(field, defV, p)
+	default:
+		return "", fmt.Errorf("\"%s\" can't be used as the default of a %s field", defV, strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_")))
+	}
+}
+
+// intBits is the size of a PB integer type in bits.
+func intBits(field *descriptorpb.FieldDescriptorProto) int {
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return 64
 	default:
+		return 32
+	}
+}
+
+// intLiteral is the Elm value of an integer field written as the decimal n,
+// which is a String for 64 bit integers marked with [jstype = JS_STRING].
+func intLiteral(field *descriptorpb.FieldDescriptorProto, n string) string {
+	if elm.IsJSString(field) {
+		return elm.StringLiteral(n)
+	}
+
+	return n
+}
+
+// enumDefault is the variant of an enum field's type for the value named
+// valueName, qualified when the enum is generated in another module.
+func enumDefault(field *descriptorpb.FieldDescriptorProto, valueName string, p parameters) (string, error) {
+	enum, ok := p.enums[field.GetTypeName()]
+	if !ok || elm.IsWellKnownType(field.GetTypeName(), p.scope) {
+		return "", fmt.Errorf("\"%s\" can't be used as the default of a %s field", valueName, field.GetTypeName())
 	}
-	if defV == "" {
-		return elm.BasicFieldDefaultValue(field)
+
+	enumType := elm.NestedType(enum.pb.GetName(), enum.preface, p.scope)
+	for _, variant := range enumVariants(enum.pb, enumType, enum.preface, false, p) {
+		if variant.ProtoName != valueName {
+			continue
+		}
+		if module, ok := p.enumModules[field.GetTypeName()]; ok && module != p.module {
+			return module + "." + string(variant.Name), nil
+		}
+		return string(variant.Name), nil
 	}
-	return defV
+
+	return "", fmt.Errorf("\"%s\" is not a value of enum %s", valueName, strings.TrimPrefix(field.GetTypeName(), "."))
 }
 
-func messages(preface []string, messagePbs []*descriptorpb.DescriptorProto, p parameters) []pbMessage {
+func messages(preface []string, messagePbs []*descriptorpb.DescriptorProto, p parameters) ([]pbMessage, error) {
 	var result []pbMessage
 	for _, messagePb := range messagePbs {
 		if isDeprecated(messagePb.Options) && p.RemoveDeprecated {
 			continue
 		}
-
-		name := elm.NestedType(messagePb.GetName(), preface)
-		nestedPreface := append([]string{messagePb.GetName()}, preface...)
+
+		if !p.isIncluded(preface, messagePb.GetName()) {
+			continue
+		}
+
+		name := elm.NestedType(messagePb.GetName(), preface, p.scope)
+		nestedPreface := append(append([]string(nil), preface...), messagePb.GetName())
+
+		if wrapName, ok := p.wrapTypes[fullTypeName(p.pkg, nestedPreface)]; ok {
+			wrapper, err := wrapperType(wrapName, messagePb, p)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid wrap-type for %s", name)
+			}
+
+			nestedMessages, err := messages(nestedPreface, messagePb.GetNestedType(), p)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, pbMessage{
+				FullName:        fullTypeName(p.pkg, nestedPreface),
+				Wrapper:         &wrapper,
+				EnumCustomTypes: enumsToCustomTypes(nestedPreface, messagePb.GetEnumType(), p),
+				NestedMessages:  nestedMessages,
+			})
+			continue
+		}
+		debugf("Message %s: %d fields, %d oneofs, %d nested messages, %d nested enums",
+			name,
+			len(messagePb.GetField()),
+			len(messagePb.GetOneofDecl()),
+			len(messagePb.GetNestedType()),
+			len(messagePb.GetEnumType()),
+		)
 		alias := elm.TypeAlias{
-			Name:    name,
-			Decoder: elm.DecoderName(name),
-			Encoder: elm.EncoderName(name),
+			Name:          name,
+			Decoder:       elm.DecoderName(name, p.scope),
+			Encoder:       elm.EncoderName(name, p.scope),
+			Backend:       p.backend,
+			StringHelpers: p.StringHelpers,
+			Merge:         p.Merge,
+		}
+		if p.backend == elm.CodecBackend {
+			alias.Codec = elm.CodecName(name, p.scope)
+		}
+		if p.ArrayHelpers {
+			alias.ToArray = elm.ToArrayName(name)
+			alias.FromArray = elm.FromArrayName(name)
 		}
+		if p.StreamHelpers {
+			alias.ListDecoder = elm.ListDecoderName(name, p.scope)
+		}
+		alias.Pipeline = p.decoderStyle == elm.PipelineStyle
+		alias.ObjectDecoders = p.json == elm.BothFormats
+		alias.ObjectEncoding = p.jsonEncoder == elm.ObjectFormat
 
 		for _, fieldPb := range messagePb.GetField() {
 			if isDeprecated(fieldPb.Options) && p.RemoveDeprecated {
 				continue
 			}
 
-			if fieldPb.OneofIndex != nil {
+			if p.backend == elm.BinaryBackend {
+				if err := elm.BinarySupported(fieldPb, p.scope); err != nil {
+					return nil, errors.Wrapf(err, "invalid field %s.%s", name, fieldPb.GetName())
+				}
+			}
+
+			if fieldName, ok := options.FieldName(fieldPb.GetOptions()); ok && !isVariableName(fieldName) {
+				return nil, fmt.Errorf("invalid field %s.%s: (elm.field_name) \"%s\" is not a lowercase Elm name", name, fieldPb.GetName(), fieldName)
+			}
+
+			if isOneofVariant(fieldPb) {
 				// For encoding, we need one encoder for each variant in
 				// the oneof, but for decoding, we only want one decoder
 				// for the whole oneof.
 				oneof := messagePb.GetOneofDecl()[fieldPb.GetOneofIndex()]
-				typeName := elm.OneOfType(elm.NestedType(oneof.GetName(), nestedPreface))
-				alias.FieldEncoders = append(alias.FieldEncoders, elm.TypeAliasField{
-					Name:    elm.FieldName(oneof.GetName()),
-					Type:    typeName,
-					Number:  elm.ProtobufFieldNumber(fieldPb.GetNumber()),
-					Encoder: elm.OneOfEncoder(oneof, fieldPb, typeName),
-				})
+				// protoc rejects these, but descriptors can come from elsewhere.
+				if isRepeated(fieldPb) {
+					return nil, fmt.Errorf("invalid field %s.%s: members of oneof %s can't be repeated", name, fieldPb.GetName(), oneof.GetName())
+				}
+				typeName := elm.OneOfType(elm.NestedType(oneof.GetName(), nestedPreface, p.scope))
+				debugf("  Field %s is a variant of oneof %s", fieldPb.GetName(), typeName)
+				field := elm.TypeAliasField{
+					Name:          elm.FieldName(oneof.GetName()),
+					Type:          typeName,
+					Number:        elm.ProtobufFieldNumber(fieldPb.GetNumber()),
+					Encoder:       elm.OneOfEncoder(oneof, fieldPb, typeName, p.scope),
+					ObjectEncoder: elm.ObjectOneOfEncoder(oneof, typeName, p.scope),
+				}
+				if p.MaybeOneofs {
+					field.Encoder = elm.MaybeOneOfEncoder(oneof, fieldPb, typeName, p.scope)
+					field.ObjectEncoder = elm.ObjectMaybeOneOfEncoder(oneof, typeName, p.scope)
+				}
+				alias.FieldEncoders = append(alias.FieldEncoders, field)
 				continue
 			}
 
+			if wkt, ok := elm.WellKnownTypeFor(fieldPb.GetTypeName(), p.scope); ok {
+				debugf("  Field %s uses well known type %s as %s", fieldPb.GetName(), fieldPb.GetTypeName(), wkt.Type)
+			}
+
+			// Map fields are repeated fields of a synthetic map entry message,
+			// so they have to be told apart before the isRepeated check below
+			// treats them as lists of entries.
 			nested := getNestedType(fieldPb, messagePb)
 			if nested != nil {
+				debugf("  Field %s is a map of %s", fieldPb.GetName(), nested.GetName())
+				if !isRepeated(fieldPb) {
+					return nil, fmt.Errorf("invalid map field %s.%s: map entry %s can only be used by repeated fields", name, fieldPb.GetName(), nested.GetName())
+				}
+				mapType, err := elm.MapType(nested, p.scope)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid map field %s.%s", name, fieldPb.GetName())
+				}
+
 				field := elm.TypeAliasField{
-					Name:    elm.FieldName(fieldPb.GetName()),
-					Type:    elm.MapType(nested),
-					Number:  elm.ProtobufFieldNumber(fieldPb.GetNumber()),
-					Default: "Nothing",
-					Encoder: elm.MapEncoder(fieldPb, nested),
-					Decoder: elm.MapDecoder(fieldPb, nested),
+					Name:       elm.RecordFieldName(fieldPb, p.scope),
+					Type:       mapType,
+					Number:     elm.ProtobufFieldNumber(fieldPb.GetNumber()),
+					Default:    "Dict.empty",
+					Encoder:    elm.MapEncoder(fieldPb, nested, p.scope),
+					Decoder:    elm.MapDecoder(fieldPb, nested, p.scope),
+					Deprecated: isDeprecated(fieldPb.Options),
+				}
+				if p.OmitDefaults {
+					field.Encoder = elm.MapOmitEmptyEncoder(fieldPb, nested, p.scope)
+				}
+				field.ObjectDecoder = elm.ObjectMapDecoder(fieldPb, nested, p.scope)
+				field.ObjectEncoder = elm.ObjectFieldEncoder(fieldPb, elm.ObjectMapEncoder(fieldPb, nested, p.scope))
+				if p.OmitDefaults {
+					field.ObjectEncoder = elm.ObjectFieldEncoder(fieldPb, elm.ObjectMapOmitEmptyEncoder(fieldPb, nested, p.scope))
 				}
 				alias.Fields = append(alias.Fields, field)
 				alias.FieldEncoders = append(alias.FieldEncoders, field)
@@ -505,87 +2776,328 @@ func messages(preface []string, messagePbs []*descriptorpb.DescriptorProto, p pa
 			}
 			if isOptional(fieldPb) {
 				field := elm.TypeAliasField{
-					Name:    elm.FieldName(fieldPb.GetName()),
-					Type:    elm.MaybeType(elm.BasicFieldType(fieldPb)),
-					Number:  elm.ProtobufFieldNumber(fieldPb.GetNumber()),
-					Default: fieldDefault(fieldPb),
-					Encoder: elm.MaybeEncoder(fieldPb),
-					Decoder: elm.MaybeDecoder(fieldPb),
+					Name:       elm.RecordFieldName(fieldPb, p.scope),
+					Type:       elm.MaybeType(elm.BasicFieldType(fieldPb, p.scope)),
+					Number:     elm.ProtobufFieldNumber(fieldPb.GetNumber()),
+					Default:    "Nothing",
+					Encoder:    elm.MaybeEncoder(fieldPb, p.scope),
+					Decoder:    elm.MaybeDecoder(fieldPb, p.scope),
+					Deprecated: isDeprecated(fieldPb.Options),
 				}
+				field.ObjectDecoder = elm.ObjectMaybeDecoder(fieldPb, p.scope)
+				field.ObjectEncoder = elm.ObjectFieldEncoder(fieldPb, field.Encoder)
 				alias.Fields = append(alias.Fields, field)
 				alias.FieldEncoders = append(alias.FieldEncoders, field)
 				continue
 			}
 			if isRepeated(fieldPb) {
 				field := elm.TypeAliasField{
-					Name:    elm.FieldName(fieldPb.GetName()),
-					Type:    elm.ListType(elm.BasicFieldType(fieldPb)),
-					Number:  elm.ProtobufFieldNumber(fieldPb.GetNumber()),
-					Default: "[]",
-					Encoder: elm.ListEncoder(fieldPb),
-					Decoder: elm.ListDecoder(fieldPb),
+					Name:       elm.RecordFieldName(fieldPb, p.scope),
+					Type:       elm.ListType(elm.BasicFieldType(fieldPb, p.scope)),
+					Number:     elm.ProtobufFieldNumber(fieldPb.GetNumber()),
+					Default:    elm.ListDefault(),
+					Encoder:    elm.ListEncoder(fieldPb, p.scope),
+					Decoder:    elm.ListDecoder(fieldPb, p.scope),
+					Deprecated: isDeprecated(fieldPb.Options),
+				}
+				if p.OmitDefaults {
+					field.Encoder = elm.ListOmitEmptyEncoder(fieldPb, p.scope)
+				}
+				field.ObjectDecoder = elm.ObjectListDecoder(fieldPb, p.scope)
+				if p.LenientLists {
+					field.Decoder = elm.LenientListDecoder(fieldPb, p.scope)
+					field.ObjectDecoder = elm.ObjectLenientListDecoder(fieldPb, p.scope)
 				}
+				field.ObjectEncoder = elm.ObjectFieldEncoder(fieldPb, field.Encoder)
 				alias.Fields = append(alias.Fields, field)
 				alias.FieldEncoders = append(alias.FieldEncoders, field)
 				continue
 			}
+			defV, err := fieldDefault(fieldPb, p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid field %s.%s: %v", name, fieldPb.GetName(), err)
+			}
 			field := elm.TypeAliasField{
-				Name:    elm.FieldName(fieldPb.GetName()),
-				Type:    elm.BasicFieldType(fieldPb),
-				Number:  elm.ProtobufFieldNumber(fieldPb.GetNumber()),
-				Default: fieldDefault(fieldPb),
-				Encoder: elm.RequiredFieldEncoder(fieldPb),
-				Decoder: elm.RequiredFieldDecoder(fieldPb),
+				Name:       elm.RecordFieldName(fieldPb, p.scope),
+				Type:       elm.BasicFieldType(fieldPb, p.scope),
+				Number:     elm.ProtobufFieldNumber(fieldPb.GetNumber()),
+				Default:    defV,
+				Encoder:    elm.RequiredFieldEncoder(fieldPb, p.scope),
+				Decoder:    elm.RequiredFieldDecoder(fieldPb, zeroValue(fieldPb, p), p.scope),
+				Deprecated: isDeprecated(fieldPb.Options),
+			}
+			if p.OmitDefaults && !isMessage(fieldPb) {
+				field.Encoder = elm.RequiredFieldOmitDefaultEncoder(fieldPb, zeroValue(fieldPb, p), p.scope)
 			}
+			field.ObjectDecoder = elm.ObjectRequiredFieldDecoder(fieldPb, zeroValue(fieldPb, p), p.scope)
+			field.ObjectEncoder = elm.ObjectFieldEncoder(fieldPb, field.Encoder)
 			alias.Fields = append(alias.Fields, field)
 			alias.FieldEncoders = append(alias.FieldEncoders, field)
 		}
 		sort.Slice(alias.FieldEncoders, func(i, j int) bool {
 			// Order matters in the encoders, since their index has to correspond to
-			// their field number for port encoding.  The template fills in gaps but
-			// has no way to backtrack if it has already filled in an index.
+			// their field number for port encoding.  Padding fills in the gaps
+			// between consecutive field numbers.
 			return alias.FieldEncoders[i].Number < alias.FieldEncoders[j].Number
 		})
+		elm.PadFieldEncoders(alias.FieldEncoders)
+		if alias.ObjectEncoding {
+			alias.ObjectEncoders = objectEncoders(alias.FieldEncoders)
+		}
+		if p.backend != elm.BinaryBackend && !alias.ObjectEncoding {
+			if err := checkSparseFields(name, alias.FieldEncoders); err != nil {
+				return nil, err
+			}
+		}
 
-		for _, oneOfPb := range messagePb.GetOneofDecl() {
-			typeName := elm.OneOfType(elm.NestedType(oneOfPb.GetName(), nestedPreface))
-			alias.Fields = append(alias.Fields, elm.TypeAliasField{
-				Name:    elm.FieldName(oneOfPb.GetName()),
-				Type:    typeName,
-				Default: string(typeName + "Unspecified"),
-				Decoder: elm.OneOfDecoder(oneOfPb, typeName),
-			})
+		for oneofIndex, oneOfPb := range messagePb.GetOneofDecl() {
+			if isSyntheticOneof(messagePb, oneofIndex) {
+				continue
+			}
+
+			typeName := elm.OneOfType(elm.NestedType(oneOfPb.GetName(), nestedPreface, p.scope))
+			field := elm.TypeAliasField{
+				Name:          elm.FieldName(oneOfPb.GetName()),
+				Type:          typeName,
+				Default:       string(typeName + "Unspecified"),
+				Decoder:       elm.OneOfDecoder(oneOfPb, typeName, p.scope),
+				ObjectDecoder: elm.ObjectOneOfDecoder(typeName, p.scope),
+			}
+			if fields := oneofFields(messagePb, oneofIndex, p); p.MaybeOneofs && len(fields) > 0 {
+				field.Type = elm.MaybeType(typeName)
+				field.Default = "Nothing"
+				field.Decoder = elm.MaybeOneOfDecoder(typeName, elm.FieldNum(fields[0]), p.scope)
+				field.ObjectDecoder = elm.ObjectMaybeOneOfDecoder(typeName, fields, p.scope)
+			}
+			alias.Fields = append(alias.Fields, field)
+		}
+
+		// The binary decoders skip unknown fields, extensions included.
+		if len(messagePb.GetExtensionRange()) > 0 && p.backend != elm.BinaryBackend {
+			field := elm.ExtensionsField()
+			for _, other := range alias.Fields {
+				if other.Name == field.Name {
+					return nil, fmt.Errorf("message %s has extension ranges, so it can't have a field named %s", name, field.Name)
+				}
+			}
+			debugf("  Extensions of %s are kept in field %s", name, field.Name)
+			alias.Fields = append(alias.Fields, field)
+			alias.FieldEncoders = append(alias.FieldEncoders, field)
+		}
+
+		if p.Validators {
+			alias.Validations = validations(name, messagePb, alias.Fields, p)
+			if len(alias.Validations) > 0 {
+				alias.Validator = elm.ValidatorName(name)
+			}
+		}
+
+		if p.Builders {
+			for i := range alias.Fields {
+				alias.Fields[i].Setter = elm.SetterName(name, alias.Fields[i].Name)
+			}
+		}
+
+		nestedMessages, err := messages(nestedPreface, messagePb.GetNestedType(), p)
+		if err != nil {
+			return nil, err
 		}
 
 		result = append(result, pbMessage{
+			FullName:         fullTypeName(p.pkg, nestedPreface),
+			MapEntry:         messagePb.GetOptions().GetMapEntry(),
 			TypeAlias:        alias,
 			OneOfCustomTypes: oneOfsToCustomTypes(nestedPreface, messagePb, p),
 			EnumCustomTypes:  enumsToCustomTypes(nestedPreface, messagePb.GetEnumType(), p),
-			NestedMessages:   messages(nestedPreface, messagePb.GetNestedType(), p),
+			NestedMessages:   nestedMessages,
 		})
 	}
 
+	return result, nil
+}
+
+// validations returns the checks of the (validate.rules) set on a message's
+// fields.  Rules that can't be checked are skipped with a warning.
+func validations(name elm.Type, messagePb *descriptorpb.DescriptorProto, fields []elm.TypeAliasField, p parameters) []elm.Validation {
+	var result []elm.Validation
+	for _, fieldPb := range messagePb.GetField() {
+		rules, ok := options.Validate(fieldPb.GetOptions())
+		if !ok {
+			continue
+		}
+
+		var field *elm.TypeAliasField
+		for i := range fields {
+			if fields[i].Name == elm.RecordFieldName(fieldPb, p.scope) && !isOneofVariant(fieldPb) {
+				field = &fields[i]
+			}
+		}
+		if field == nil {
+			if !(isDeprecated(fieldPb.Options) && p.RemoveDeprecated) {
+				warnf("validation rules of %s.%s are skipped, since it is part of a oneof", name, fieldPb.GetName())
+			}
+			continue
+		}
+
+		checks, skipped := elm.FieldValidations(*field, rules)
+		for _, rule := range skipped {
+			warnf("%s validation rules of %s.%s are not supported and are skipped", rule, name, fieldPb.GetName())
+		}
+		result = append(result, checks...)
+	}
+
+	return result
+}
+
+// objectEncoders returns the canonical JSON object encoders of a message's
+// fields, in field number order.  Every variant of a oneof shares a single
+// encoder, which is kept at the position of the oneof's first field.
+func objectEncoders(encoders []elm.TypeAliasField) []elm.FieldEncoder {
+	var result []elm.FieldEncoder
+	seen := map[elm.FieldEncoder]bool{}
+	for _, encoder := range encoders {
+		if seen[encoder.ObjectEncoder] {
+			continue
+		}
+		seen[encoder.ObjectEncoder] = true
+		result = append(result, encoder.ObjectEncoder)
+	}
+
 	return result
 }
 
+// wrapperType builds the wrapper type for a message selected by wrap-type,
+// which must have exactly one singular, non-oneof field.
+func wrapperType(name elm.Type, messagePb *descriptorpb.DescriptorProto, p parameters) (elm.WrapperType, error) {
+	if len(messagePb.GetField()) != 1 {
+		return elm.WrapperType{}, fmt.Errorf("wrapped messages must have exactly one field, found %d", len(messagePb.GetField()))
+	}
+
+	fieldPb := messagePb.GetField()[0]
+	if isRepeated(fieldPb) || isOneofVariant(fieldPb) || isOptional(fieldPb) {
+		return elm.WrapperType{}, fmt.Errorf("wrapped field %s must not be repeated, optional or part of a oneof", fieldPb.GetName())
+	}
+
+	return elm.NewWrapperType(name, fieldPb, zeroValue(fieldPb, p), p.scope), nil
+}
+
+// checkSparseFields guards against field numbers that are much larger than the
+// number of fields, since every unused field number below the largest one
+// becomes a null in the encoded javascript array.
+func checkSparseFields(name elm.Type, encoders []elm.TypeAliasField) error {
+	if len(encoders) == 0 {
+		return nil
+	}
+
+	maxNum := int(encoders[len(encoders)-1].Number)
+	empty := maxNum - len(encoders)
+	switch {
+	case empty > sparseFieldsLimit:
+		return fmt.Errorf(
+			"message %s has field number %d but only %d fields, which would encode %d empty array slots; renumber the fields, or use json=both,json-encoder=object or backend=binary, which do not index by field number",
+			name, maxNum, len(encoders), empty,
+		)
+	case empty > sparseFieldsWarning:
+		warnf("message %s has field number %d but only %d fields, so its encoder pads %d empty array slots", name, maxNum, len(encoders), empty)
+	}
+
+	return nil
+}
+
+// normalizeEditions rewrites the fields of a file using editions syntax with
+// the labels of proto2 and proto3, which the rest of the generator relies on.
+// Scalar fields with LEGACY_REQUIRED presence become required, and scalar
+// fields that opt in to explicit presence where it is otherwise implicit become
+// proto3 optional fields.
+func normalizeEditions(inFile *descriptorpb.FileDescriptorProto) {
+	edition, ok := options.FileEdition(inFile)
+	if !ok {
+		return
+	}
+
+	presence := options.FilePresence(inFile.GetOptions(), options.DefaultPresence(edition))
+	normalizeFieldPresence(inFile.GetMessageType(), presence)
+}
+
+func normalizeFieldPresence(messagePbs []*descriptorpb.DescriptorProto, presence options.Presence) {
+	for _, messagePb := range messagePbs {
+		for _, fieldPb := range messagePb.GetField() {
+			if fieldPb.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED || fieldPb.OneofIndex != nil {
+				continue
+			}
+
+			// Message fields always have explicit presence and are generated
+			// as a Maybe, even when required.
+			if isMessage(fieldPb) {
+				continue
+			}
+
+			switch options.FieldPresence(fieldPb.GetOptions(), presence) {
+			case options.PresenceLegacyRequired:
+				fieldPb.Label = descriptorpb.FieldDescriptorProto_LABEL_REQUIRED.Enum()
+			case options.PresenceExplicit:
+				if presence == options.PresenceImplicit {
+					fieldPb.Proto3Optional = proto.Bool(true)
+				}
+			}
+		}
+
+		normalizeFieldPresence(messagePb.GetNestedType(), presence)
+	}
+}
+
+// isOptional reports whether a field is generated as a Maybe: either a
+// singular message field or a proto3 `optional` field.
 func isOptional(inField *descriptorpb.FieldDescriptorProto) bool {
-	return inField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL &&
-		inField.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	if inField.GetProto3Optional() {
+		return true
+	}
+
+	return inField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL && isMessage(inField)
 }
 
-func isRepeated(inField *descriptorpb.FieldDescriptorProto) bool {
-	return inField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+// isMessage reports whether a field holds a message.  Proto2 groups are
+// messages that are delimited differently on the wire, so they are generated
+// the same way.
+func isMessage(inField *descriptorpb.FieldDescriptorProto) bool {
+	return inField.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE ||
+		inField.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP
+}
+
+// isOneofVariant reports whether a field belongs to a real oneof.  Proto3
+// `optional` fields are also assigned a oneof, but it is a synthetic one that
+// only exists to track presence.
+func isOneofVariant(inField *descriptorpb.FieldDescriptorProto) bool {
+	return inField.OneofIndex != nil && !inField.GetProto3Optional()
 }
 
-func getLocalType(fullyQualifiedTypeName string) string {
-	splitName := strings.Split(fullyQualifiedTypeName, ".")
-	return splitName[len(splitName)-1]
+// isSyntheticOneof reports whether the oneof at oneofIndex was synthesized by
+// protoc for a proto3 `optional` field.
+func isSyntheticOneof(inMessage *descriptorpb.DescriptorProto, oneofIndex int) bool {
+	for _, inField := range inMessage.GetField() {
+		if inField.OneofIndex != nil && inField.GetOneofIndex() == int32(oneofIndex) {
+			return inField.GetProto3Optional()
+		}
+	}
+
+	return false
+}
+
+func isRepeated(inField *descriptorpb.FieldDescriptorProto) bool {
+	return inField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED
 }
 
+// getNestedType returns the map entry message nested in inMessage that
+// inField is a list of, if any.  The field's type name is matched against the
+// whole nested name rather than only its last segment, so that synthetic
+// descriptors whose nested names contain dots still resolve.
 func getNestedType(inField *descriptorpb.FieldDescriptorProto, inMessage *descriptorpb.DescriptorProto) *descriptorpb.DescriptorProto {
-	localTypeName := getLocalType(inField.GetTypeName())
+	typeName := inField.GetTypeName()
 	for _, nested := range inMessage.GetNestedType() {
-		if nested.GetName() == localTypeName && nested.GetOptions().GetMapEntry() {
+		if !nested.GetOptions().GetMapEntry() {
+			continue
+		}
+		if typeName == nested.GetName() || strings.HasSuffix(typeName, "."+nested.GetName()) {
 			return nested
 		}
 	}
@@ -593,11 +3105,141 @@ func getNestedType(inField *descriptorpb.FieldDescriptorProto, inMessage *descri
 	return nil
 }
 
-func fileName(inFilePath string) string {
+// outputNames returns the output file name for each input file.  With
+// flatten-output, files are named after the proto base name alone unless two
+// files share a base name, in which case they fall back to flatFileName.
+func outputNames(inFiles []*descriptorpb.FileDescriptorProto, p parameters) []string {
+	names := make([]string, len(inFiles))
+	if !p.FlattenOutput {
+		for i, inFile := range inFiles {
+			names[i] = fileName(p.modulePath(inFile), p.fileSuffix)
+		}
+		return names
+	}
+
+	baseNames := map[string]int{}
+	for _, inFile := range inFiles {
+		baseNames[fileName(filepath.Base(p.modulePath(inFile)), p.fileSuffix)]++
+	}
+
+	for i, inFile := range inFiles {
+		name := fileName(filepath.Base(p.modulePath(inFile)), p.fileSuffix)
+		if baseNames[name] > 1 {
+			name = flatFileName(p.modulePath(inFile), p.fileSuffix)
+		}
+		names[i] = name
+	}
+
+	return names
+}
+
+// checkFileName returns an error for files whose name can't be turned into a
+// module name.  protoc always names files, but hand-built descriptors may not.
+func checkFileName(inFile *descriptorpb.FileDescriptorProto) error {
+	name := inFile.GetName()
+	if name == "" {
+		return fmt.Errorf("input file with package %q and %d messages has no name", inFile.GetPackage(), len(inFile.GetMessageType()))
+	}
+	if strings.HasSuffix(name, "/") || strings.TrimSuffix(filepath.Base(name), ".proto") == "" {
+		return fmt.Errorf("invalid file name %q: there is no base name to generate a module name from", name)
+	}
+
+	return nil
+}
+
+// checkDuplicateNames returns an error when more than one input file would be
+// written to the same output file, since protoc silently keeps only the last.
+func checkDuplicateNames(inFiles []*descriptorpb.FileDescriptorProto, names []string) error {
+	sources := map[string][]string{}
+	for i, name := range names {
+		sources[name] = append(sources[name], inFiles[i].GetName())
+	}
+
+	var collisions []string
+	for i, name := range names {
+		if len(sources[name]) > 1 && sources[name][0] == inFiles[i].GetName() {
+			collisions = append(collisions, fmt.Sprintf("%s (from %s)", name, strings.Join(sources[name], ", ")))
+		}
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("multiple files generate the same output file: %s", strings.Join(collisions, "; "))
+	}
+
+	return nil
+}
+
+// checkImportCycles returns an error naming the files of the first import
+// cycle found between the modules generated for inFiles, since Elm modules
+// can't import each other.  Public imports are followed the same way that
+// additionalImports follows them.
+func checkImportCycles(inFiles []*descriptorpb.FileDescriptorProto, files map[string]*descriptorpb.FileDescriptorProto) error {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			for i, other := range path {
+				if other == name {
+					return fmt.Errorf("import cycle between generated modules: %s", strings.Join(append(path[i:], name), " -> "))
+				}
+			}
+		}
+
+		inFile, ok := files[name]
+		if !ok || excludedFiles[name] {
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range dependencies(inFile, files) {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+
+		return nil
+	}
+
+	for _, inFile := range inFiles {
+		if err := visit(inFile.GetName()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flatFileName joins every path segment of a proto file into a single file
+// name, e.g. `foo/bar.proto` becomes `Foo_Bar.elm`.
+func flatFileName(inFilePath, suffix string) string {
+	var segments []string
+	for _, segment := range strings.Split(strings.TrimSuffix(inFilePath, ".proto"), "/") {
+		if segment == "" {
+			continue
+		}
+
+		segments = append(segments, moduleSegment(segment))
+	}
+
+	return strings.Join(segments, "_") + suffix
+}
+
+func fileName(inFilePath, suffix string) string {
 	inFileDir, inFileName := filepath.Split(inFilePath)
 
 	trimmed := strings.TrimSuffix(inFileName, ".proto")
-	shortFileName := stringextras.FirstUpper(trimmed)
+	shortFileName := moduleSegment(trimmed)
 
 	fullFileName := ""
 	for _, segment := range strings.Split(inFileDir, "/") {
@@ -605,17 +3247,17 @@ func fileName(inFilePath string) string {
 			continue
 		}
 
-		fullFileName += stringextras.FirstUpper(segment) + "/"
+		fullFileName += moduleSegment(segment) + "/"
 	}
 
-	return fullFileName + shortFileName + extension
+	return fullFileName + shortFileName + suffix
 }
 
 func moduleName(modPrefix, inFilePath string) string {
 	inFileDir, inFileName := filepath.Split(inFilePath)
 
 	trimmed := strings.TrimSuffix(inFileName, ".proto")
-	shortModuleName := stringextras.FirstUpper(trimmed)
+	shortModuleName := moduleSegment(trimmed)
 
 	path := strings.Split(inFileDir, string(filepath.Separator))
 	if modPrefix != "" {
@@ -628,29 +3270,234 @@ func moduleName(modPrefix, inFilePath string) string {
 			continue
 		}
 
-		final = append(final, stringextras.FirstUpper(segment))
+		final = append(final, moduleSegment(segment))
 	}
 
 	return strings.Join(append(final, shortModuleName), ".")
 }
 
-func additionalImports(modPrefix string, dependencies []string) []string {
-	prefix := strings.Split(modPrefix, ".")
+// normalizeModulePrefix drops the empty segments of a dot separated module
+// prefix, e.g. from a trailing dot, and rejects segments that aren't valid Elm
+// module name components.
+func normalizeModulePrefix(prefix string) (string, error) {
+	var segments []string
+	for _, segment := range strings.Split(prefix, ".") {
+		if segment == "" {
+			continue
+		}
+		for i, r := range segment {
+			if r >= unicode.MaxASCII || !(unicode.IsLetter(r) || (i > 0 && (unicode.IsDigit(r) || r == '_'))) {
+				return "", fmt.Errorf("invalid module-prefix: \"%s\", expected dot separated module names such as Acme.Api", prefix)
+			}
+		}
+		segments = append(segments, stringextras.FirstUpper(segment))
+	}
+
+	return strings.Join(segments, "."), nil
+}
+
+// isVariableName reports whether name can start an Elm variable name: a
+// lowercase letter followed by letters, digits and underscores.
+func isVariableName(name string) bool {
+	for i, r := range name {
+		if r >= unicode.MaxASCII || !(unicode.IsLower(r) || (i > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'))) {
+			return false
+		}
+	}
+
+	return name != ""
+}
+
+// moduleSegment turns a path segment into a valid Elm module name component:
+// characters other than letters, digits and underscores are replaced with
+// underscores, and segments that don't start with a letter are prefixed with
+// "P" (e.g. `123data` becomes `P123data`).
+func moduleSegment(segment string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			return r
+		}
+		return '_'
+	}, segment)
+
+	if sanitized == "" || !unicode.IsLetter(rune(sanitized[0])) {
+		sanitized = "P" + sanitized
+	}
+
+	return stringextras.FirstUpper(sanitized)
+}
+
+// addEnumModules records the Elm module that each enum in a file (including
+// nested enums) is generated in, keyed by its fully qualified PB name.
+func addEnumModules(enumModules map[string]string, inFile *descriptorpb.FileDescriptorProto, module string) {
+	prefix := ""
+	if inFile.GetPackage() != "" {
+		prefix = "." + inFile.GetPackage()
+	}
+
+	for _, enumPb := range inFile.GetEnumType() {
+		enumModules[prefix+"."+enumPb.GetName()] = module
+	}
+	for _, messagePb := range inFile.GetMessageType() {
+		addNestedEnumModules(enumModules, prefix+"."+messagePb.GetName(), messagePb, module)
+	}
+}
+
+func addNestedEnumModules(enumModules map[string]string, prefix string, messagePb *descriptorpb.DescriptorProto, module string) {
+	for _, enumPb := range messagePb.GetEnumType() {
+		enumModules[prefix+"."+enumPb.GetName()] = module
+	}
+	for _, nested := range messagePb.GetNestedType() {
+		addNestedEnumModules(enumModules, prefix+"."+nested.GetName(), nested, module)
+	}
+}
+
+// enumDefinition is an enum along with the names of the messages it is
+// nested in.
+type enumDefinition struct {
+	pb      *descriptorpb.EnumDescriptorProto
+	preface []string
+}
+
+// addEnumDefinitions records each enum in a file (including nested enums),
+// keyed by its fully qualified PB name.
+func addEnumDefinitions(enums map[string]enumDefinition, inFile *descriptorpb.FileDescriptorProto) {
+	var walk func(preface []string, enumPbs []*descriptorpb.EnumDescriptorProto, messagePbs []*descriptorpb.DescriptorProto)
+	walk = func(preface []string, enumPbs []*descriptorpb.EnumDescriptorProto, messagePbs []*descriptorpb.DescriptorProto) {
+		for _, enumPb := range enumPbs {
+			enums[fullTypeName(inFile.GetPackage(), append(append([]string(nil), preface...), enumPb.GetName()))] = enumDefinition{pb: enumPb, preface: preface}
+		}
+		for _, messagePb := range messagePbs {
+			walk(append(append([]string(nil), preface...), messagePb.GetName()), messagePb.GetEnumType(), messagePb.GetNestedType())
+		}
+	}
+	walk(nil, inFile.GetEnumType(), inFile.GetMessageType())
+}
+
+// addTypeModules records the Elm module that each message and enum in a file
+// (including nested definitions) is generated in, keyed by its fully
+// qualified PB name.
+func addTypeModules(typeModules map[string]string, inFile *descriptorpb.FileDescriptorProto, module string) {
+	prefix := ""
+	if inFile.GetPackage() != "" {
+		prefix = "." + inFile.GetPackage()
+	}
+
+	for _, enumPb := range inFile.GetEnumType() {
+		typeModules[prefix+"."+enumPb.GetName()] = module
+	}
+	for _, messagePb := range inFile.GetMessageType() {
+		addNestedTypeModules(typeModules, prefix+"."+messagePb.GetName(), messagePb, module)
+	}
+}
+
+func addNestedTypeModules(typeModules map[string]string, name string, messagePb *descriptorpb.DescriptorProto, module string) {
+	typeModules[name] = module
+	for _, enumPb := range messagePb.GetEnumType() {
+		typeModules[name+"."+enumPb.GetName()] = module
+	}
+	for _, nested := range messagePb.GetNestedType() {
+		addNestedTypeModules(typeModules, name+"."+nested.GetName(), nested, module)
+	}
+}
+
+// collidingTypes returns the module of each type from another module that
+// inFile refers to by the same Elm name as a different type.  Elm names drop
+// the package, so e.g. a.Status and b.Status are both Status, and references
+// to them are qualified with their module instead.
+func collidingTypes(inFile *descriptorpb.FileDescriptorProto, p parameters) elm.QualifiedTypes {
+	byName := map[elm.Type]map[string]bool{}
+	addName := func(typeName string) {
+		name := elm.ExternalType(typeName, p.scope)
+		if byName[name] == nil {
+			byName[name] = map[string]bool{}
+		}
+		byName[name][typeName] = true
+	}
+
+	var walk func(prefix string, messagePbs []*descriptorpb.DescriptorProto)
+	walk = func(prefix string, messagePbs []*descriptorpb.DescriptorProto) {
+		for _, messagePb := range messagePbs {
+			name := prefix + "." + messagePb.GetName()
+			addName(name)
+			for _, enumPb := range messagePb.GetEnumType() {
+				addName(name + "." + enumPb.GetName())
+			}
+			for _, fieldPb := range messagePb.GetField() {
+				if fieldPb.GetTypeName() != "" && !elm.IsWellKnownType(fieldPb.GetTypeName(), p.scope) {
+					addName(fieldPb.GetTypeName())
+				}
+			}
+			walk(name, messagePb.GetNestedType())
+		}
+	}
+
+	prefix := ""
+	if inFile.GetPackage() != "" {
+		prefix = "." + inFile.GetPackage()
+	}
+	for _, enumPb := range inFile.GetEnumType() {
+		addName(prefix + "." + enumPb.GetName())
+	}
+	walk(prefix, inFile.GetMessageType())
+
+	result := elm.QualifiedTypes{}
+	for _, typeNames := range byName {
+		if len(typeNames) < 2 {
+			continue
+		}
+		for typeName := range typeNames {
+			if module, ok := p.typeModules[typeName]; ok && module != p.module {
+				result[typeName] = module
+			}
+		}
+	}
+
+	return result
+}
+
+// dependencies returns the files imported by inFile along with any files that
+// they publicly import, since protoc lets inFile use definitions from both.
+func dependencies(inFile *descriptorpb.FileDescriptorProto, files map[string]*descriptorpb.FileDescriptorProto) []string {
+	var result []string
+	seen := map[string]bool{}
+
+	var add func(name string)
+	add = func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		result = append(result, name)
+
+		dep, ok := files[name]
+		if !ok {
+			return
+		}
+		for _, i := range dep.GetPublicDependency() {
+			add(dep.GetDependency()[i])
+		}
+	}
+
+	for _, name := range inFile.GetDependency() {
+		add(name)
+	}
+
+	return result
+}
+
+func additionalImports(dependencies []string, p parameters) []string {
 	var additions []string
 	for _, d := range dependencies {
 		if excludedFiles[d] {
 			continue
 		}
 
-		final := append([]string(nil), prefix...)
-		for _, segment := range strings.Split(strings.TrimSuffix(d, ".proto"), "/") {
-			if segment == "" {
-				continue
-			}
-			final = append(final, stringextras.FirstUpper(segment))
+		if dep, ok := p.files[d]; ok {
+			additions = append(additions, p.moduleFor(dep))
+			continue
 		}
-
-		additions = append(additions, strings.Join(final, "."))
+		additions = append(additions, moduleName(p.modPrefix, d))
 	}
 	return additions
 }
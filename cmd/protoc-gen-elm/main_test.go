@@ -0,0 +1,578 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jalandis/elm-protobuf/pkg/elm"
+	"github.com/jalandis/elm-protobuf/pkg/options"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// restoreGlobals restores the package level settings that parseParameters
+// changes once the test finishes.
+func restoreGlobals(t *testing.T) {
+	backend := elm.SelectedBackend
+	decoderStyle := elm.SelectedDecoderStyle
+	repeated := elm.SelectedRepeated
+	logLevel := selectedLogLevel
+	t.Cleanup(func() {
+		elm.SelectedBackend = backend
+		elm.SelectedDecoderStyle = decoderStyle
+		elm.SelectedRepeated = repeated
+		selectedLogLevel = logLevel
+	})
+}
+
+func TestParseParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    func(p *parameters)
+		wantErr string
+	}{
+		{
+			name:  "empty",
+			input: "",
+		},
+		{
+			name:  "whitespace and empty segments",
+			input: " , ,\t",
+		},
+		{
+			name:  "trailing comma",
+			input: "remove-deprecated,",
+			want: func(p *parameters) {
+				p.RemoveDeprecated = true
+			},
+		},
+		{
+			name:  "whitespace around names and values",
+			input: " remove-deprecated , max-nested-name-length = 5 ",
+			want: func(p *parameters) {
+				p.RemoveDeprecated = true
+				p.scope.MaxNestedNameLength = 5
+			},
+		},
+		{
+			name:  "enum-dict",
+			input: "enum-dict=4",
+			want: func(p *parameters) {
+				p.enumDict = 4
+			},
+		},
+		{
+			name:  "codec-prefix",
+			input: "codec-prefix=pb",
+			want: func(p *parameters) {
+				p.scope.CodecPrefix = "pb"
+			},
+		},
+		{
+			name:  "timestamp",
+			input: "timestamp=millis",
+			want: func(p *parameters) {
+				p.timestamp = "millis"
+				p.scope.SetWellKnownType(".google.protobuf.Timestamp", elm.TimestampMillisType)
+			},
+		},
+		{
+			name:  "wrap-type",
+			input: "codec-prefix=pb,wrap-type=acme.UserId:UserId",
+			want: func(p *parameters) {
+				p.scope.CodecPrefix = "pb"
+				p.wrapTypes = map[string]elm.Type{".acme.UserId": "UserId"}
+				p.scope.SetWellKnownType(".acme.UserId", elm.WellKnownType{
+					Type:    "UserId",
+					Decoder: "pbUserIdPortDecoder",
+					Encoder: "pbUserIdPortEncoder",
+				})
+			},
+		},
+		{
+			name:  "scalar-map",
+			input: "scalar-map=double:Decimal.Decimal:Decimal.decoder:Decimal.encoder",
+			want: func(p *parameters) {
+				decimal := elm.ScalarType{
+					Type:    "Decimal.Decimal",
+					Decoder: "Decimal.decoder",
+					Encoder: "Decimal.encoder",
+				}
+				p.scalarTypes = map[string]elm.ScalarType{"double": decimal}
+				decimal.Default = "Decimal.decimalDefault"
+				p.scope.ScalarTypes = map[descriptorpb.FieldDescriptorProto_Type]elm.ScalarType{
+					descriptorpb.FieldDescriptorProto_TYPE_DOUBLE: decimal,
+				}
+			},
+		},
+		{
+			name:    "invalid max-nested-name-length",
+			input:   "max-nested-name-length=0",
+			wantErr: `invalid max-nested-name-length: "0"`,
+		},
+		{
+			name:    "invalid enum-dict",
+			input:   "enum-dict=many",
+			wantErr: `invalid enum-dict: "many"`,
+		},
+		{
+			name:    "invalid backend before a valid max-nested-name-length",
+			input:   "backend=bogus,max-nested-name-length=5",
+			wantErr: `unknown backend: "bogus"`,
+		},
+		{
+			name:    "invalid json before a valid enum-dict",
+			input:   "json=xml,enum-dict=4",
+			wantErr: `unknown json format: "xml"`,
+		},
+		{
+			name:    "two invalid parameters",
+			input:   "backend=bogus,json=xml",
+			wantErr: `unknown backend: "bogus"`,
+		},
+		{
+			name:    "two invalid switch parameters",
+			input:   "timestamp=iso,decoder-style=fancy,repeated=set",
+			wantErr: `unknown timestamp format: "iso"`,
+		},
+		{
+			name:    "invalid backend before a valid module-prefix",
+			input:   "backend=bogus,module-prefix=Acme",
+			wantErr: `unknown backend: "bogus"`,
+		},
+		{
+			name:  "log-level",
+			input: "log-level=debug",
+			want: func(p *parameters) {
+				p.logLevel = debugLevel
+			},
+		},
+		{
+			name:    "unknown log-level",
+			input:   "log-level=loud",
+			wantErr: `unknown log-level: "loud"`,
+		},
+		{
+			name:    "unknown parameter",
+			input:   "remove-deprecated,bogus",
+			wantErr: `unknown parameter: "bogus"`,
+		},
+		{
+			name:  "ignored unknown parameter",
+			input: "bogus,ignore-unknown-params",
+			want: func(p *parameters) {
+				p.IgnoreUnknown = true
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			restoreGlobals(t)
+
+			got, err := parseParameters(&test.input)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("parseParameters(%q) error = %v, want %s", test.input, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseParameters(%q) unexpected error: %v", test.input, err)
+			}
+
+			want, _ := parseParameters(nil)
+			if test.want != nil {
+				test.want(&want)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("parseParameters(%q) = %+v, want %+v", test.input, got, want)
+			}
+		})
+	}
+}
+
+// TestParseParametersRepeated parses parameters that register types twice,
+// as a process generating several requests does, checking that the second
+// parse neither fails nor inherits the registrations of the first.
+func TestParseParametersRepeated(t *testing.T) {
+	restoreGlobals(t)
+	input := "timestamp=millis,wrap-type=.acme.UserId:UserId,scalar-map=double:Decimal.Decimal:Decimal.decoder:Decimal.encoder"
+	first, err := parseParameters(&input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := parseParameters(&input)
+	if err != nil {
+		t.Fatalf("parsing again: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("parsing again = %+v, want %+v", second, first)
+	}
+
+	p, err := parseParameters(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wkt, _ := elm.WellKnownTypeFor(".google.protobuf.Timestamp", p.scope); wkt.Type != "Timestamp" {
+		t.Errorf("Timestamp resolved to %s after timestamp=millis was parsed", wkt.Type)
+	}
+	if elm.IsWellKnownType(".acme.UserId", p.scope) {
+		t.Error(".acme.UserId resolved as a wrapper after wrap-type was parsed")
+	}
+	if len(p.scope.ScalarTypes) != 0 {
+		t.Errorf("scalar types %v registered after scalar-map was parsed", p.scope.ScalarTypes)
+	}
+}
+
+func TestMessagesFieldName(t *testing.T) {
+	tests := []struct {
+		fieldName string
+		wantErr   string
+	}{
+		{fieldName: "displayName"},
+		{fieldName: "display_name2"},
+		{fieldName: "DisplayName", wantErr: `invalid field Profile.name: (elm.field_name) "DisplayName" is not a lowercase Elm name`},
+		{fieldName: "display-name", wantErr: `invalid field Profile.name: (elm.field_name) "display-name" is not a lowercase Elm name`},
+		{fieldName: "2name", wantErr: `invalid field Profile.name: (elm.field_name) "2name" is not a lowercase Elm name`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fieldName, func(t *testing.T) {
+			restoreGlobals(t)
+			p, err := parseParameters(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			field := scalarField("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+			field.Options = &descriptorpb.FieldOptions{}
+			proto.SetExtension(field.Options, options.E_FieldName, test.fieldName)
+			messagePbs := []*descriptorpb.DescriptorProto{{
+				Name:  proto.String("Profile"),
+				Field: []*descriptorpb.FieldDescriptorProto{field},
+			}}
+
+			_, err = messages(nil, messagePbs, p)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Fatalf("error = %v, want %s", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// TestMessagesRepeatedOneofMember checks that a descriptor protoc would reject,
+// with a repeated field in a oneof, is reported rather than generated.
+func TestMessagesRepeatedOneofMember(t *testing.T) {
+	restoreGlobals(t)
+	p, err := parseParameters(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags := scalarField("tags", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+	tags.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	tags.OneofIndex = proto.Int32(0)
+	messagePbs := []*descriptorpb.DescriptorProto{{
+		Name:      proto.String("Profile"),
+		Field:     []*descriptorpb.FieldDescriptorProto{tags},
+		OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("labels")}},
+	}}
+
+	_, err = messages(nil, messagePbs, p)
+	want := "invalid field Profile.tags: members of oneof labels can't be repeated"
+	if err == nil || err.Error() != want {
+		t.Fatalf("error = %v, want %s", err, want)
+	}
+}
+
+func TestFieldDefault(t *testing.T) {
+	withDefault := func(name string, fieldType descriptorpb.FieldDescriptorProto_Type, value string) *descriptorpb.FieldDescriptorProto {
+		field := scalarField(name, 1, fieldType)
+		field.Options = &descriptorpb.FieldOptions{}
+		proto.SetExtension(field.Options, options.E_Default, value)
+		return field
+	}
+	theme := func(value string) *descriptorpb.FieldDescriptorProto {
+		field := withDefault("theme", descriptorpb.FieldDescriptorProto_TYPE_ENUM, value)
+		field.TypeName = proto.String(".settings.Theme")
+		return field
+	}
+	jsString := func(value string) *descriptorpb.FieldDescriptorProto {
+		field := withDefault("id", descriptorpb.FieldDescriptorProto_TYPE_INT64, value)
+		field.Options.Jstype = descriptorpb.FieldOptions_JS_STRING.Enum()
+		return field
+	}
+	nested := scalarField("nested", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE)
+	nested.TypeName = proto.String(".settings.Settings")
+	nested.Options = &descriptorpb.FieldOptions{}
+	proto.SetExtension(nested.Options, options.E_Default, "{}")
+
+	tests := []struct {
+		name    string
+		field   *descriptorpb.FieldDescriptorProto
+		want    string
+		wantErr string
+	}{
+		{name: "string", field: withDefault("s", descriptorpb.FieldDescriptorProto_TYPE_STRING, `say "hi"`), want: `"say \"hi\""`},
+		{name: "bool", field: withDefault("b", descriptorpb.FieldDescriptorProto_TYPE_BOOL, "true"), want: "True"},
+		{name: "int32", field: withDefault("n", descriptorpb.FieldDescriptorProto_TYPE_INT32, "-25"), want: "-25"},
+		{name: "double", field: withDefault("d", descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, "1e3"), want: "1000"},
+		{name: "jstype string", field: jsString("9007199254740993"), want: `"9007199254740993"`},
+		{name: "enum", field: theme("THEME_DARK"), want: "ThemeDark"},
+		{name: "Elm bool", field: withDefault("b", descriptorpb.FieldDescriptorProto_TYPE_BOOL, "True"), wantErr: `(elm.default) "True" is not a valid bool`},
+		{name: "int32 out of range", field: withDefault("n", descriptorpb.FieldDescriptorProto_TYPE_INT32, "4294967296"), wantErr: `(elm.default) "4294967296" is not a valid int32`},
+		{name: "negative uint32", field: withDefault("n", descriptorpb.FieldDescriptorProto_TYPE_UINT32, "-1"), wantErr: `(elm.default) "-1" is not a valid uint32`},
+		{name: "expression", field: withDefault("d", descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, "1 / 0"), wantErr: `(elm.default) "1 / 0" is not a valid double`},
+		{name: "infinite double", field: withDefault("d", descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, "inf"), wantErr: `(elm.default) "inf" is not a valid double`},
+		{name: "Elm variant", field: theme("ThemeDark"), wantErr: `(elm.default) "ThemeDark" is not a value of enum settings.Theme`},
+		{name: "message", field: nested, wantErr: `(elm.default) "{}" can't be used as the default of a message field`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			restoreGlobals(t)
+			p, err := parseParameters(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			inFile := &descriptorpb.FileDescriptorProto{
+				Name:    proto.String("settings.proto"),
+				Package: proto.String("settings"),
+				Syntax:  proto.String("proto3"),
+				EnumType: []*descriptorpb.EnumDescriptorProto{{
+					Name: proto.String("Theme"),
+					Value: []*descriptorpb.EnumValueDescriptorProto{
+						{Name: proto.String("THEME_UNSPECIFIED"), Number: proto.Int32(0)},
+						{Name: proto.String("THEME_DARK"), Number: proto.Int32(1)},
+					},
+				}},
+			}
+			p, err = resolveFiles([]*descriptorpb.FileDescriptorProto{inFile}, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.module = p.moduleFor(inFile)
+
+			got, err := fieldDefault(test.field, p)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("error = %v, want %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("fieldDefault = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckImportCycles(t *testing.T) {
+	file := func(name string, deps ...string) *descriptorpb.FileDescriptorProto {
+		return &descriptorpb.FileDescriptorProto{
+			Name:       proto.String(name),
+			Dependency: deps,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		inFiles []*descriptorpb.FileDescriptorProto
+		wantErr string
+	}{
+		{
+			name: "diamond",
+			inFiles: []*descriptorpb.FileDescriptorProto{
+				file("a.proto", "b.proto", "c.proto"),
+				file("b.proto", "d.proto"),
+				file("c.proto", "d.proto"),
+				file("d.proto"),
+			},
+		},
+		{
+			name: "direct",
+			inFiles: []*descriptorpb.FileDescriptorProto{
+				file("a.proto", "b.proto"),
+				file("b.proto", "a.proto"),
+			},
+			wantErr: "import cycle between generated modules: a.proto -> b.proto -> a.proto",
+		},
+		{
+			name: "three files",
+			inFiles: []*descriptorpb.FileDescriptorProto{
+				file("a.proto", "b.proto"),
+				file("b.proto", "c.proto"),
+				file("c.proto", "a.proto"),
+			},
+			wantErr: "import cycle between generated modules: a.proto -> b.proto -> c.proto -> a.proto",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			files := map[string]*descriptorpb.FileDescriptorProto{}
+			for _, inFile := range test.inFiles {
+				files[inFile.GetName()] = inFile
+			}
+
+			err := checkImportCycles(test.inFiles, files)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Fatalf("error = %v, want %s", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckDuplicateNames(t *testing.T) {
+	file := func(name, pkg string) *descriptorpb.FileDescriptorProto {
+		return &descriptorpb.FileDescriptorProto{
+			Name:    proto.String(name),
+			Package: proto.String(pkg),
+		}
+	}
+
+	tests := []struct {
+		parameter string
+		inFiles   []*descriptorpb.FileDescriptorProto
+		wantErr   string
+	}{
+		{
+			parameter: "module-from=path",
+			inFiles:   []*descriptorpb.FileDescriptorProto{file("api/user.proto", "acme"), file("admin/user.proto", "acme")},
+		},
+		{
+			parameter: "module-from=package",
+			inFiles:   []*descriptorpb.FileDescriptorProto{file("api/user.proto", "acme"), file("admin/user.proto", "acme")},
+			wantErr:   "multiple files generate the same output file: Acme/User.elm (from api/user.proto, admin/user.proto)",
+		},
+		{
+			parameter: "flatten-output",
+			inFiles:   []*descriptorpb.FileDescriptorProto{file("api/user.proto", "acme"), file("admin/user.proto", "acme")},
+		},
+		{
+			parameter: "flatten-output,remove-deprecated",
+			inFiles:   []*descriptorpb.FileDescriptorProto{file("api/user.proto", "acme"), file("Api/user.proto", "acme"), file("admin/user.proto", "acme")},
+			wantErr:   "multiple files generate the same output file: Api_User.elm (from api/user.proto, Api/user.proto)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.parameter, func(t *testing.T) {
+			restoreGlobals(t)
+			p, err := parseParameters(proto.String(test.parameter))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = checkDuplicateNames(test.inFiles, outputNames(test.inFiles, p))
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Fatalf("error = %v, want %s", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestMessagesSparseFields(t *testing.T) {
+	tests := []struct {
+		parameter string
+		number    int32
+		wantErr   string
+	}{
+		{number: 300},
+		{number: 100000, wantErr: "message Profile has field number 100000 but only 2 fields, which would encode 99998 empty array slots; renumber the fields, or use json=both,json-encoder=object or backend=binary, which do not index by field number"},
+		{parameter: "json=both,json-encoder=object", number: 100000},
+		{parameter: "backend=binary", number: 100000},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%d", test.parameter, test.number), func(t *testing.T) {
+			restoreGlobals(t)
+			p, err := parseParameters(proto.String(test.parameter))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			messagePbs := []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Profile"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					scalarField("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					scalarField("age", test.number, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+				},
+			}}
+
+			_, err = messages(nil, messagePbs, p)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Fatalf("error = %v, want %s", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveFilesName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{name: "acme/user.proto"},
+		{name: "", wantErr: `input file with package "acme" and 1 messages has no name`},
+		{name: "acme/", wantErr: `invalid file name "acme/": there is no base name to generate a module name from`},
+		{name: "acme/.proto", wantErr: `invalid file name "acme/.proto": there is no base name to generate a module name from`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			restoreGlobals(t)
+			p, err := parseParameters(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			inFile := &descriptorpb.FileDescriptorProto{
+				Name:        proto.String(test.name),
+				Package:     proto.String("acme"),
+				MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("User")}},
+			}
+			_, err = resolveFiles([]*descriptorpb.FileDescriptorProto{inFile}, p)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Fatalf("error = %v, want %s", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -84,11 +84,16 @@ repeated : String -> JD.Decoder a -> JD.Decoder (List a -> b) -> JD.Decoder b
 repeated name decoder d =
     field (withDefault [] <| JD.field name <| JD.list decoder) d
 
-{-| Decodes a Dict.
+{-| Decodes a map field at an index of a message in the javascript array
+format, where maps are lists of [ key, value ] pairs.
 -}
-mapEntries : String -> JD.Decoder a -> JD.Decoder (Dict.Dict String a -> b) -> JD.Decoder b
-mapEntries name valueDecoder d =
-    field (withDefault Dict.empty <| JD.field name <| JD.dict valueDecoder) d
+mapEntries : Int -> JD.Decoder comparable -> JD.Decoder a -> JD.Decoder (Dict.Dict comparable a -> b) -> JD.Decoder b
+mapEntries idx keyDecoder valueDecoder d =
+    let
+        entry =
+            JD.map2 Tuple.pair (JD.index 0 keyDecoder) (JD.index 1 valueDecoder)
+    in
+    field (withDefault Dict.empty <| JD.index idx <| JD.oneOf [ JD.null Dict.empty, JD.map Dict.fromList (JD.list entry) ]) d
 
 
 {-| Decodes a field.
@@ -138,18 +143,13 @@ repeatedFieldEncoder name encoder v =
         _ ->
             Just ( name, JE.list encoder v )
 
-{-| Encodes dictionary field.
+{-| Encodes a map field in the javascript array format, as a list of
+[ key, value ] pairs.
 -}
-mapEntriesFieldEncoder : String -> (a -> JE.Value) -> Dict.Dict String a -> Maybe ( String, JE.Value )
-mapEntriesFieldEncoder name valueEncoder v =
-    if Dict.isEmpty v then
-        Nothing
-    else
-        let
-            items = Dict.toList v
-            encodedItems = List.map (\(key, val) -> (key, valueEncoder val)) items
-        in
-            Just ( name, JE.object encodedItems)
+mapEntriesFieldEncoder : (comparable -> JE.Value) -> (a -> JE.Value) -> Dict.Dict comparable a -> JE.Value
+mapEntriesFieldEncoder keyEncoder valueEncoder v =
+    Dict.toList v
+        |> JE.list (\( key, val ) -> JE.list identity [ keyEncoder key, valueEncoder val ])
 
 
 {-| Bytes field.
@@ -0,0 +1,299 @@
+package elm
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// binaryWellKnownType - decoder and encoder expressions for a Google well
+// known type in the binary wire format.  Wrapper types are messages holding
+// their value in field 1.
+type binaryWellKnownType struct {
+	Decoder VariableName
+	Encoder VariableName
+}
+
+var binaryWellKnownTypeMap = map[string]binaryWellKnownType{
+	".google.protobuf.Int32Value":  binaryWrapper("Decode.int32", "Encode.int32", "0"),
+	".google.protobuf.UInt32Value": binaryWrapper("Decode.uint32", "Encode.uint32", "0"),
+	".google.protobuf.DoubleValue": binaryWrapper("Decode.double", "Encode.double", "0"),
+	".google.protobuf.FloatValue":  binaryWrapper("Decode.float", "Encode.float", "0"),
+	".google.protobuf.StringValue": binaryWrapper("Decode.string", "Encode.string", `""`),
+	".google.protobuf.BytesValue":  binaryWrapper("bytesDecoder", "bytesEncoder", "[]"),
+	".google.protobuf.BoolValue":   binaryWrapper("Decode.bool", "Encode.bool", "False"),
+}
+
+func binaryWrapper(decoder, encoder VariableName, def string) binaryWellKnownType {
+	return binaryWellKnownType{
+		Decoder: VariableName(fmt.Sprintf("(Decode.message %s [ Decode.optional 1 %s always ])", def, decoder)),
+		Encoder: VariableName(fmt.Sprintf("(\\x -> Encode.message [ ( 1, %s x ) ])", encoder)),
+	}
+}
+
+// BinarySupported - returns an error for fields that the binary backend can't
+// represent.  Elm has no 64 bit integers, so neither 64 bit integer fields nor
+// the well known types built on them are supported.  The runtime can't decode
+// groups, which are delimited by start and end tags.
+func BinarySupported(inField *descriptorpb.FieldDescriptorProto, s Scope) error {
+	switch inField.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return fmt.Errorf("64 bit integer type %s is not supported by the binary backend", inField.GetType())
+	case descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return fmt.Errorf("group fields are not supported by the binary backend")
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		if !IsWellKnownType(inField.GetTypeName(), s) {
+			return nil
+		}
+		if _, ok := binaryWellKnownTypeMap[inField.GetTypeName()]; !ok {
+			return fmt.Errorf("well known type %s is not supported by the binary backend", inField.GetTypeName())
+		}
+	}
+
+	return nil
+}
+
+func binaryFieldEncoder(inField *descriptorpb.FieldDescriptorProto, s Scope) VariableName {
+	switch inField.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32:
+		return "Encode.int32"
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT32:
+		return "Encode.uint32"
+	case descriptorpb.FieldDescriptorProto_TYPE_SINT32:
+		return "Encode.sint32"
+	case descriptorpb.FieldDescriptorProto_TYPE_FIXED32:
+		return "Encode.fixed32"
+	case descriptorpb.FieldDescriptorProto_TYPE_SFIXED32:
+		return "Encode.sfixed32"
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		return "Encode.float"
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return "Encode.double"
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		return "Encode.bool"
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return "Encode.string"
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return "bytesEncoder"
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		if n, ok := binaryWellKnownTypeMap[inField.GetTypeName()]; ok {
+			return n.Encoder
+		}
+
+		return VariableName(s.qualify(inField.GetTypeName(), string(EncoderName(ExternalType(inField.GetTypeName(), s), s))))
+	default:
+		panic(fmt.Errorf("error generating binary encoder for field %s", inField.GetType()))
+	}
+}
+
+func binaryFieldDecoder(inField *descriptorpb.FieldDescriptorProto, s Scope) VariableName {
+	switch inField.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32:
+		return "Decode.int32"
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT32:
+		return "Decode.uint32"
+	case descriptorpb.FieldDescriptorProto_TYPE_SINT32:
+		return "Decode.sint32"
+	case descriptorpb.FieldDescriptorProto_TYPE_FIXED32:
+		return "Decode.fixed32"
+	case descriptorpb.FieldDescriptorProto_TYPE_SFIXED32:
+		return "Decode.sfixed32"
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		return "Decode.float"
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return "Decode.double"
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		return "Decode.bool"
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return "Decode.string"
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return "bytesDecoder"
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		if n, ok := binaryWellKnownTypeMap[inField.GetTypeName()]; ok {
+			return n.Decoder
+		}
+
+		return VariableName(s.qualify(inField.GetTypeName(), string(DecoderName(ExternalType(inField.GetTypeName(), s), s))))
+	default:
+		panic(fmt.Errorf("error generating binary decoder for field %s", inField.GetType()))
+	}
+}
+
+// binarySetter - lambda that sets a record field, as expected by the
+// Protobuf.Decode field decoders
+func binarySetter(name VariableName) string {
+	return fmt.Sprintf("(\\a r -> { r | %s = a })", name)
+}
+
+// binaryMapValueDefault - value used by Decode.mapped for map entries
+// without a value.  Unlike message fields, map values are not wrapped in
+// Maybe.
+func binaryMapValueDefault(valueField *descriptorpb.FieldDescriptorProto, s Scope) string {
+	if valueField.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+		return s.qualify(valueField.GetTypeName(), fmt.Sprintf("default%s", ExternalType(valueField.GetTypeName(), s)))
+	}
+
+	return BasicFieldDefaultValue(valueField, s)
+}
+
+func binaryRequiredFieldEncoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf(
+		"%d, %s v.%s",
+		FieldNum(pb),
+		BasicFieldEncoder(pb, s),
+		RecordFieldName(pb, s),
+	))
+}
+
+func binaryRequiredFieldOmitDefaultEncoder(pb *descriptorpb.FieldDescriptorProto, zero string, s Scope) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf(
+		"%d, if v.%s == %s then Encode.none else %s v.%s",
+		FieldNum(pb),
+		RecordFieldName(pb, s),
+		zero,
+		BasicFieldEncoder(pb, s),
+		RecordFieldName(pb, s),
+	))
+}
+
+func binaryRequiredFieldDecoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldDecoder {
+	return FieldDecoder(fmt.Sprintf(
+		"Decode.optional %d %s %s",
+		FieldNum(pb),
+		BasicFieldDecoder(pb, s),
+		binarySetter(RecordFieldName(pb, s)),
+	))
+}
+
+func binaryOneOfEncoder(oneof *descriptorpb.OneofDescriptorProto, field *descriptorpb.FieldDescriptorProto, t Type, s Scope) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf("%d, %s %d v.%s",
+		FieldNum(field),
+		EncoderName(t, s),
+		FieldNum(field),
+		FieldName(oneof.GetName()),
+	))
+}
+
+func binaryOneOfDecoder(pb *descriptorpb.OneofDescriptorProto, t Type, s Scope) FieldDecoder {
+	return FieldDecoder(fmt.Sprintf(
+		"Decode.oneOf %s (\\a r -> { r | %s = Maybe.withDefault %sUnspecified a })",
+		DecoderName(t, s),
+		FieldName(pb.GetName()),
+		t,
+	))
+}
+
+func binaryMapEncoder(fieldPb *descriptorpb.FieldDescriptorProto, messagePb *descriptorpb.DescriptorProto, s Scope) FieldEncoder {
+	keyField := messagePb.GetField()[0]
+	valueField := messagePb.GetField()[1]
+
+	return FieldEncoder(fmt.Sprintf(
+		"%d, Encode.dict %s %s v.%s",
+		FieldNum(fieldPb),
+		BasicFieldEncoder(keyField, s),
+		BasicFieldEncoder(valueField, s),
+		RecordFieldName(fieldPb, s),
+	))
+}
+
+func binaryMapDecoder(fieldPb *descriptorpb.FieldDescriptorProto, messagePb *descriptorpb.DescriptorProto, s Scope) FieldDecoder {
+	keyField := messagePb.GetField()[0]
+	valueField := messagePb.GetField()[1]
+
+	return FieldDecoder(fmt.Sprintf(
+		"Decode.mapped %d ( %s, %s ) %s %s .%s %s",
+		FieldNum(fieldPb),
+		BasicFieldDefaultValue(keyField, s),
+		binaryMapValueDefault(valueField, s),
+		BasicFieldDecoder(keyField, s),
+		BasicFieldDecoder(valueField, s),
+		RecordFieldName(fieldPb, s),
+		binarySetter(RecordFieldName(fieldPb, s)),
+	))
+}
+
+func binaryMaybeEncoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf(
+		"%d, Maybe.withDefault Encode.none (Maybe.map %s v.%s)",
+		FieldNum(pb),
+		BasicFieldEncoder(pb, s),
+		RecordFieldName(pb, s),
+	))
+}
+
+func binaryMaybeDecoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldDecoder {
+	return FieldDecoder(fmt.Sprintf(
+		"Decode.optional %d (Decode.map Just %s) %s",
+		FieldNum(pb),
+		BasicFieldDecoder(pb, s),
+		binarySetter(RecordFieldName(pb, s)),
+	))
+}
+
+func binaryListEncoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf(
+		"%d, Encode.list %s v.%s",
+		FieldNum(pb),
+		BasicFieldEncoder(pb, s),
+		RecordFieldName(pb, s),
+	))
+}
+
+func binaryListDecoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldDecoder {
+	return FieldDecoder(fmt.Sprintf(
+		"Decode.repeated %d %s .%s %s",
+		FieldNum(pb),
+		BasicFieldDecoder(pb, s),
+		RecordFieldName(pb, s),
+		binarySetter(RecordFieldName(pb, s)),
+	))
+}
+
+// Protobuf wire types, as used in field tags
+// https://protobuf.dev/programming-guides/encoding/#structure
+const (
+	WireVarint     = 0
+	WireFixed64    = 1
+	WireLength     = 2
+	WireStartGroup = 3
+	WireFixed32    = 5
+)
+
+// WireType - protobuf wire type of a single value of a PB field.  Packed
+// repeated fields are encoded as a single WireLength value instead.
+func WireType(field *descriptorpb.FieldDescriptorProto) int {
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+		descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return WireVarint
+	case descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return WireFixed64
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		return WireLength
+	case descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return WireStartGroup
+	case descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		return WireFixed32
+	default:
+		panic(fmt.Errorf("error - no known wire type for field %s", field.GetType()))
+	}
+}
@@ -0,0 +1,43 @@
+package elm
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestWireType(t *testing.T) {
+	tests := map[descriptorpb.FieldDescriptorProto_Type]int{
+		descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:   WireFixed64,
+		descriptorpb.FieldDescriptorProto_TYPE_FLOAT:    WireFixed32,
+		descriptorpb.FieldDescriptorProto_TYPE_INT64:    WireVarint,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64:   WireVarint,
+		descriptorpb.FieldDescriptorProto_TYPE_INT32:    WireVarint,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64:  WireFixed64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32:  WireFixed32,
+		descriptorpb.FieldDescriptorProto_TYPE_BOOL:     WireVarint,
+		descriptorpb.FieldDescriptorProto_TYPE_STRING:   WireLength,
+		descriptorpb.FieldDescriptorProto_TYPE_GROUP:    WireStartGroup,
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:  WireLength,
+		descriptorpb.FieldDescriptorProto_TYPE_BYTES:    WireLength,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT32:   WireVarint,
+		descriptorpb.FieldDescriptorProto_TYPE_ENUM:     WireVarint,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32: WireFixed32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64: WireFixed64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT32:   WireVarint,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64:   WireVarint,
+	}
+
+	for number := range descriptorpb.FieldDescriptorProto_Type_name {
+		if _, ok := tests[descriptorpb.FieldDescriptorProto_Type(number)]; !ok {
+			t.Errorf("no wire type expected for %s", descriptorpb.FieldDescriptorProto_Type(number))
+		}
+	}
+
+	for fieldType, want := range tests {
+		field := &descriptorpb.FieldDescriptorProto{Type: fieldType.Enum()}
+		if got := WireType(field); got != want {
+			t.Errorf("WireType(%s) = %d, want %d", fieldType, got, want)
+		}
+	}
+}
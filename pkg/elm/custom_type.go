@@ -17,6 +17,20 @@ type EnumCustomType struct {
 	DefaultVariantVariable VariableName
 	DefaultVariantValue    VariantName
 	Variants               []EnumVariant
+	Backend                Backend
+	// Unrecognized is only set when unknown values are preserved, in which
+	// case it names the variant that carries them.
+	Unrecognized VariantName
+	// Codec is only set for the elm-codec backend, in which case it is
+	// generated instead of the decoder and encoder.
+	Codec VariableName
+	// ToString and FromString are only set when enum string conversions are
+	// generated.
+	ToString   VariableName
+	FromString VariableName
+	// ValueLookup is only set for enums decoded through a Dict of their
+	// variants by value, which compiles faster than a case for large enums.
+	ValueLookup VariableName
 }
 
 // VariantName - unique camelcase identifier used for custom type variants
@@ -28,36 +42,82 @@ type VariantName string
 type EnumVariant struct {
 	Name  VariantName
 	Value ProtobufFieldNumber
+	// ProtoName is the name of the PB enum value, e.g. COLOR_RED.
+	ProtoName string
 }
 
 // OneOfCustomType - defines an Elm custom type (sometimes called union type) for a PB one-of
 // https://guide.elm-lang.org/types/custom_types.html
 type OneOfCustomType struct {
-	Name     Type
-	Decoder  VariableName
-	Encoder  VariableName
+	Name    Type
+	Decoder VariableName
+	Encoder VariableName
+	// Variants are ordered by field number, which is also the order the
+	// decoder tries them in when more than one could match.
 	Variants []OneOfVariant
+	Backend  Backend
+	// ObjectDecoder is set when the canonical JSON object format is decoded,
+	// and ObjectEncoder when it is encoded.
+	ObjectDecoder VariableName
+	ObjectEncoder VariableName
 }
 
 // OneOfVariant - a possible variant of a one-of CustomType
 // https://guide.elm-lang.org/types/custom_types.html
 type OneOfVariant struct {
-	Name    VariantName
-	Type    Type
-	Num     ProtobufFieldNumber
-	Decoder VariableName
-	Encoder VariableName
+	Name     VariantName
+	Type     Type
+	Num      ProtobufFieldNumber
+	Decoder  VariableName
+	Encoder  VariableName
+	JSONName string
+	// Getter and Mapper are only set when oneof accessors are generated.
+	Getter VariableName
+	Mapper VariableName
 }
 
 // NestedVariantName - Elm variant name for a possibly nested PB definition
-func NestedVariantName(name string, preface []string) VariantName {
-    fullName := strings.Join(
-        append(preface, stringextras.CamelCase(strings.ToLower(name))),
-        "_",
-    )
+func NestedVariantName(name string, preface []string, s Scope) VariantName {
+	fullName := joinNested(preface, stringextras.CamelCase(strings.ToLower(name)), s)
 	return VariantName(fullName)
 }
 
+// OneOfGetterName - accessor returning the value of a one-of variant, e.g.
+// getFoo_Bar
+func OneOfGetterName(v VariantName) VariableName {
+	return VariableName(fmt.Sprintf("get%s", v))
+}
+
+// OneOfMapperName - accessor updating the value of a one-of variant, e.g.
+// mapFoo_Bar
+func OneOfMapperName(v VariantName) VariableName {
+	return VariableName(fmt.Sprintf("map%s", v))
+}
+
+// UnrecognizedVariantName - variant holding enum values that have no
+// matching variant, e.g. UnrecognizedColor
+func UnrecognizedVariantName(t Type) VariantName {
+	return VariantName(fmt.Sprintf("Unrecognized%s", t))
+}
+
+// EnumToStringName - function converting an enum to its PB value name, e.g.
+// colorToString
+func EnumToStringName(t Type) VariableName {
+	return VariableName(stringextras.FirstLower(fmt.Sprintf("%sToString", t)))
+}
+
+// EnumFromStringName - function converting a PB value name to an enum, e.g.
+// colorFromString
+func EnumFromStringName(t Type) VariableName {
+	return VariableName(stringextras.FirstLower(fmt.Sprintf("%sFromString", t)))
+}
+
+// EnumValueLookupName - Dict of the variants of an enum by value, e.g.
+// colorByValue
+func EnumValueLookupName(t Type) VariableName {
+	return VariableName(stringextras.FirstLower(fmt.Sprintf("%sByValue", t)))
+}
+
 // EnumDefaultVariantVariableName - convenient identifier for a enum custom types default variant
 func EnumDefaultVariantVariableName(t Type) VariableName {
 	return VariableName(stringextras.FirstLower(fmt.Sprintf("%sDefault", t)))
@@ -71,19 +131,62 @@ type {{ .Name }}
 {{- range $i, $v := .Variants }}
     {{ if not $i }}={{ else }}|{{ end }} {{ $v.Name }} -- {{ $v.Value }}
 {{- end }}
+{{- if .Unrecognized }}
+    | {{ .Unrecognized }} Int
+{{- end }}
+{{- if .ValueLookup }}
+
+
+{{ .ValueLookup }} : Dict.Dict Int {{ .Name }}
+{{ .ValueLookup }} =
+    Dict.fromList
+        [{{ range $i, $v := .Variants }}{{ if $i }}
+        ,{{ end }} ( {{ .Value }}, {{ .Name }} ){{ end }}
+        ]
+{{- end }}
+{{- if eq .Backend "binary" }}
+
+
+{{ .Decoder }} : Decode.Decoder {{ .Name }}
+{{ .Decoder }} =
+    let
+        lookup v ={{ template "enum-from-int" . }}
+    in
+        Decode.map lookup Decode.int32
+
+
+{{ .DefaultVariantVariable }} : {{ .Name }}
+{{ .DefaultVariantVariable }} = {{ .DefaultVariantValue }}
+
+
+{{ .Encoder }} : {{ .Name }} -> Encode.Encoder
+{{ .Encoder }} v =
+    let
+        lookup s ={{ template "enum-to-int" . }}
+    in
+        Encode.int32 <| lookup v
+{{- else if .Codec }}
+
+
+{{ .Codec }} : Codec {{ .Name }}
+{{ .Codec }} =
+    let
+        fromInt v ={{ template "enum-from-int" . }}
+
+        toInt s ={{ template "enum-to-int" . }}
+    in
+        Codec.map fromInt toInt Codec.int
+
+
+{{ .DefaultVariantVariable }} : {{ .Name }}
+{{ .DefaultVariantVariable }} = {{ .DefaultVariantValue }}
+{{- else }}
 
 
 {{ .Decoder }} : JD.Decoder {{ .Name }}
 {{ .Decoder }} =
     let
-        lookup v =
-            case v of
-{{- range .Variants }}
-                {{ .Value }} ->
-                    {{ .Name }}
-{{ end }}
-                _ ->
-                    {{ .DefaultVariantValue }}
+        lookup v ={{ template "enum-from-int" . }}
     in
         JD.map lookup JD.int
 
@@ -95,14 +198,66 @@ type {{ .Name }}
 {{ .Encoder }} : {{ .Name }} -> JE.Value
 {{ .Encoder }} v =
     let
-        lookup s =
+        lookup s ={{ template "enum-to-int" . }}
+    in
+        JE.int <| lookup v
+{{- end }}
+{{- if .ToString }}
+
+
+{{ .ToString }} : {{ .Name }} -> String
+{{ .ToString }} v =
+    case v of
+{{- range $i, $v := .Variants }}
+{{- if $i }}
+{{ end }}
+        {{ .Name }} ->
+            "{{ .ProtoName }}"
+{{- end }}
+{{- if .Unrecognized }}
+
+        {{ .Unrecognized }} n ->
+            String.fromInt n
+{{- end }}
+
+
+{{ .FromString }} : String -> Maybe {{ .Name }}
+{{ .FromString }} s =
+    case s of
+{{- range .Variants }}
+        "{{ .ProtoName }}" ->
+            Just {{ .Name }}
+{{ end }}
+        _ ->
+            Nothing
+{{- end }}
+{{- end -}}
+
+{{- define "enum-from-int" }}
+{{- if .ValueLookup }}
+            Dict.get v {{ .ValueLookup }}
+                |> Maybe.withDefault {{ if .Unrecognized }}({{ .Unrecognized }} v){{ else }}{{ .DefaultVariantValue }}{{ end }}
+{{- else }}
+            case v of
+{{- range .Variants }}
+                {{ .Value }} ->
+                    {{ .Name }}
+{{ end }}
+                _ ->
+                    {{ if .Unrecognized }}{{ .Unrecognized }} v{{ else }}{{ .DefaultVariantValue }}{{ end }}
+{{- end }}
+{{- end -}}
+
+{{- define "enum-to-int" }}
             case s of
 {{- range .Variants }}
                 {{ .Name }} ->
                     {{ .Value }}
 {{ end }}
-    in
-        JE.int <| lookup v
+{{- if .Unrecognized }}
+                {{ .Unrecognized }} n ->
+                    n
+{{ end }}
 {{- end -}}
 `)
 }
@@ -114,8 +269,29 @@ func OneOfCustomTypeTemplate(t *template.Template) (*template.Template, error) {
 type {{ .Name }}
     = {{ .Name }}Unspecified
 {{- range .Variants }}
-    | {{ .Name }} {{ .Type }}
+    | {{ .Name }} {{ .Type }} -- {{ .Num }}
 {{- end }}
+{{- if eq .Backend "binary" }}
+
+
+{{ .Decoder }} : List ( Int, Decode.Decoder {{ .Name }} )
+{{ .Decoder }} =
+    [{{ range $i, $v := .Variants }}{{ if $i }}
+    ,{{ end }} ( {{ .Num }}, Decode.map {{ .Name }} {{ .Decoder }} ){{ end }}
+    ]
+
+
+{{ .Encoder }} : Int -> {{ .Name }} -> Encode.Encoder
+{{ .Encoder }} idx v =
+    case v of
+        {{ .Name }}Unspecified ->
+            Encode.none
+        {{- range .Variants }}
+
+        {{ .Name }} x ->
+            if idx == {{ .Num }} then {{ .Encoder }} x else Encode.none
+        {{- end }}
+{{- else }}
 
 
 {{ .Decoder }} : JD.Decoder {{ .Name }}
@@ -136,6 +312,56 @@ type {{ .Name }}
         {{ .Name }} x ->
             if idx == {{ .Num }} then {{ .Encoder }} x else JE.null
         {{- end }}
+{{- if .ObjectDecoder }}
+
+
+{{ .ObjectDecoder }} : JD.Decoder {{ .Name }}
+{{ .ObjectDecoder }} =
+    JD.lazy <| \_ -> JD.oneOf
+        [{{ range $i, $v := .Variants }}{{ if $i }},{{ end }} JD.map {{ .Name }} (JD.field "{{ .JSONName }}" (failOnNull {{ .Decoder }}))
+        {{ end }}, JD.succeed {{ .Name }}Unspecified
+        ]
+{{- end }}
+{{- if .ObjectEncoder }}
+
+
+{{ .ObjectEncoder }} : {{ .Name }} -> List ( String, JE.Value )
+{{ .ObjectEncoder }} v =
+    case v of
+        {{ .Name }}Unspecified ->
+            []
+        {{- range .Variants }}
+
+        {{ .Name }} x ->
+            [ ( "{{ .JSONName }}", {{ .Encoder }} x ) ]
+        {{- end }}
+{{- end }}
+{{- end }}
+{{- $name := .Name }}
+{{- range .Variants }}
+{{- if .Getter }}
+
+
+{{ .Getter }} : {{ $name }} -> Maybe {{ argument (printf "%s" .Type) }}
+{{ .Getter }} v =
+    case v of
+        {{ .Name }} x ->
+            Just x
+
+        _ ->
+            Nothing
+
+
+{{ .Mapper }} : ({{ .Type }} -> {{ .Type }}) -> {{ $name }} -> {{ $name }}
+{{ .Mapper }} f v =
+    case v of
+        {{ .Name }} x ->
+            {{ .Name }} (f x)
+
+        _ ->
+            v
+{{- end }}
+{{- end }}
 {{- end -}}
 `)
 }
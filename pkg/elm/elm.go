@@ -2,12 +2,14 @@ package elm
 
 import (
 	"fmt"
+	"hash/fnv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/jalandis/elm-protobuf/pkg/stringextras"
 
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
@@ -31,41 +33,309 @@ type VariableName string
 // javascript code stores protobuf messages internally).
 type ProtobufFieldNumber int
 
+// codecName - name of a decoder, encoder or codec, with the scope's
+// CodecPrefix
+func codecName(name string, s Scope) VariableName {
+	return VariableName(stringextras.FirstLower(s.CodecPrefix + name))
+}
+
 // DecoderName - decoder function name for Elm type
-func DecoderName(t Type) VariableName {
-	return VariableName(stringextras.FirstLower(fmt.Sprintf("%sPortDecoder", t)))
+func DecoderName(t Type, s Scope) VariableName {
+	if SelectedBackend == BinaryBackend {
+		return codecName(fmt.Sprintf("%sDecoder", t), s)
+	}
+
+	return codecName(fmt.Sprintf("%sPortDecoder", t), s)
 }
 
 // EncoderName - encoder function name for Elm type
-func EncoderName(t Type) VariableName {
-	return VariableName(stringextras.FirstLower(fmt.Sprintf("%sPortEncoder", t)))
+func EncoderName(t Type, s Scope) VariableName {
+	if SelectedBackend == BinaryBackend {
+		return codecName(fmt.Sprintf("%sEncoder", t), s)
+	}
+
+	return codecName(fmt.Sprintf("%sPortEncoder", t), s)
+}
+
+// ToArrayName - name of the function encoding an Elm type as the array that
+// javascript protobuf messages are constructed from
+func ToArrayName(t Type) VariableName {
+	return VariableName(stringextras.FirstLower(fmt.Sprintf("%sToArray", t)))
+}
+
+// FromArrayName - name of the function decoding an Elm type from the array
+// that javascript protobuf messages serialize to
+func FromArrayName(t Type) VariableName {
+	return VariableName(stringextras.FirstLower(fmt.Sprintf("%sFromArray", t)))
+}
+
+// ListDecoderName - name of the decoder for a top level JSON array of an Elm
+// type
+func ListDecoderName(t Type, s Scope) VariableName {
+	return codecName(fmt.Sprintf("%sListPortDecoder", t), s)
+}
+
+// CodecName - elm-codec Codec name for Elm type
+func CodecName(t Type, s Scope) VariableName {
+	return codecName(fmt.Sprintf("%sCodec", t), s)
+}
+
+// Backend - the Elm library that generated encoders and decoders are built on
+type Backend string
+
+const (
+	// PortsBackend - Json.Decode/Json.Encode functions for the javascript
+	// array format used by ports
+	PortsBackend Backend = "ports"
+	// CodecBackend - miniBill/elm-codec codecs for the same array format
+	CodecBackend Backend = "elm-codec"
+	// BinaryBackend - eriktim/elm-protocol-buffers decoders and encoders for
+	// the protobuf binary wire format
+	BinaryBackend Backend = "binary"
+)
+
+// SelectedBackend - backend that field encoders and decoders are generated for
+var SelectedBackend = PortsBackend
+
+// DecoderStyle - functions that message decoders chain field decoders with
+type DecoderStyle string
+
+const (
+	// ChainStyle - `decode Foo |> ...` with the runtime and generated helpers
+	ChainStyle DecoderStyle = "chain"
+	// PipelineStyle - `JD.succeed Foo |> ...` with NoRedInk/elm-json-decode-pipeline
+	// wherever it has a counterpart.  The javascript array format is indexed
+	// rather than keyed, so its field decoders keep the generated helpers.
+	PipelineStyle DecoderStyle = "pipeline"
+)
+
+// SelectedDecoderStyle - style that message decoders are generated in
+var SelectedDecoderStyle = ChainStyle
+
+// Repeated - Elm collection type that repeated fields are generated as
+type Repeated string
+
+const (
+	// ListRepeated - List, the default
+	ListRepeated Repeated = "list"
+	// ArrayRepeated - Array, for constant time indexing of large fields
+	ArrayRepeated Repeated = "array"
+)
+
+// SelectedRepeated - collection type of generated repeated fields
+var SelectedRepeated = ListRepeated
+
+// ListDefault - default value of repeated fields
+func ListDefault() string {
+	if SelectedRepeated == ArrayRepeated {
+		return "Array.empty"
+	}
+
+	return "[]"
+}
+
+// listDecoder - JSON decoder of a repeated field with values decoded by decoder
+func listDecoder(decoder VariableName) string {
+	if SelectedRepeated == ArrayRepeated {
+		return fmt.Sprintf("(JD.array %s)", decoder)
+	}
+
+	return fmt.Sprintf("(JD.list %s)", decoder)
+}
+
+// lenientListDecoder - like listDecoder, but also accepts a single value
+func lenientListDecoder(decoder VariableName) string {
+	if SelectedRepeated == ArrayRepeated {
+		return fmt.Sprintf("(JD.map Array.fromList (lenientList %s))", decoder)
+	}
+
+	return fmt.Sprintf("(lenientList %s)", decoder)
+}
+
+// customDecoder - field decoder running decoder on the whole message value
+func customDecoder(decoder string) FieldDecoder {
+	if SelectedDecoderStyle == PipelineStyle {
+		return FieldDecoder("Pipeline.custom " + decoder)
+	}
+
+	return FieldDecoder("custom " + decoder)
+}
+
+// StringLiteral - quoted Elm string literal for an arbitrary string
+func StringLiteral(in string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range in {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if unicode.IsControl(r) {
+				fmt.Fprintf(&b, `\u{%04X}`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// joinNested joins the names of a nested definition's parents (its preface)
+// with the definition's own name.  If the result is longer than the scope's
+// MaxNestedNameLength, the preface is replaced by the outermost parent's name
+// followed by an FNV-1a hash of the full preface, e.g. `A_B_C_D_E_Foo` becomes
+// `A1b2c3d4e_Foo`.  This is deterministic, so every reference to the same
+// definition agrees on its name, but two prefaces with colliding hashes will
+// produce the same name.
+func joinNested(preface []string, name string, s Scope) string {
+	full := strings.Join(append(append([]string(nil), preface...), name), "_")
+	if s.MaxNestedNameLength <= 0 || len(full) <= s.MaxNestedNameLength || len(preface) == 0 {
+		return full
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(strings.Join(preface, ".")))
+	return fmt.Sprintf("%s%08x_%s", preface[0], h.Sum32(), name)
+}
+
+// nestedName - Elm type for the PB definition named by segments, the names of
+// its parents followed by its own.  NestedType and ExternalType both name
+// types with it, so that a definition and the references to it from other
+// files agree on the name, hashed preface included.
+func nestedName(segments []string, s Scope) Type {
+	last := len(segments) - 1
+	preface := make([]string, last)
+	for i, segment := range segments[:last] {
+		preface[i] = stringextras.FirstUpper(segment)
+	}
+
+	return Type(joinNested(preface, stringextras.CamelCase(segments[last]), s))
 }
 
 // NestedType - top level Elm type for a possibly nested PB definition
-func NestedType(name string, preface []string) Type {
-	fullName := strings.Join(
-		append(preface, stringextras.CamelCase(name)),
-		"_",
-	)
-	return Type(stringextras.FirstUpper(fullName))
+func NestedType(name string, preface []string, s Scope) Type {
+	return nestedName(append(append([]string(nil), preface...), name), s)
+}
+
+// QualifiedTypes - map of PB type identifier to the Elm module that references
+// to the type are qualified with, for types from other modules whose Elm names
+// collide with another type in the generated file
+type QualifiedTypes map[string]string
+
+// Scope - the settings that name the definitions generated for PB types, and
+// references to them, in the file being generated
+type Scope struct {
+	// Qualified - types whose references are qualified with their module
+	Qualified QualifiedTypes
+	// CodecPrefix - prepended to the names of the decoders, encoders and
+	// codecs of generated types, e.g. pbFooPortDecoder, so that they don't
+	// collide with hand-written ones
+	CodecPrefix string
+	// MaxNestedNameLength - when positive, names of nested definitions
+	// longer than this have their preface replaced by a short hash (see
+	// joinNested)
+	MaxNestedNameLength int
+	// WellKnownTypes - encoder/decoder info that fields of the PB types it
+	// maps are resolved with in place of WellKnownTypeMap's, e.g. for the
+	// timestamp parameter
+	WellKnownTypes map[string]WellKnownType
+	// ScalarTypes - types replacing the built in ones for PB scalar types,
+	// set by scalar-map (see RegisterScalarType)
+	ScalarTypes map[descriptorpb.FieldDescriptorProto_Type]ScalarType
+	// RenameExtension - field number of the FieldOptions extension selected
+	// by rename-option, or zero (see RecordFieldName)
+	RenameExtension protowire.Number
+}
+
+// SetWellKnownType - resolves fields of the PB type typeName with t in this
+// scope
+func (s *Scope) SetWellKnownType(typeName string, t WellKnownType) {
+	if s.WellKnownTypes == nil {
+		s.WellKnownTypes = map[string]WellKnownType{}
+	}
+	s.WellKnownTypes[typeName] = t
+}
+
+// qualify - name, a definition generated for the PB type typeName, qualified
+// with its module if it is one of the scope's qualified types
+func (s Scope) qualify(typeName string, name string) string {
+	return s.Qualified.qualify(typeName, name)
+}
+
+// qualify - name, a definition generated for the PB type typeName, qualified
+// with its module if it is one of q
+func (q QualifiedTypes) qualify(typeName string, name string) string {
+	if module, ok := q[typeName]; ok {
+		return module + "." + name
+	}
+
+	return name
 }
 
 // ExternalType - handles types defined in external files
-func ExternalType(inType string) Type {
+func ExternalType(inType string, s Scope) Type {
 	messageSegments := []string{}
-	for _, s := range strings.Split(inType, ".") {
-		if s == "" {
+	for _, segment := range strings.Split(inType, ".") {
+		if segment == "" {
 			continue
 		}
 
-		if r, _ := utf8.DecodeRuneInString(s); !unicode.IsLower(r) {
-			messageSegments = append(messageSegments, stringextras.FirstUpper(s))
+		if r, _ := utf8.DecodeRuneInString(segment); !unicode.IsLower(r) {
+			messageSegments = append(messageSegments, segment)
 		}
 	}
-	return Type(strings.Join(messageSegments, "_"))
+	if len(messageSegments) == 0 {
+		return ""
+	}
+
+	return nestedName(messageSegments, s)
+}
+
+// IsJSString - whether a 64 bit integer field is marked with
+// [jstype = JS_STRING], in which case it is represented as an Elm String
+func IsJSString(inField *descriptorpb.FieldDescriptorProto) bool {
+	if inField.GetOptions().GetJstype() != descriptorpb.FieldOptions_JS_STRING {
+		return false
+	}
+
+	switch inField.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return true
+	default:
+		return false
+	}
+}
+
+func BasicFieldEncoder(inField *descriptorpb.FieldDescriptorProto, s Scope) VariableName {
+	switch SelectedBackend {
+	case CodecBackend:
+		return VariableName(fmt.Sprintf("(Codec.encoder %s)", BasicFieldCodec(inField, s)))
+	case BinaryBackend:
+		return binaryFieldEncoder(inField, s)
+	}
+
+	return basicFieldPortEncoder(inField, s)
 }
 
-func BasicFieldEncoder(inField *descriptorpb.FieldDescriptorProto) VariableName {
+func basicFieldPortEncoder(inField *descriptorpb.FieldDescriptorProto, s Scope) VariableName {
+	if t, ok := mappedScalar(inField, s); ok {
+		return t.Encoder
+	}
+
 	switch inField.GetType() {
 	case descriptorpb.FieldDescriptorProto_TYPE_INT32,
 		descriptorpb.FieldDescriptorProto_TYPE_UINT32,
@@ -78,6 +348,12 @@ func BasicFieldEncoder(inField *descriptorpb.FieldDescriptorProto) VariableName
 		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
 		descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
 		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		switch inField.GetOptions().GetJstype() {
+		case descriptorpb.FieldOptions_JS_STRING:
+			return "JE.string"
+		case descriptorpb.FieldOptions_JS_NUMBER:
+			return "JE.int"
+		}
 		return "numericStringEncoder"
 	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
 		descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
@@ -87,12 +363,13 @@ func BasicFieldEncoder(inField *descriptorpb.FieldDescriptorProto) VariableName
 	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
 		return "JE.string"
 	case descriptorpb.FieldDescriptorProto_TYPE_ENUM,
-		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
-		if n, ok := WellKnownTypeMap[inField.GetTypeName()]; ok {
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		if n, ok := WellKnownTypeFor(inField.GetTypeName(), s); ok {
 			return n.Encoder
 		}
 
-		return EncoderName(ExternalType(inField.GetTypeName()))
+		return VariableName(s.qualify(inField.GetTypeName(), string(EncoderName(ExternalType(inField.GetTypeName(), s), s))))
 	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
 		return "bytesFieldEncoder"
 	default:
@@ -100,7 +377,26 @@ func BasicFieldEncoder(inField *descriptorpb.FieldDescriptorProto) VariableName
 	}
 }
 
-func BasicFieldDecoder(inField *descriptorpb.FieldDescriptorProto) VariableName {
+func BasicFieldDecoder(inField *descriptorpb.FieldDescriptorProto, s Scope) VariableName {
+	switch SelectedBackend {
+	case CodecBackend:
+		return VariableName(fmt.Sprintf("(Codec.decoder %s)", BasicFieldCodec(inField, s)))
+	case BinaryBackend:
+		return binaryFieldDecoder(inField, s)
+	}
+
+	return basicFieldPortDecoder(inField, s)
+}
+
+func basicFieldPortDecoder(inField *descriptorpb.FieldDescriptorProto, s Scope) VariableName {
+	if t, ok := mappedScalar(inField, s); ok {
+		return t.Decoder
+	}
+
+	if IsJSString(inField) {
+		return "JD.string"
+	}
+
 	switch inField.GetType() {
 	case descriptorpb.FieldDescriptorProto_TYPE_INT32,
 		descriptorpb.FieldDescriptorProto_TYPE_INT64,
@@ -123,18 +419,76 @@ func BasicFieldDecoder(inField *descriptorpb.FieldDescriptorProto) VariableName
 	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
 		return "bytesFieldDecoder"
 	case descriptorpb.FieldDescriptorProto_TYPE_ENUM,
-		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
-		if n, ok := WellKnownTypeMap[inField.GetTypeName()]; ok {
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		if n, ok := WellKnownTypeFor(inField.GetTypeName(), s); ok {
 			return n.Decoder
 		}
 
-		return DecoderName(ExternalType(inField.GetTypeName()))
+		return VariableName(s.qualify(inField.GetTypeName(), string(DecoderName(ExternalType(inField.GetTypeName(), s), s))))
 	default:
 		panic(fmt.Errorf("error generating decoder for field %s", inField.GetType()))
 	}
 }
 
-func BasicFieldType(inField *descriptorpb.FieldDescriptorProto) Type {
+// BasicFieldCodec - elm-codec Codec for a single value of a PB field.  Codecs
+// for types that elm-codec has no equivalent for are built from the port
+// encoders and decoders in the runtime module.
+func BasicFieldCodec(inField *descriptorpb.FieldDescriptorProto, s Scope) VariableName {
+	if t, ok := mappedScalar(inField, s); ok {
+		return VariableName(fmt.Sprintf("(Codec.build %s %s)", t.Encoder, t.Decoder))
+	}
+
+	switch inField.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32:
+		return "intCodec"
+	case descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		switch inField.GetOptions().GetJstype() {
+		case descriptorpb.FieldOptions_JS_STRING:
+			return "Codec.string"
+		case descriptorpb.FieldOptions_JS_NUMBER:
+			return "intCodec"
+		}
+		return "int64Codec"
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+		descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return "Codec.float"
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		return "Codec.bool"
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return "Codec.string"
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return "bytesCodec"
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		if n, ok := WellKnownTypeFor(inField.GetTypeName(), s); ok {
+			return VariableName(fmt.Sprintf("(Codec.build %s %s)", n.Encoder, n.Decoder))
+		}
+
+		return VariableName(s.qualify(inField.GetTypeName(), string(CodecName(ExternalType(inField.GetTypeName(), s), s))))
+	default:
+		panic(fmt.Errorf("error generating codec for field %s", inField.GetType()))
+	}
+}
+
+func BasicFieldType(inField *descriptorpb.FieldDescriptorProto, s Scope) Type {
+	if t, ok := mappedScalar(inField, s); ok {
+		return t.Type
+	}
+
+	if IsJSString(inField) {
+		return stringType
+	}
+
 	switch inField.GetType() {
 	case descriptorpb.FieldDescriptorProto_TYPE_INT32,
 		descriptorpb.FieldDescriptorProto_TYPE_INT64,
@@ -157,19 +511,28 @@ func BasicFieldType(inField *descriptorpb.FieldDescriptorProto) Type {
 	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
 		return bytesType
 	case descriptorpb.FieldDescriptorProto_TYPE_ENUM,
-		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
-		if n, ok := WellKnownTypeMap[inField.GetTypeName()]; ok {
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		if n, ok := WellKnownTypeFor(inField.GetTypeName(), s); ok {
 			return n.Type
 		}
-		return ExternalType(inField.GetTypeName())
+		return Type(s.qualify(inField.GetTypeName(), string(ExternalType(inField.GetTypeName(), s))))
 	default:
 		panic(fmt.Errorf("Error generating type for field %q %s", inField.GetName(), inField.GetType()))
 	}
 }
 
-func BasicFieldDefaultValue(inField *descriptorpb.FieldDescriptorProto) string {
+func BasicFieldDefaultValue(inField *descriptorpb.FieldDescriptorProto, s Scope) string {
 	if inField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
-		return "[]"
+		return ListDefault()
+	}
+
+	if t, ok := mappedScalar(inField, s); ok {
+		return t.Default
+	}
+
+	if IsJSString(inField) {
+		return "\"0\""
 	}
 
 	switch inField.GetType() {
@@ -193,8 +556,12 @@ func BasicFieldDefaultValue(inField *descriptorpb.FieldDescriptorProto) string {
 	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
 		return "[]"
 	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
-		return string(EnumDefaultVariantVariableName(ExternalType(inField.GetTypeName())))
-	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		if n, ok := WellKnownTypeFor(inField.GetTypeName(), s); ok && n.Default != "" {
+			return n.Default
+		}
+		return s.qualify(inField.GetTypeName(), string(EnumDefaultVariantVariableName(ExternalType(inField.GetTypeName(), s))))
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptorpb.FieldDescriptorProto_TYPE_GROUP:
 		return "Nothing"
 	default:
 		// TODO: maps and stuff
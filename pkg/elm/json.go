@@ -0,0 +1,197 @@
+package elm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jalandis/elm-protobuf/pkg/stringextras"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// JSONFormat - shape of the JSON values that port decoders and encoders use
+// for messages
+type JSONFormat string
+
+const (
+	// ArrayFormat is the javascript array format, indexed by field number.
+	ArrayFormat JSONFormat = "array"
+	// ObjectFormat is the canonical proto3 JSON object format, keyed by
+	// field JSON name.
+	ObjectFormat JSONFormat = "object"
+	// BothFormats decodes either of the above.
+	BothFormats JSONFormat = "both"
+)
+
+// ObjectDecoderName - decoder name for the canonical JSON object format of a
+// oneof, which is spread across the fields of its message
+func ObjectDecoderName(t Type, s Scope) VariableName {
+	return codecName(fmt.Sprintf("%sObjectDecoder", t), s)
+}
+
+// ObjectEncoderName - encoder name for the canonical JSON object format of a
+// oneof, producing the fields of its message
+func ObjectEncoderName(t Type, s Scope) VariableName {
+	return codecName(fmt.Sprintf("%sObjectEncoder", t), s)
+}
+
+// JSONName - key of a PB field in the canonical JSON object format
+func JSONName(pb *descriptorpb.FieldDescriptorProto) string {
+	if pb.JsonName != nil {
+		return pb.GetJsonName()
+	}
+
+	return stringextras.JSONName(pb.GetName())
+}
+
+// ObjectFieldEncoder - wraps a field encoder as the list of key/value pairs
+// that it contributes to a canonical JSON object
+func ObjectFieldEncoder(pb *descriptorpb.FieldDescriptorProto, encoder FieldEncoder) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf("[ ( %q, %s ) ]", JSONName(pb), encoder))
+}
+
+// ObjectOneOfEncoder - key/value pairs of whichever oneof variant is set
+func ObjectOneOfEncoder(oneof *descriptorpb.OneofDescriptorProto, t Type, s Scope) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf("%s v.%s",
+		ObjectEncoderName(t, s),
+		FieldName(oneof.GetName()),
+	))
+}
+
+// ObjectMaybeOneOfEncoder - like ObjectOneOfEncoder, for a oneof generated as
+// a Maybe
+func ObjectMaybeOneOfEncoder(oneof *descriptorpb.OneofDescriptorProto, t Type, s Scope) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf("%s (Maybe.withDefault %sUnspecified v.%s)",
+		ObjectEncoderName(t, s),
+		t,
+		FieldName(oneof.GetName()),
+	))
+}
+
+// ObjectRequiredFieldDecoder - like RequiredFieldDecoder, for the canonical
+// JSON object format
+func ObjectRequiredFieldDecoder(pb *descriptorpb.FieldDescriptorProto, zero string, s Scope) FieldDecoder {
+	if SelectedDecoderStyle == PipelineStyle {
+		return FieldDecoder(fmt.Sprintf("Pipeline.optional %q %s %s", JSONName(pb), BasicFieldDecoder(pb, s), zero))
+	}
+
+	return FieldDecoder(fmt.Sprintf(
+		"fieldWithDefault %q %s %s",
+		JSONName(pb),
+		BasicFieldDecoder(pb, s),
+		zero,
+	))
+}
+
+// ObjectMaybeDecoder - like MaybeDecoder, for the canonical JSON object format
+func ObjectMaybeDecoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldDecoder {
+	if SelectedDecoderStyle == PipelineStyle {
+		return FieldDecoder(fmt.Sprintf("Pipeline.optional %q (JD.nullable %s) Nothing", JSONName(pb), BasicFieldDecoder(pb, s)))
+	}
+
+	return FieldDecoder(fmt.Sprintf(
+		"maybeField %q %s",
+		JSONName(pb),
+		BasicFieldDecoder(pb, s),
+	))
+}
+
+// ObjectListDecoder - like ListDecoder, for the canonical JSON object format
+func ObjectListDecoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldDecoder {
+	decoder := listDecoder(BasicFieldDecoder(pb, s))
+	if SelectedDecoderStyle == PipelineStyle {
+		return FieldDecoder(fmt.Sprintf("Pipeline.optional %q %s %s", JSONName(pb), decoder, ListDefault()))
+	}
+
+	return FieldDecoder(fmt.Sprintf(
+		"fieldWithDefault %q %s %s",
+		JSONName(pb),
+		decoder,
+		ListDefault(),
+	))
+}
+
+// ObjectLenientListDecoder - like LenientListDecoder, for the canonical JSON
+// object format
+func ObjectLenientListDecoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldDecoder {
+	decoder := lenientListDecoder(BasicFieldDecoder(pb, s))
+	if SelectedDecoderStyle == PipelineStyle {
+		return FieldDecoder(fmt.Sprintf("Pipeline.optional %q %s %s", JSONName(pb), decoder, ListDefault()))
+	}
+
+	return FieldDecoder(fmt.Sprintf(
+		"fieldWithDefault %q %s %s",
+		JSONName(pb),
+		decoder,
+		ListDefault(),
+	))
+}
+
+// ObjectOneOfDecoder - like OneOfDecoder, for the canonical JSON object format
+func ObjectOneOfDecoder(t Type, s Scope) FieldDecoder {
+	return customDecoder(string(ObjectDecoderName(t, s)))
+}
+
+// ObjectMaybeOneOfDecoder - like MaybeOneOfDecoder, for the canonical JSON
+// object format.  It decodes Nothing when none of the oneof's fields are keys
+// of the object.
+func ObjectMaybeOneOfDecoder(t Type, fields []*descriptorpb.FieldDescriptorProto, s Scope) FieldDecoder {
+	var names []string
+	for _, field := range fields {
+		names = append(names, fmt.Sprintf("%q", JSONName(field)))
+	}
+
+	return customDecoder(fmt.Sprintf("(maybeOneofField [ %s ] %s)",
+		strings.Join(names, ", "),
+		ObjectDecoderName(t, s),
+	))
+}
+
+// objectMapValue - decoder of the object that a map field is in the canonical
+// JSON object format, keyed by the map keys.  Integer keys are decimal strings.
+func objectMapValue(messagePb *descriptorpb.DescriptorProto, s Scope) VariableName {
+	keyField := messagePb.GetField()[0]
+	valueField := messagePb.GetField()[1]
+	if BasicFieldType(keyField, s) == intType {
+		return VariableName(fmt.Sprintf("(intKeyDict %s)", BasicFieldDecoder(valueField, s)))
+	}
+
+	return VariableName(fmt.Sprintf("(JD.dict %s)", BasicFieldDecoder(valueField, s)))
+}
+
+// ObjectMapDecoder - like MapDecoder, for the canonical JSON object format
+func ObjectMapDecoder(fieldPb *descriptorpb.FieldDescriptorProto, messagePb *descriptorpb.DescriptorProto, s Scope) FieldDecoder {
+	if SelectedDecoderStyle == PipelineStyle {
+		return FieldDecoder(fmt.Sprintf("Pipeline.optional %q %s Dict.empty", JSONName(fieldPb), objectMapValue(messagePb, s)))
+	}
+
+	return FieldDecoder(fmt.Sprintf(
+		"fieldWithDefault %q %s Dict.empty",
+		JSONName(fieldPb),
+		objectMapValue(messagePb, s),
+	))
+}
+
+// objectMapEncoder - JE.dict encoder of a map field, turning integer keys into
+// decimal strings
+func objectMapEncoder(messagePb *descriptorpb.DescriptorProto, s Scope) string {
+	keyField := messagePb.GetField()[0]
+	valueField := messagePb.GetField()[1]
+	keyEncoder := "identity"
+	if BasicFieldType(keyField, s) == intType {
+		keyEncoder = "String.fromInt"
+	}
+
+	return fmt.Sprintf("JE.dict %s %s", keyEncoder, BasicFieldEncoder(valueField, s))
+}
+
+// ObjectMapEncoder - like MapEncoder, for the canonical JSON object format.
+// Its result is wrapped by ObjectFieldEncoder.
+func ObjectMapEncoder(fieldPb *descriptorpb.FieldDescriptorProto, messagePb *descriptorpb.DescriptorProto, s Scope) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf("%s v.%s", objectMapEncoder(messagePb, s), RecordFieldName(fieldPb, s)))
+}
+
+// ObjectMapOmitEmptyEncoder - like ObjectMapEncoder, but encodes null for
+// empty maps
+func ObjectMapOmitEmptyEncoder(fieldPb *descriptorpb.FieldDescriptorProto, messagePb *descriptorpb.DescriptorProto, s Scope) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf("omitWhen Dict.isEmpty (%s) v.%s", objectMapEncoder(messagePb, s), RecordFieldName(fieldPb, s)))
+}
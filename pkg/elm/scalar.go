@@ -0,0 +1,80 @@
+package elm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jalandis/elm-protobuf/pkg/stringextras"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ScalarType - Elm type, decoder and encoder used in place of the built in
+// ones for a PB scalar type
+type ScalarType struct {
+	Type    Type
+	Decoder VariableName
+	Encoder VariableName
+	Default string
+}
+
+// RegisterScalarType - resolves fields of the PB scalar type named pbType
+// (e.g. `double`) to t.  When t has no Default, the default value is named
+// after the type, the same way as enum defaults (e.g. `decimalDefault` for
+// `Decimal`).  The type is only replaced in this scope.
+func (s *Scope) RegisterScalarType(pbType string, t ScalarType) error {
+	v, ok := descriptorpb.FieldDescriptorProto_Type_value["TYPE_"+strings.ToUpper(pbType)]
+	switch descriptorpb.FieldDescriptorProto_Type(v) {
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		ok = false
+	}
+	if !ok {
+		return fmt.Errorf("unknown scalar type %q", pbType)
+	}
+
+	if t.Default == "" {
+		module, name := splitQualified(string(t.Type))
+		t.Default = module + stringextras.FirstLower(name) + "Default"
+	}
+
+	if s.ScalarTypes == nil {
+		s.ScalarTypes = map[descriptorpb.FieldDescriptorProto_Type]ScalarType{}
+	}
+	s.ScalarTypes[descriptorpb.FieldDescriptorProto_Type(v)] = t
+	return nil
+}
+
+// ScalarTypeImports - modules referenced by qualified names in the scope's
+// ScalarTypes, which generated files have to import
+func ScalarTypeImports(s Scope) []string {
+	modules := map[string]bool{}
+	for _, t := range s.ScalarTypes {
+		for _, name := range []string{string(t.Type), string(t.Decoder), string(t.Encoder), t.Default} {
+			if module, _ := splitQualified(name); module != "" {
+				modules[strings.TrimSuffix(module, ".")] = true
+			}
+		}
+	}
+
+	var result []string
+	for module := range modules {
+		result = append(result, module)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// splitQualified splits a qualified Elm name into its module prefix, including
+// the trailing dot, and the name itself.
+func splitQualified(name string) (string, string) {
+	i := strings.LastIndex(name, ".")
+	return name[:i+1], name[i+1:]
+}
+
+func mappedScalar(inField *descriptorpb.FieldDescriptorProto, s Scope) (ScalarType, bool) {
+	t, ok := s.ScalarTypes[inField.GetType()]
+	return t, ok
+}
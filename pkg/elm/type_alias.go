@@ -2,8 +2,10 @@ package elm
 
 import (
 	"fmt"
+	"strings"
 	"text/template"
 
+	"github.com/jalandis/elm-protobuf/pkg/options"
 	"github.com/jalandis/elm-protobuf/pkg/stringextras"
 
 	"google.golang.org/protobuf/types/descriptorpb"
@@ -14,6 +16,9 @@ type WellKnownType struct {
 	Type    Type
 	Encoder VariableName
 	Decoder VariableName
+	// Default is only set for enums, whose zero value is otherwise named
+	// after the enum.
+	Default string
 }
 
 var (
@@ -69,6 +74,41 @@ var (
 			Decoder: "boolValueDecoder",
 			Encoder: "boolValueEncoder",
 		},
+		// NullValue has the single value NULL_VALUE, which is JSON null in
+		// the canonical JSON format and 0 in the javascript array format.
+		// It is encoded as 0 so that a oneof set to it isn't mistaken for an
+		// empty slot.
+		".google.protobuf.NullValue": {
+			Type:    "()",
+			Decoder: "(JD.oneOf [ JD.null (), JD.map (always ()) JD.int ])",
+			Encoder: "(always (JE.int 0))",
+			Default: "()",
+		},
+		// Struct, Value and ListValue hold arbitrary JSON, so they are kept
+		// as raw JSON values.
+		".google.protobuf.Struct": {
+			Type:    "JD.Value",
+			Decoder: "JD.value",
+			Encoder: "identity",
+		},
+		".google.protobuf.Value": {
+			Type:    "JD.Value",
+			Decoder: "JD.value",
+			Encoder: "identity",
+		},
+		".google.protobuf.ListValue": {
+			Type:    "JD.Value",
+			Decoder: "JD.value",
+			Encoder: "identity",
+		},
+	}
+
+	// WellKnownTypeFiles - files defining the Google well known types in
+	// WellKnownTypeMap, for which no Elm modules are generated
+	WellKnownTypeFiles = map[string]bool{
+		"google/protobuf/timestamp.proto": true,
+		"google/protobuf/wrappers.proto":  true,
+		"google/protobuf/struct.proto":    true,
 	}
 
 	reservedKeywords = map[string]bool{
@@ -89,6 +129,41 @@ var (
 	}
 )
 
+// WellKnownTypeFor - encoder/decoder info for the PB type typeName, if it is
+// handled as a well known type.  The scope's WellKnownTypes take precedence
+// over WellKnownTypeMap.
+func WellKnownTypeFor(typeName string, s Scope) (WellKnownType, bool) {
+	if wkt, ok := s.WellKnownTypes[typeName]; ok {
+		return wkt, true
+	}
+
+	wkt, ok := WellKnownTypeMap[typeName]
+	return wkt, ok
+}
+
+// IsWellKnownType - whether the PB type typeName is handled as a well known
+// type rather than by a generated definition
+func IsWellKnownType(typeName string, s Scope) bool {
+	_, ok := WellKnownTypeFor(typeName, s)
+	return ok
+}
+
+// TimestampMillisType - Timestamp well known type for javascript that stores
+// timestamps as epoch milliseconds rather than RFC 3339 strings
+var TimestampMillisType = WellKnownType{
+	Type:    "Time.Posix",
+	Decoder: "timestampMillisDecoder",
+	Encoder: "timestampMillisEncoder",
+}
+
+// TimestampPosixType - Timestamp well known type as elm/time's Time.Posix,
+// still encoded as an RFC 3339 string
+var TimestampPosixType = WellKnownType{
+	Type:    "Time.Posix",
+	Decoder: "timestampPosixDecoder",
+	Encoder: "timestampPosixEncoder",
+}
+
 // TypeAlias - defines an Elm type alias (somtimes called a record)
 // https://guide.elm-lang.org/types/type_aliases.html
 type TypeAlias struct {
@@ -97,6 +172,30 @@ type TypeAlias struct {
 	Encoder       VariableName
 	FieldEncoders []TypeAliasField
 	Fields        []TypeAliasField
+	Backend       Backend
+	StringHelpers bool
+	Merge         bool
+	// Codec is only set for the elm-codec backend, in which case it is
+	// generated instead of the decoder and encoder.
+	Codec VariableName
+	// Pipeline is set when decoders are generated in the pipeline style.
+	Pipeline bool
+	// ObjectDecoders is set when the decoder also accepts the canonical JSON
+	// object format, using each field's ObjectDecoder.
+	ObjectDecoders bool
+	// ObjectEncoding is set when messages are encoded in the canonical JSON
+	// object format, concatenating ObjectEncoders (one per field or oneof).
+	ObjectEncoding bool
+	ObjectEncoders []FieldEncoder
+	// ToArray and FromArray are only set when array helpers are generated.
+	ToArray   VariableName
+	FromArray VariableName
+	// ListDecoder is only set when stream helpers are generated.
+	ListDecoder VariableName
+	// Validator is only set when validators are generated for a type alias
+	// with (validate.rules).
+	Validator   VariableName
+	Validations []Validation
 }
 
 // FieldDecoder used in type alias decdoer (ex. )
@@ -113,6 +212,58 @@ type TypeAliasField struct {
 	Default string
 	Decoder FieldDecoder
 	Encoder FieldEncoder
+	// Padding is the number of empty values the encoder has to emit before
+	// this field so that it lands on its javascript array index.
+	Padding    int
+	Deprecated bool
+	// ObjectDecoder and ObjectEncoder are the canonical JSON object format
+	// counterparts of Decoder and Encoder, when that format is in use.
+	ObjectDecoder FieldDecoder
+	ObjectEncoder FieldEncoder
+	// Setter is only set when builders are generated.
+	Setter VariableName
+}
+
+// ValueMerger - for fields holding the JD.Value of Struct, Value or
+// ListValue, which Elm can't compare with (==), the function that merge
+// functions combine the field with by checking whether the right one is
+// empty instead.  Empty for other fields, which are compared with their
+// default by mergeField.
+func (f TypeAliasField) ValueMerger() string {
+	t := string(f.Type)
+	if !strings.Contains(t, "JD.Value") {
+		return ""
+	}
+
+	switch {
+	case strings.HasPrefix(t, "Maybe "):
+		return "mergeMaybe"
+	case strings.HasPrefix(t, "List "):
+		return "mergeUnless List.isEmpty"
+	case strings.HasPrefix(t, "Array."):
+		return "mergeUnless Array.isEmpty"
+	case strings.HasPrefix(t, "Dict."):
+		return "mergeUnless Dict.isEmpty"
+	default:
+		return ""
+	}
+}
+
+// SetterName - builder function setting a field of a type alias, e.g.
+// withPersonName
+func SetterName(t Type, field VariableName) VariableName {
+	return VariableName(fmt.Sprintf("with%s%s", t, stringextras.FirstUpper(string(field))))
+}
+
+// PadFieldEncoders - sets the Padding of each field encoder.  The encoders
+// must already be sorted by field number.
+func PadFieldEncoders(encoders []TypeAliasField) {
+	next := 0
+	for i := range encoders {
+		idx := jsIdx(encoders[i].Number)
+		encoders[i].Padding = idx - next
+		next = idx + 1
+	}
 }
 
 func avoidCollision(in string) string {
@@ -135,11 +286,45 @@ func FieldName(in string) VariableName {
 	return VariableName(avoidCollision(stringextras.LowerCamelCase(in)))
 }
 
-func RequiredFieldEncoder(pb *descriptorpb.FieldDescriptorProto) FieldEncoder {
+// RecordFieldName - record field name for a PB field, preferring the
+// (elm.field_name) option, then the option selected by rename-option, over the
+// name derived from the PB field name
+func RecordFieldName(pb *descriptorpb.FieldDescriptorProto, s Scope) VariableName {
+	if name, ok := options.FieldName(pb.GetOptions()); ok {
+		return VariableName(avoidCollision(name))
+	}
+
+	if name, ok := options.Rename(pb.GetOptions(), s.RenameExtension); ok {
+		return FieldName(name)
+	}
+
+	return FieldName(pb.GetName())
+}
+
+func RequiredFieldEncoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldEncoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryRequiredFieldEncoder(pb, s)
+	}
+
 	return FieldEncoder(fmt.Sprintf(
 		"%s v.%s",
-		BasicFieldEncoder(pb),
-		FieldName(pb.GetName()),
+		BasicFieldEncoder(pb, s),
+		RecordFieldName(pb, s),
+	))
+}
+
+// RequiredFieldOmitDefaultEncoder - like RequiredFieldEncoder, but encodes
+// null in place of the field's zero value, zero
+func RequiredFieldOmitDefaultEncoder(pb *descriptorpb.FieldDescriptorProto, zero string, s Scope) FieldEncoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryRequiredFieldOmitDefaultEncoder(pb, zero, s)
+	}
+
+	return FieldEncoder(fmt.Sprintf(
+		"omitWhen ((==) %s) %s v.%s",
+		zero,
+		BasicFieldEncoder(pb, s),
+		RecordFieldName(pb, s),
 	))
 }
 
@@ -149,64 +334,142 @@ func FieldNum(pb *descriptorpb.FieldDescriptorProto) ProtobufFieldNumber {
 	return ProtobufFieldNumber(pb.GetNumber())
 }
 
-func RequiredFieldDecoder(pb *descriptorpb.FieldDescriptorProto) FieldDecoder {
+// RequiredFieldDecoder - decodes a field, falling back to its zero value, zero,
+// when the field is absent
+func RequiredFieldDecoder(pb *descriptorpb.FieldDescriptorProto, zero string, s Scope) FieldDecoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryRequiredFieldDecoder(pb, s)
+	}
+
 	return FieldDecoder(fmt.Sprintf(
 		"idxWithDefault %d %s %s",
 		jsIdx(FieldNum(pb)),
-		BasicFieldDecoder(pb),
-		BasicFieldDefaultValue(pb),
+		BasicFieldDecoder(pb, s),
+		zero,
 	))
 }
 
-func OneOfEncoder(oneof *descriptorpb.OneofDescriptorProto, field *descriptorpb.FieldDescriptorProto, t Type) FieldEncoder {
+func OneOfEncoder(oneof *descriptorpb.OneofDescriptorProto, field *descriptorpb.FieldDescriptorProto, t Type, s Scope) FieldEncoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryOneOfEncoder(oneof, field, t, s)
+	}
+
 	return FieldEncoder(fmt.Sprintf("%s %d v.%s",
-		EncoderName(t),
+		EncoderName(t, s),
+		FieldNum(field),
+		FieldName(oneof.GetName()),
+	))
+}
+
+func OneOfDecoder(pb *descriptorpb.OneofDescriptorProto, t Type, s Scope) FieldDecoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryOneOfDecoder(pb, t, s)
+	}
+
+	return customDecoder(string(DecoderName(t, s)))
+}
+
+// MaybeOneOfEncoder - like OneOfEncoder, for a oneof generated as a Maybe,
+// which encodes Nothing the same as the Unspecified variant
+func MaybeOneOfEncoder(oneof *descriptorpb.OneofDescriptorProto, field *descriptorpb.FieldDescriptorProto, t Type, s Scope) FieldEncoder {
+	return FieldEncoder(fmt.Sprintf("%s %d (Maybe.withDefault %sUnspecified v.%s)",
+		EncoderName(t, s),
 		FieldNum(field),
+		t,
 		FieldName(oneof.GetName()),
 	))
 }
 
-func OneOfDecoder(pb *descriptorpb.OneofDescriptorProto, t Type) FieldDecoder {
-	return FieldDecoder(fmt.Sprintf("custom %s",
-		DecoderName(t),
+// MaybeOneOfDecoder - like OneOfDecoder, for a oneof generated as a Maybe.  It
+// decodes Nothing when the array doesn't reach the oneof's first field, first.
+func MaybeOneOfDecoder(t Type, first ProtobufFieldNumber, s Scope) FieldDecoder {
+	return customDecoder(fmt.Sprintf("(maybeOneof %d %s)",
+		jsIdx(first),
+		DecoderName(t, s),
 	))
 }
 
-func MapType(messagePb *descriptorpb.DescriptorProto) Type {
+// MapType - Elm Dict type for a PB map entry.  Only key types that produce a
+// comparable Elm type are supported.
+func MapType(messagePb *descriptorpb.DescriptorProto, s Scope) (Type, error) {
 	keyField := messagePb.GetField()[0]
 	valueField := messagePb.GetField()[1]
 
+	switch keyType := BasicFieldType(keyField, s); keyType {
+	case intType, stringType:
+	default:
+		return "", fmt.Errorf(
+			"unsupported map key type %s (Elm type %s): map keys must be integer or string types",
+			keyField.GetType(),
+			keyType,
+		)
+	}
+
 	return Type(fmt.Sprintf(
 		"Dict.Dict %s %s",
-		BasicFieldType(keyField),
-		BasicFieldType(valueField),
-	))
+		BasicFieldType(keyField, s),
+		BasicFieldType(valueField, s),
+	)), nil
 }
 
 func MapEncoder(
 	fieldPb *descriptorpb.FieldDescriptorProto,
 	messagePb *descriptorpb.DescriptorProto,
+	s Scope,
 ) FieldEncoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryMapEncoder(fieldPb, messagePb, s)
+	}
+
+	keyField := messagePb.GetField()[0]
 	valueField := messagePb.GetField()[1]
 
 	return FieldEncoder(fmt.Sprintf(
-		"mapEntriesFieldEncoder %d %s v.%s",
-		FieldNum(fieldPb),
-		BasicFieldEncoder(valueField),
-		FieldName(fieldPb.GetName()),
+		"mapEntriesFieldEncoder %s %s v.%s",
+		BasicFieldEncoder(keyField, s),
+		BasicFieldEncoder(valueField, s),
+		RecordFieldName(fieldPb, s),
+	))
+}
+
+// MapOmitEmptyEncoder - like MapEncoder, but encodes null for empty maps
+func MapOmitEmptyEncoder(
+	fieldPb *descriptorpb.FieldDescriptorProto,
+	messagePb *descriptorpb.DescriptorProto,
+	s Scope,
+) FieldEncoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryMapEncoder(fieldPb, messagePb, s)
+	}
+
+	keyField := messagePb.GetField()[0]
+	valueField := messagePb.GetField()[1]
+
+	return FieldEncoder(fmt.Sprintf(
+		"omitWhen Dict.isEmpty (mapEntriesFieldEncoder %s %s) v.%s",
+		BasicFieldEncoder(keyField, s),
+		BasicFieldEncoder(valueField, s),
+		RecordFieldName(fieldPb, s),
 	))
 }
 
 func MapDecoder(
 	fieldPb *descriptorpb.FieldDescriptorProto,
 	messagePb *descriptorpb.DescriptorProto,
+	s Scope,
 ) FieldDecoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryMapDecoder(fieldPb, messagePb, s)
+	}
+
+	keyField := messagePb.GetField()[0]
 	valueField := messagePb.GetField()[1]
 
 	return FieldDecoder(fmt.Sprintf(
-		"mapEntries %d %s",
-		FieldNum(fieldPb),
-		BasicFieldDecoder(valueField),
+		"mapEntries %d %s %s",
+		jsIdx(FieldNum(fieldPb)),
+		BasicFieldDecoder(keyField, s),
+		BasicFieldDecoder(valueField, s),
 	))
 }
 
@@ -214,42 +477,120 @@ func MaybeType(t Type) Type {
 	return Type(fmt.Sprintf("Maybe %s", t))
 }
 
-func MaybeEncoder(pb *descriptorpb.FieldDescriptorProto) FieldEncoder {
+func MaybeEncoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldEncoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryMaybeEncoder(pb, s)
+	}
+
 	return FieldEncoder(fmt.Sprintf(
 		"maybeEncoder %s v.%s",
-		BasicFieldEncoder(pb),
-		FieldName(pb.GetName()),
+		BasicFieldEncoder(pb, s),
+		RecordFieldName(pb, s),
 	))
 }
 
-func MaybeDecoder(pb *descriptorpb.FieldDescriptorProto) FieldDecoder {
+func MaybeDecoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldDecoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryMaybeDecoder(pb, s)
+	}
+
 	return FieldDecoder(fmt.Sprintf(
-		"idxWithDefault %d (JD.maybe %s) Nothing",
+		"maybeIdx %d %s",
 		jsIdx(FieldNum(pb)),
-		BasicFieldDecoder(pb),
+		BasicFieldDecoder(pb, s),
 	))
 }
 
 func ListType(t Type) Type {
+	if SelectedRepeated == ArrayRepeated {
+		return Type(fmt.Sprintf("Array.Array %s", t))
+	}
+
 	return Type(fmt.Sprintf("List %s", t))
 }
 
-func ListEncoder(pb *descriptorpb.FieldDescriptorProto) FieldEncoder {
+func ListEncoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldEncoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryListEncoder(pb, s)
+	}
+
+	if SelectedRepeated == ArrayRepeated {
+		return FieldEncoder(fmt.Sprintf("JE.array %s v.%s", BasicFieldEncoder(pb, s), RecordFieldName(pb, s)))
+	}
+
 	return FieldEncoder(fmt.Sprintf(
 		"JE.list %s v.%s",
-		BasicFieldEncoder(pb),
-		FieldName(pb.GetName()),
+		BasicFieldEncoder(pb, s),
+		RecordFieldName(pb, s),
+	))
+}
+
+// ListOmitEmptyEncoder - like ListEncoder, but encodes null for empty lists
+func ListOmitEmptyEncoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldEncoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryListEncoder(pb, s)
+	}
+
+	if SelectedRepeated == ArrayRepeated {
+		return FieldEncoder(fmt.Sprintf("omitWhen Array.isEmpty (JE.array %s) v.%s", BasicFieldEncoder(pb, s), RecordFieldName(pb, s)))
+	}
+
+	return FieldEncoder(fmt.Sprintf(
+		"omitWhen List.isEmpty (JE.list %s) v.%s",
+		BasicFieldEncoder(pb, s),
+		RecordFieldName(pb, s),
 	))
 }
 
-func ListDecoder(pb *descriptorpb.FieldDescriptorProto) FieldDecoder {
+func ListDecoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldDecoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryListDecoder(pb, s)
+	}
+
 	return FieldDecoder(fmt.Sprintf(
-		"idxWithDefault %d (JD.list %s) []",
+		"idxWithDefault %d %s %s",
 		jsIdx(FieldNum(pb)),
-		BasicFieldDecoder(pb),
+		listDecoder(BasicFieldDecoder(pb, s)),
+		ListDefault(),
 	))
 }
 
+// LenientListDecoder - like ListDecoder, but also accepts a single value in
+// place of a list.  The binary wire format has no such ambiguity, so the binary
+// backend uses ListDecoder.
+func LenientListDecoder(pb *descriptorpb.FieldDescriptorProto, s Scope) FieldDecoder {
+	if SelectedBackend == BinaryBackend {
+		return binaryListDecoder(pb, s)
+	}
+
+	return FieldDecoder(fmt.Sprintf(
+		"idxWithDefault %d %s %s",
+		jsIdx(FieldNum(pb)),
+		lenientListDecoder(BasicFieldDecoder(pb, s)),
+		ListDefault(),
+	))
+}
+
+// ExtensionsField - catch-all field holding the values of a message's
+// extension fields by field number.  Javascript keeps extensions in an object
+// after the message's last field.  The canonical JSON object format keys them
+// by their full name instead, so they are neither decoded nor encoded there.
+func ExtensionsField() TypeAliasField {
+	field := TypeAliasField{
+		Name:          "extensions",
+		Type:          "Dict.Dict Int JD.Value",
+		Default:       "Dict.empty",
+		Decoder:       customDecoder("extensionFields"),
+		Encoder:       "extensionObject v.extensions",
+		ObjectDecoder: customDecoder("(JD.succeed Dict.empty)"),
+	}
+	if SelectedDecoderStyle == PipelineStyle {
+		field.ObjectDecoder = "Pipeline.hardcoded Dict.empty"
+	}
+
+	return field
+}
+
 // OneOfType returns the type of a oneof field.  Oneof fields will always
 // be nested (they cannot be defined outside of a message type), so we know
 // that we will always be passed the result of a NestedType call.
@@ -263,7 +604,7 @@ func TypeAliasTemplate(t *template.Template) (*template.Template, error) {
 {{- define "type-alias" -}}
 type alias {{ .Name }} =
     { {{ range $i, $v := .Fields }}
-        {{- if $i }}, {{ end }}{{ .Name }} : {{ .Type }}{{ if .Number }} -- {{ .Number }}{{ end }}
+        {{- if $i }}, {{ end }}{{ .Name }} : {{ .Type }}{{ if .Number }} -- {{ .Number }}{{ end }}{{ if .Deprecated }} -- DEPRECATED{{ end }}
     {{ end }}}
 
 
@@ -276,34 +617,203 @@ default{{ .Name }} =
   }
 
 
+{{ if eq .Backend "binary" -}}
+-- {{ .Decoder }} is used to decode protobuf messages from the binary wire format.
+{{ .Decoder }} : Decode.Decoder {{ .Name }}
+{{ .Decoder }} =
+    Decode.lazy <| \_ -> Decode.message default{{ .Name }}
+        [ {{ range $i, $v := .Fields }}{{ if $i }}
+        , {{ end }}{{ .Decoder }}{{ end }}
+        ]
+
+
+-- {{ .Encoder }} is used to encode protobuf messages to the binary wire format.
+{{ .Encoder }} : {{ .Name }} -> Encode.Encoder
+{{ .Encoder }} v =
+    Encode.message
+        [ {{ range $i, $v := .FieldEncoders }}{{ if $i }}
+        , {{ end }}( {{ .Encoder }} ){{ end }}
+        ]
+{{- else if .Codec -}}
+-- {{ .Codec }} is used to decode and encode protobuf messages for ports, following the
+-- javascript array format.
+{{ .Codec }} : Codec {{ .Name }}
+{{ .Codec }} =
+    Codec.lazy <| \_ -> Codec.build
+        (\v ->
+            valueList
+                [ {{ range $i, $v := .FieldEncoders -}}
+                 {{- range $j, $_ := (padding $v.Padding) -}}
+                 {{- if (or $i $j) }}
+                , {{ end -}}
+                     JE.null
+                 {{- end }}
+                 {{- if (or $i $v.Padding) }}
+                , {{ end -}}
+                 ({{ $v.Encoder }})
+                {{- end }}
+                ]
+        )
+        ({{ if .Pipeline }}JD.succeed{{ else }}decode{{ end }} {{ .Name }}{{ range .Fields }}
+            |> {{ .Decoder }}{{ end }}
+        )
+{{- else -}}
+{{- if .ObjectDecoders -}}
+-- {{ .Decoder }} is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+{{ .Decoder }} : JD.Decoder {{ .Name }}
+{{ .Decoder }} =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            ({{ if .Pipeline }}JD.succeed{{ else }}decode{{ end }} {{ .Name }}{{ range .Fields }}
+                |> {{ .Decoder }}{{ end }}
+            )
+        , objectMessage
+            ({{ if .Pipeline }}JD.succeed{{ else }}decode{{ end }} {{ .Name }}{{ range .Fields }}
+                |> {{ .ObjectDecoder }}{{ end }}
+            )
+        ]
+{{- else -}}
 -- {{ .Decoder }} is used to decode protobuf messages from ports, following the javascript
 -- array format.
 {{ .Decoder }} : JD.Decoder {{ .Name }}
 {{ .Decoder }} =
-    JD.lazy <| \_ -> decode {{ .Name }}{{ range .Fields }}
+    JD.lazy <| \_ -> {{ if .Pipeline }}JD.succeed{{ else }}decode{{ end }} {{ .Name }}{{ range .Fields }}
         |> {{ .Decoder }}{{ end }}
+{{- end }}
 
 
+{{ if .ObjectEncoding -}}
+-- {{ .Encoder }} is used to encode protobuf messages for ports, following the canonical
+-- JSON object format.
+{{ .Encoder }} : {{ .Name }} -> JE.Value
+{{ .Encoder }} v =
+{{- if not .ObjectEncoders }}
+    JE.object []
+{{- else }}
+    JE.object <|
+        List.concat
+            [ {{ range $i, $v := .ObjectEncoders }}{{ if $i }}
+            , {{ end }}{{ $v }}{{ end }}
+            ]
+{{- end }}
+{{- else -}}
 -- {{ .Encoder }} is used to encode protobuf messages for ports, so that javascript code
 -- may use the value in the message constructor.
 {{ .Encoder }} : {{ .Name }} -> JE.Value
 {{ .Encoder }} v =
     -- javascript uses the field number to index into arrays, so we need to ensure that
     -- the list has empty values at indexes that don't have fields.
-    {{- $idx := 1 }}
     valueList
         [ {{ range $i, $v := .FieldEncoders -}}
-         {{- range (fieldSeq $idx $v.Number) -}}
-         {{- if (ne . 1) }}
+         {{- range $j, $_ := (padding $v.Padding) -}}
+         {{- if (or $i $j) }}
         , {{ end -}}
              JE.null
          {{- end }}
-         {{- if (ne $v.Number 1) }}
+         {{- if (or $i $v.Padding) }}
         , {{ end -}}
          ({{ $v.Encoder }})
-         {{- $idx = (nextFieldNum $v.Number) -}}
-        {{ end }}
+        {{- end }}
+        ]
+{{- end }}
+{{- end }}
+{{- if .StringHelpers }}
+
+
+-- encode{{ .Name }} encodes a {{ .Name }} to a JSON string.
+encode{{ .Name }} : {{ .Name }} -> String
+encode{{ .Name }} v =
+{{- if .Codec }}
+    Codec.encodeToString 0 {{ .Codec }} v
+{{- else }}
+    JE.encode 0 ({{ .Encoder }} v)
+{{- end }}
+
+
+-- decode{{ .Name }} decodes a {{ .Name }} from a JSON string.
+decode{{ .Name }} : String -> Result JD.Error {{ .Name }}
+decode{{ .Name }} s =
+{{- if .Codec }}
+    Codec.decodeString {{ .Codec }} s
+{{- else }}
+    JD.decodeString {{ .Decoder }} s
+{{- end }}
+{{- end }}
+{{- if .ToArray }}
+
+
+-- {{ .ToArray }} encodes a {{ .Name }} as the array that javascript protobuf message
+-- constructors take, to send through a port.
+{{ .ToArray }} : {{ .Name }} -> JE.Value
+{{ .ToArray }} =
+{{- if .Codec }}
+    Codec.encoder {{ .Codec }}
+{{- else }}
+    {{ .Encoder }}
+{{- end }}
+
+
+-- {{ .FromArray }} decodes a {{ .Name }} from the array that javascript protobuf messages
+-- return from toArray, as received through a port.
+{{ .FromArray }} : JD.Decoder {{ .Name }}
+{{ .FromArray }} =
+{{- if .Codec }}
+    Codec.decoder {{ .Codec }}
+{{- else }}
+    {{ .Decoder }}
+{{- end }}
+{{- end }}
+{{- if .ListDecoder }}
+
+
+-- {{ .ListDecoder }} decodes a top level JSON array of {{ .Name }}, e.g. a page of
+-- results.  Decode newline-delimited streams with
+-- decodeStream {{ if .Codec }}(Codec.decoder {{ .Codec }}){{ else }}{{ .Decoder }}{{ end }}.
+{{ .ListDecoder }} : JD.Decoder (List {{ .Name }})
+{{ .ListDecoder }} =
+{{- if .Codec }}
+    JD.list (Codec.decoder {{ .Codec }})
+{{- else }}
+    JD.list {{ .Decoder }}
+{{- end }}
+{{- end }}
+{{- if .Merge }}
+
+
+-- merge{{ .Name }} overlays the fields of right that are not set to their default onto left.
+merge{{ .Name }} : {{ .Name }} -> {{ .Name }} -> {{ .Name }}
+{{- if .Fields }}
+merge{{ .Name }} left right =
+    { left{{ range $i, $v := .Fields }}
+        {{ if $i }},{{ else }}|{{ end }} {{ .Name }} = {{ with .ValueMerger }}{{ . }}{{ else }}mergeField {{ argument .Default }}{{ end }} left.{{ .Name }} right.{{ .Name }}{{ end }}
+    }
+{{- else }}
+merge{{ .Name }} left _ =
+    left
+{{- end }}
+{{- end }}
+{{- $name := .Name }}
+{{- range .Fields }}
+{{- if .Setter }}
+
+
+{{ .Setter }} : {{ .Type }} -> {{ $name }} -> {{ $name }}
+{{ .Setter }} value v =
+    { v | {{ .Name }} = value }
+{{- end }}
+{{- end }}
+{{- if .Validator }}
+
+
+{{ .Validator }} : {{ .Name }} -> Result (List String) {{ .Name }}
+{{ .Validator }} v =
+    validated v
+{{- range $i, $validation := .Validations }}
+        {{ if eq $i 0 }}[{{ else }},{{ end }} ( {{ .Check }}, "{{ .Error }}" )
+{{- end }}
         ]
+{{- end }}
 {{- end -}}
 `)
 }
@@ -0,0 +1,114 @@
+package elm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jalandis/elm-protobuf/pkg/options"
+)
+
+// Validation - one check of a type alias validator, with the error reported
+// when the check is False
+type Validation struct {
+	Check string
+	Error string
+}
+
+var comparisonErrors = map[options.Comparison]string{
+	options.Equal:          "equal to",
+	options.LessThan:       "less than",
+	options.LessOrEqual:    "at most",
+	options.GreaterThan:    "greater than",
+	options.GreaterOrEqual: "at least",
+}
+
+// ValidatorName - function checking the (validate.rules) of a type alias, e.g.
+// validatePerson
+func ValidatorName(t Type) VariableName {
+	return VariableName(fmt.Sprintf("validate%s", t))
+}
+
+// FieldValidations - checks of the rules set on a record field, along with
+// the rules that can't be checked on the field's Elm type
+func FieldValidations(field TypeAliasField, rules options.ValidateRules) ([]Validation, []string) {
+	var result []Validation
+	skipped := rules.Unsupported
+	value := fmt.Sprintf("v.%s", field.Name)
+
+	if rules.Required {
+		if strings.HasPrefix(string(field.Type), "Maybe ") {
+			result = append(result, Validation{
+				Check: fmt.Sprintf("%s /= Nothing", value),
+				Error: fmt.Sprintf("%s is required", field.Name),
+			})
+		} else {
+			skipped = append(skipped, "required")
+		}
+	}
+
+	if rules.MinLen != nil || rules.MaxLen != nil {
+		if field.Type == stringType {
+			// protoc-gen-validate counts characters, which String.length
+			// doesn't for characters outside the basic multilingual plane.
+			length := fmt.Sprintf("List.length (String.toList %s)", value)
+			result = append(result, limits(field.Name, length, rules.MinLen, rules.MaxLen, "be", "characters long")...)
+		} else {
+			skipped = append(skipped, "string")
+		}
+	}
+
+	if rules.MinItems != nil || rules.MaxItems != nil {
+		switch {
+		case strings.HasPrefix(string(field.Type), "List "):
+			length := fmt.Sprintf("List.length %s", value)
+			result = append(result, limits(field.Name, length, rules.MinItems, rules.MaxItems, "have", "items")...)
+		case strings.HasPrefix(string(field.Type), "Array.Array "):
+			length := fmt.Sprintf("Array.length %s", value)
+			result = append(result, limits(field.Name, length, rules.MinItems, rules.MaxItems, "have", "items")...)
+		default:
+			skipped = append(skipped, "repeated")
+		}
+	}
+
+	if len(rules.Bounds) > 0 {
+		if field.Type == floatType || (field.Type == intType && !rules.FloatBounds) {
+			for _, bound := range rules.Bounds {
+				result = append(result, Validation{
+					Check: fmt.Sprintf("%s %s %s", value, bound.Comparison, bound.Value),
+					Error: fmt.Sprintf("%s must be %s %s", field.Name, comparisonErrors[bound.Comparison], bound.Value),
+				})
+			}
+		} else {
+			skipped = append(skipped, "numeric")
+		}
+	}
+
+	return result, skipped
+}
+
+// limits checks a length against optional minimum and maximum values.  Errors
+// read "<name> must <verb> at least <min> <unit>".
+func limits(name VariableName, length string, min *uint64, max *uint64, verb string, unit string) []Validation {
+	if min != nil && max != nil && *min == *max {
+		return []Validation{{
+			Check: fmt.Sprintf("%s == %d", length, *min),
+			Error: fmt.Sprintf("%s must %s exactly %d %s", name, verb, *min, unit),
+		}}
+	}
+
+	var result []Validation
+	if min != nil {
+		result = append(result, Validation{
+			Check: fmt.Sprintf("%s >= %d", length, *min),
+			Error: fmt.Sprintf("%s must %s at least %d %s", name, verb, *min, unit),
+		})
+	}
+	if max != nil {
+		result = append(result, Validation{
+			Check: fmt.Sprintf("%s <= %d", length, *max),
+			Error: fmt.Sprintf("%s must %s at most %d %s", name, verb, *max, unit),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,67 @@
+package elm
+
+import (
+	"text/template"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// WrapperType - defines an opaque Elm custom type for a PB message with a
+// single field, used in place of a type alias so that values of different
+// messages (e.g. IDs) can't be mixed up
+type WrapperType struct {
+	Name         Type
+	Type         Type
+	Decoder      VariableName
+	Encoder      VariableName
+	FieldDecoder VariableName
+	FieldEncoder VariableName
+	Default      string
+	// Index is the javascript array index of the wrapped field.
+	Index int
+}
+
+// RegisterWrapperType - resolves fields of PB type pbType to the Elm wrapper
+// type name in this scope, the same way well known types are resolved
+func (s *Scope) RegisterWrapperType(pbType string, name Type) {
+	s.SetWellKnownType(pbType, WellKnownType{
+		Type:    name,
+		Decoder: DecoderName(name, *s),
+		Encoder: EncoderName(name, *s),
+	})
+}
+
+// NewWrapperType - wrapper type named name for a message's only field.  zero
+// is the value used when the field is absent.
+func NewWrapperType(name Type, field *descriptorpb.FieldDescriptorProto, zero string, s Scope) WrapperType {
+	return WrapperType{
+		Name:         name,
+		Type:         BasicFieldType(field, s),
+		Decoder:      DecoderName(name, s),
+		Encoder:      EncoderName(name, s),
+		FieldDecoder: BasicFieldDecoder(field, s),
+		FieldEncoder: BasicFieldEncoder(field, s),
+		Default:      zero,
+		Index:        jsIdx(FieldNum(field)),
+	}
+}
+
+// WrapperTypeTemplate - defines template for a wrapper type
+func WrapperTypeTemplate(t *template.Template) (*template.Template, error) {
+	return t.Parse(`
+{{- define "wrapper-type" -}}
+type {{ .Name }}
+    = {{ .Name }} {{ .Type }}
+
+
+{{ .Decoder }} : JD.Decoder {{ .Name }}
+{{ .Decoder }} =
+    JD.map {{ .Name }} (JD.oneOf [ JD.index {{ .Index }} {{ .FieldDecoder }}, JD.succeed {{ .Default }} ])
+
+
+{{ .Encoder }} : {{ .Name }} -> JE.Value
+{{ .Encoder }} ({{ .Name }} v) =
+    valueList {{ if .Index }}(List.repeat {{ .Index }} JE.null ++ [ {{ .FieldEncoder }} v ]){{ else }}[ {{ .FieldEncoder }} v ]{{ end }}
+{{- end -}}
+`)
+}
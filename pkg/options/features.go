@@ -0,0 +1,138 @@
+package options
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Editions and their features are newer than the descriptorpb package this
+// program is built with, so they are read from unknown fields using the field
+// numbers from google/protobuf/descriptor.proto.
+const (
+	fileEditionField     protowire.Number = 14
+	fileFeaturesField    protowire.Number = 50
+	fieldFeaturesField   protowire.Number = 21
+	fieldPresenceFeature protowire.Number = 1
+)
+
+// Edition - value of the Edition enum from descriptor.proto
+type Edition int32
+
+const (
+	EditionProto2 Edition = 998
+	EditionProto3 Edition = 999
+	Edition2023   Edition = 1000
+)
+
+// Presence - value of the field_presence feature
+type Presence int32
+
+const (
+	PresenceUnknown        Presence = 0
+	PresenceExplicit       Presence = 1
+	PresenceImplicit       Presence = 2
+	PresenceLegacyRequired Presence = 3
+)
+
+// FileEdition - the edition of a file using `edition = "..."` syntax, or false
+// for proto2 and proto3 files
+func FileEdition(file *descriptorpb.FileDescriptorProto) (Edition, bool) {
+	if file.GetSyntax() != "editions" {
+		return 0, false
+	}
+
+	v, ok := unknownVarint(file.ProtoReflect().GetUnknown(), fileEditionField)
+	return Edition(v), ok
+}
+
+// DefaultPresence - field presence of fields that don't set the feature, before
+// any file level override
+func DefaultPresence(edition Edition) Presence {
+	if edition == EditionProto3 {
+		return PresenceImplicit
+	}
+
+	return PresenceExplicit
+}
+
+// FilePresence - the file's field_presence feature, or inherited when unset
+func FilePresence(opts *descriptorpb.FileOptions, inherited Presence) Presence {
+	return presence(opts, fileFeaturesField, inherited)
+}
+
+// FieldPresence - the field's field_presence feature, or inherited when unset
+func FieldPresence(opts *descriptorpb.FieldOptions, inherited Presence) Presence {
+	return presence(opts, fieldFeaturesField, inherited)
+}
+
+func presence(opts proto.Message, featuresField protowire.Number, inherited Presence) Presence {
+	if opts == nil || !opts.ProtoReflect().IsValid() {
+		return inherited
+	}
+
+	// Repeated occurrences of a message field are merged, so the features
+	// can be concatenated and the last field_presence wins.
+	var features []byte
+	b := opts.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		v, n := consumeUnknown(b, featuresField, protowire.BytesType)
+		if n < 0 {
+			break
+		}
+		features = append(features, v...)
+		b = b[n:]
+	}
+
+	v, ok := unknownVarint(features, fieldPresenceFeature)
+	if !ok || Presence(v) == PresenceUnknown {
+		return inherited
+	}
+
+	return Presence(v)
+}
+
+// unknownVarint - the last value of the varint field num in b
+func unknownVarint(b []byte, num protowire.Number) (uint64, bool) {
+	var result uint64
+	var found bool
+	for len(b) > 0 {
+		v, n := consumeUnknown(b, num, protowire.VarintType)
+		if n < 0 {
+			break
+		}
+		if v != nil {
+			result, _ = protowire.ConsumeVarint(v)
+			found = true
+		}
+		b = b[n:]
+	}
+
+	return result, found
+}
+
+// consumeUnknown consumes the field at the start of b, returning its value
+// bytes when it is field num of type typ (nil otherwise) and the number of
+// bytes consumed, which is negative when b is malformed.
+func consumeUnknown(b []byte, num protowire.Number, typ protowire.Type) ([]byte, int) {
+	fieldNum, fieldType, n := protowire.ConsumeTag(b)
+	if n < 0 {
+		return nil, n
+	}
+
+	m := protowire.ConsumeFieldValue(fieldNum, fieldType, b[n:])
+	if m < 0 {
+		return nil, m
+	}
+
+	if fieldNum != num || fieldType != typ {
+		return nil, n + m
+	}
+
+	value := b[n : n+m]
+	if typ == protowire.BytesType {
+		value, _ = protowire.ConsumeBytes(value)
+	}
+
+	return value, n + m
+}
@@ -0,0 +1,141 @@
+// Package options registers the custom protobuf options declared in
+// proto/elm/options.proto so that they are decoded along with the
+// CodeGeneratorRequest and can be read with proto.GetExtension.
+package options
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// File - import path of the options definition, as seen by protoc
+const File = "elm/options.proto"
+
+var (
+	// E_FieldName - (elm.field_name) overrides the generated Elm record field name
+	E_FieldName protoreflect.ExtensionType
+	// E_Default - (elm.default) overrides the Elm default value of a field
+	E_Default protoreflect.ExtensionType
+	// E_ModulePrefix - (elm.module_prefix) overrides the module-prefix
+	// parameter for a file
+	E_ModulePrefix protoreflect.ExtensionType
+)
+
+var fileDescriptor = &descriptorpb.FileDescriptorProto{
+	Name:       proto.String(File),
+	Package:    proto.String("elm"),
+	Dependency: []string{"google/protobuf/descriptor.proto"},
+	Extension: []*descriptorpb.FieldDescriptorProto{
+		{
+			Name:     proto.String("field_name"),
+			JsonName: proto.String("fieldName"),
+			Number:   proto.Int32(50001),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Extendee: proto.String(".google.protobuf.FieldOptions"),
+		},
+		{
+			Name:     proto.String("default"),
+			JsonName: proto.String("default"),
+			Number:   proto.Int32(50002),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Extendee: proto.String(".google.protobuf.FieldOptions"),
+		},
+		{
+			Name:     proto.String("module_prefix"),
+			JsonName: proto.String("modulePrefix"),
+			Number:   proto.Int32(50003),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Extendee: proto.String(".google.protobuf.FileOptions"),
+		},
+	},
+}
+
+func init() {
+	fd, err := protodesc.NewFile(fileDescriptor, protoregistry.GlobalFiles)
+	if err != nil {
+		panic(err)
+	}
+	if err := protoregistry.GlobalFiles.RegisterFile(fd); err != nil {
+		panic(err)
+	}
+
+	E_FieldName = register(fd, "field_name")
+	E_Default = register(fd, "default")
+	E_ModulePrefix = register(fd, "module_prefix")
+}
+
+func register(fd protoreflect.FileDescriptor, name protoreflect.Name) protoreflect.ExtensionType {
+	xt := dynamicpb.NewExtensionType(fd.Extensions().ByName(name))
+	if err := protoregistry.GlobalTypes.RegisterExtension(xt); err != nil {
+		panic(err)
+	}
+
+	return xt
+}
+
+// FieldName - the (elm.field_name) value for a field, if one was set
+func FieldName(opts *descriptorpb.FieldOptions) (string, bool) {
+	return stringOption(opts, E_FieldName)
+}
+
+// Default - the (elm.default) value for a field, if one was set
+func Default(opts *descriptorpb.FieldOptions) (string, bool) {
+	return stringOption(opts, E_Default)
+}
+
+// ModulePrefix - the (elm.module_prefix) value for a file, if one was set
+func ModulePrefix(opts *descriptorpb.FileOptions) (string, bool) {
+	return stringOption(opts, E_ModulePrefix)
+}
+
+func stringOption(opts proto.Message, xt protoreflect.ExtensionType) (string, bool) {
+	if opts == nil || !opts.ProtoReflect().IsValid() || !proto.HasExtension(opts, xt) {
+		return "", false
+	}
+
+	v, ok := proto.GetExtension(opts, xt).(string)
+	return v, ok && v != ""
+}
+
+// Rename - the value of the string extension of FieldOptions numbered number,
+// from any vendor, which overrides generated Elm record field names (see the
+// rename-option parameter).  The extension is usually not registered with
+// this program, so it is read from the options' unknown fields.
+func Rename(opts *descriptorpb.FieldOptions, number protowire.Number) (string, bool) {
+	if number == 0 || opts == nil {
+		return "", false
+	}
+
+	var result string
+	var found bool
+	opts.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsExtension() && fd.Number() == number && fd.Kind() == protoreflect.StringKind {
+			result, found = v.String(), true
+			return false
+		}
+		return true
+	})
+
+	b := opts.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		v, n := consumeUnknown(b, number, protowire.BytesType)
+		if n < 0 {
+			break
+		}
+		if v != nil {
+			// The last occurrence of a field wins.
+			result, found = string(v), true
+		}
+		b = b[n:]
+	}
+
+	return result, found && result != ""
+}
@@ -0,0 +1,257 @@
+package options
+
+import (
+	"math"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ValidateFile - import path of the protoc-gen-validate rules, as seen by protoc
+const ValidateFile = "validate/validate.proto"
+
+// Field numbers from protoc-gen-validate's validate/validate.proto.  The
+// extension isn't registered with this program, so (validate.rules) is read
+// from the options' unknown fields.
+const (
+	validateRulesField protowire.Number = 1071
+
+	floatRulesField    protowire.Number = 1
+	doubleRulesField   protowire.Number = 2
+	sfixed64RulesField protowire.Number = 12
+	stringRulesField   protowire.Number = 14
+	messageRulesField  protowire.Number = 17
+	repeatedRulesField protowire.Number = 18
+
+	numericConstField protowire.Number = 1
+	numericLtField    protowire.Number = 2
+	numericLteField   protowire.Number = 3
+	numericGtField    protowire.Number = 4
+	numericGteField   protowire.Number = 5
+
+	stringLenField    protowire.Number = 19
+	stringMinLenField protowire.Number = 2
+	stringMaxLenField protowire.Number = 3
+
+	messageRequiredField protowire.Number = 2
+
+	repeatedMinItemsField protowire.Number = 1
+	repeatedMaxItemsField protowire.Number = 2
+)
+
+// Comparison - operator of a numeric bound, as written in Elm
+type Comparison string
+
+const (
+	Equal          Comparison = "=="
+	LessThan       Comparison = "<"
+	LessOrEqual    Comparison = "<="
+	GreaterThan    Comparison = ">"
+	GreaterOrEqual Comparison = ">="
+)
+
+// Bound - a numeric constraint, with the value as a decimal literal
+type Bound struct {
+	Comparison Comparison
+	Value      string
+}
+
+// ValidateRules - the subset of (validate.rules) that validators are generated
+// for.  Limits are nil when unset.
+type ValidateRules struct {
+	Required bool
+	MinLen   *uint64
+	MaxLen   *uint64
+	MinItems *uint64
+	MaxItems *uint64
+	Bounds   []Bound
+	// FloatBounds is set when Bounds come from float or double rules.
+	FloatBounds bool
+	// Unsupported names the rule kinds that were set but aren't checked.
+	Unsupported []string
+}
+
+// numericWire - how each numeric rules message encodes its values
+var numericWire = map[protowire.Number]struct {
+	name   string
+	typ    protowire.Type
+	signed bool
+	zigzag bool
+}{
+	1:  {"float", protowire.Fixed32Type, true, false},
+	2:  {"double", protowire.Fixed64Type, true, false},
+	3:  {"int32", protowire.VarintType, true, false},
+	4:  {"int64", protowire.VarintType, true, false},
+	5:  {"uint32", protowire.VarintType, false, false},
+	6:  {"uint64", protowire.VarintType, false, false},
+	7:  {"sint32", protowire.VarintType, true, true},
+	8:  {"sint64", protowire.VarintType, true, true},
+	9:  {"fixed32", protowire.Fixed32Type, false, false},
+	10: {"fixed64", protowire.Fixed64Type, false, false},
+	11: {"sfixed32", protowire.Fixed32Type, true, false},
+	12: {"sfixed64", protowire.Fixed64Type, true, false},
+}
+
+// unsupportedRules - names of the FieldRules kinds that aren't checked
+var unsupportedRules = map[protowire.Number]string{
+	13: "bool",
+	15: "bytes",
+	16: "enum",
+	19: "map",
+	20: "any",
+	21: "duration",
+	22: "timestamp",
+}
+
+// Validate - the (validate.rules) set on a field, if any
+func Validate(opts *descriptorpb.FieldOptions) (ValidateRules, bool) {
+	if opts == nil || !opts.ProtoReflect().IsValid() {
+		return ValidateRules{}, false
+	}
+
+	// Repeated occurrences of a message field are merged, so they can be
+	// concatenated and the last value of each rule wins.
+	var rules []byte
+	found := false
+	b := opts.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		v, n := consumeUnknown(b, validateRulesField, protowire.BytesType)
+		if n < 0 {
+			break
+		}
+		if v != nil {
+			rules = append(rules, v...)
+			found = true
+		}
+		b = b[n:]
+	}
+	if !found {
+		return ValidateRules{}, false
+	}
+
+	// The rules messages are merged the same way, so each one is read after
+	// all of its occurrences are concatenated.
+	var order []protowire.Number
+	messages := map[protowire.Number][]byte{}
+	for len(rules) > 0 {
+		num, typ, n := protowire.ConsumeTag(rules)
+		if n < 0 {
+			break
+		}
+		m := protowire.ConsumeFieldValue(num, typ, rules[n:])
+		if m < 0 {
+			break
+		}
+		value := rules[n : n+m]
+		rules = rules[n+m:]
+		if typ != protowire.BytesType {
+			continue
+		}
+		value, _ = protowire.ConsumeBytes(value)
+		if _, ok := messages[num]; !ok {
+			order = append(order, num)
+		}
+		messages[num] = append(messages[num], value...)
+	}
+
+	var result ValidateRules
+	for _, num := range order {
+		value := messages[num]
+		switch {
+		case num == messageRulesField:
+			if v, ok := unknownVarint(value, messageRequiredField); ok {
+				result.Required = v != 0
+			}
+		case num == stringRulesField:
+			if v, ok := unknownVarint(value, stringLenField); ok {
+				result.MinLen, result.MaxLen = &v, &v
+			}
+			if v, ok := unknownVarint(value, stringMinLenField); ok {
+				result.MinLen = &v
+			}
+			if v, ok := unknownVarint(value, stringMaxLenField); ok {
+				result.MaxLen = &v
+			}
+		case num == repeatedRulesField:
+			if v, ok := unknownVarint(value, repeatedMinItemsField); ok {
+				result.MinItems = &v
+			}
+			if v, ok := unknownVarint(value, repeatedMaxItemsField); ok {
+				result.MaxItems = &v
+			}
+		case num >= floatRulesField && num <= sfixed64RulesField:
+			result.Bounds = numericBounds(value, num)
+			result.FloatBounds = num == floatRulesField || num == doubleRulesField
+		default:
+			name, ok := unsupportedRules[num]
+			if !ok {
+				name = strconv.Itoa(int(num))
+			}
+			result.Unsupported = append(result.Unsupported, name)
+		}
+	}
+
+	return result, true
+}
+
+// numericBounds reads the const, lt, lte, gt and gte rules of the numeric rules
+// message b, which is field num of FieldRules.
+func numericBounds(b []byte, num protowire.Number) []Bound {
+	wire := numericWire[num]
+	var result []Bound
+	for _, rule := range []struct {
+		field      protowire.Number
+		comparison Comparison
+	}{
+		{numericConstField, Equal},
+		{numericGtField, GreaterThan},
+		{numericGteField, GreaterOrEqual},
+		{numericLtField, LessThan},
+		{numericLteField, LessOrEqual},
+	} {
+		var raw uint64
+		var found bool
+		for rest := b; len(rest) > 0; {
+			v, n := consumeUnknown(rest, rule.field, wire.typ)
+			if n < 0 {
+				break
+			}
+			if v != nil {
+				switch wire.typ {
+				case protowire.Fixed32Type:
+					r, _ := protowire.ConsumeFixed32(v)
+					raw = uint64(r)
+				case protowire.Fixed64Type:
+					raw, _ = protowire.ConsumeFixed64(v)
+				default:
+					raw, _ = protowire.ConsumeVarint(v)
+				}
+				found = true
+			}
+			rest = rest[n:]
+		}
+		if !found {
+			continue
+		}
+
+		var value string
+		switch {
+		case num == floatRulesField:
+			value = strconv.FormatFloat(float64(math.Float32frombits(uint32(raw))), 'f', -1, 32)
+		case num == doubleRulesField:
+			value = strconv.FormatFloat(math.Float64frombits(raw), 'f', -1, 64)
+		case wire.zigzag:
+			value = strconv.FormatInt(protowire.DecodeZigZag(raw), 10)
+		case wire.name == "int32" || wire.name == "sfixed32":
+			value = strconv.FormatInt(int64(int32(raw)), 10)
+		case wire.signed:
+			value = strconv.FormatInt(int64(raw), 10)
+		default:
+			value = strconv.FormatUint(raw, 10)
+		}
+		result = append(result, Bound{Comparison: rule.comparison, Value: value})
+	}
+
+	return result
+}
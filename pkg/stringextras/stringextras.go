@@ -2,6 +2,7 @@ package stringextras
 
 import (
 	"strings"
+	"unicode"
 
 	"github.com/gogo/protobuf/protoc-gen-gogo/generator"
 )
@@ -10,8 +11,41 @@ func UpperCamelCase(in string) string {
 	return FirstUpper(CamelCase(in))
 }
 
+// LowerCamelCase converts a name to camelCase.  A leading acronym is lowered
+// as a whole, so `HTTP_status` and `HTTPStatus` become `httpStatus` rather than
+// `hTTPStatus`.
 func LowerCamelCase(in string) string {
-	return FirstLower(CamelCase(in))
+	out := []rune(CamelCase(in))
+	for i := 0; i < len(out) && unicode.IsUpper(out[i]); i++ {
+		// The last capital before a lowercase letter starts the next word.
+		if i > 0 && i+1 < len(out) && unicode.IsLower(out[i+1]) {
+			break
+		}
+		out[i] = unicode.ToLower(out[i])
+	}
+
+	return string(out)
+}
+
+// JSONName converts a field name to the JSON name protoc gives it: underscores
+// are removed and the letter after each one is capitalized, but nothing is
+// lowered, so `HTTP_status` becomes `HTTPStatus`.
+func JSONName(in string) string {
+	var out []rune
+	upper := false
+	for _, r := range in {
+		switch {
+		case r == '_':
+			upper = true
+		case upper:
+			out = append(out, unicode.ToUpper(r))
+			upper = false
+		default:
+			out = append(out, r)
+		}
+	}
+
+	return string(out)
 }
 
 func CamelCase(in string) string {
@@ -19,6 +53,23 @@ func CamelCase(in string) string {
 	return strings.Replace(generator.CamelCase(in), "_", "", -1)
 }
 
+// ScreamingSnakeCase converts a CamelCase name into SCREAMING_SNAKE_CASE, e.g.
+// `FooBar` into `FOO_BAR`.
+func ScreamingSnakeCase(in string) string {
+	var out []rune
+	runes := []rune(in)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && runes[i-1] != '_' &&
+			(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			out = append(out, '_')
+		}
+		out = append(out, unicode.ToUpper(r))
+	}
+
+	return string(out)
+}
+
 func FirstUpper(in string) string {
 	if len(in) < 2 {
 		return strings.ToUpper(in)
@@ -34,3 +85,73 @@ func FirstLower(in string) string {
 
 	return strings.ToLower(string(in[0])) + string(in[1:])
 }
+
+// CUnescape decodes a C-escaped string, as produced by protoc for bytes
+// default values.  Unrecognized escapes are kept as-is.
+func CUnescape(in string) []byte {
+	var out []byte
+	for i := 0; i < len(in); i++ {
+		if in[i] != '\\' || i+1 == len(in) {
+			out = append(out, in[i])
+			continue
+		}
+
+		i++
+		switch c := in[i]; c {
+		case 'a':
+			out = append(out, '\a')
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case 'v':
+			out = append(out, '\v')
+		case 'x', 'X':
+			var v byte
+			j := i + 1
+			for ; j < len(in) && j < i+3 && isHexDigit(in[j]); j++ {
+				v = v<<4 | hexValue(in[j])
+			}
+			if j == i+1 {
+				out = append(out, '\\', c)
+				continue
+			}
+			out = append(out, v)
+			i = j - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			var v byte
+			j := i
+			for ; j < len(in) && j < i+3 && in[j] >= '0' && in[j] <= '7'; j++ {
+				v = v<<3 | (in[j] - '0')
+			}
+			out = append(out, v)
+			i = j - 1
+		default:
+			// \\, \', \" and \? all decode to the escaped character.
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexValue(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	default:
+		return c - '0'
+	}
+}
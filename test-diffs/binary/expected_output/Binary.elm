@@ -0,0 +1,193 @@
+module Binary exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: binary.proto
+
+import Protobuf exposing (..)
+
+import Bytes
+import Bytes.Decode as BD
+import Bytes.Encode as BE
+import Protobuf.Decode as Decode
+import Protobuf.Encode as Encode
+import Dict
+
+
+bytesDecoder : Decode.Decoder Bytes
+bytesDecoder =
+    Decode.map bytesToList Decode.bytes
+
+
+bytesEncoder : Bytes -> Encode.Encoder
+bytesEncoder v =
+    Encode.bytes (BE.encode (BE.sequence (List.map BE.unsignedInt8 v)))
+
+
+bytesToList : Bytes.Bytes -> Bytes
+bytesToList b =
+    let
+        step ( n, acc ) =
+            if n <= 0 then
+                BD.succeed (BD.Done (List.reverse acc))
+
+            else
+                BD.map (\x -> BD.Loop ( n - 1, x :: acc )) BD.unsignedInt8
+    in
+    BD.decode (BD.loop ( Bytes.width b, [] ) step) b
+        |> Maybe.withDefault []
+
+
+type Status
+    = StatusUnknown -- 0
+    | StatusActive -- 1
+
+
+statusDecoder : Decode.Decoder Status
+statusDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    StatusUnknown
+
+                1 ->
+                    StatusActive
+
+                _ ->
+                    StatusUnknown
+    in
+        Decode.map lookup Decode.int32
+
+
+statusDefault : Status
+statusDefault = StatusUnknown
+
+
+statusEncoder : Status -> Encode.Encoder
+statusEncoder v =
+    let
+        lookup s =
+            case s of
+                StatusUnknown ->
+                    0
+
+                StatusActive ->
+                    1
+
+    in
+        Encode.int32 <| lookup v
+
+
+type alias Tree =
+    { name : String -- 1
+    , height : Int -- 2
+    , status : Status -- 3
+    , children : List Tree -- 4
+    , grafts : Dict.Dict String Tree -- 5
+    , nickname : Maybe String -- 6
+    , data : Bytes -- 8
+    , kind : Tree_Kind
+    }
+
+
+defaultTree : Tree
+defaultTree =
+  {name = ""
+  , height = 0
+  , status = statusDefault
+  , children = []
+  , grafts = Dict.empty
+  , nickname = Nothing
+  , data = []
+  , kind = Tree_KindUnspecified
+  }
+
+
+-- treeDecoder is used to decode protobuf messages from the binary wire format.
+treeDecoder : Decode.Decoder Tree
+treeDecoder =
+    Decode.lazy <| \_ -> Decode.message defaultTree
+        [ Decode.optional 1 Decode.string (\a r -> { r | name = a })
+        , Decode.optional 2 Decode.sint32 (\a r -> { r | height = a })
+        , Decode.optional 3 statusDecoder (\a r -> { r | status = a })
+        , Decode.repeated 4 treeDecoder .children (\a r -> { r | children = a })
+        , Decode.mapped 5 ( "", defaultTree ) Decode.string treeDecoder .grafts (\a r -> { r | grafts = a })
+        , Decode.optional 6 (Decode.map Just (Decode.message "" [ Decode.optional 1 Decode.string always ])) (\a r -> { r | nickname = a })
+        , Decode.optional 8 bytesDecoder (\a r -> { r | data = a })
+        , Decode.oneOf tree_KindDecoder (\a r -> { r | kind = Maybe.withDefault Tree_KindUnspecified a })
+        ]
+
+
+-- treeEncoder is used to encode protobuf messages to the binary wire format.
+treeEncoder : Tree -> Encode.Encoder
+treeEncoder v =
+    Encode.message
+        [ ( 1, Encode.string v.name )
+        , ( 2, Encode.sint32 v.height )
+        , ( 3, statusEncoder v.status )
+        , ( 4, Encode.list treeEncoder v.children )
+        , ( 5, Encode.dict Encode.string treeEncoder v.grafts )
+        , ( 6, Maybe.withDefault Encode.none (Maybe.map (\x -> Encode.message [ ( 1, Encode.string x ) ]) v.nickname) )
+        , ( 8, bytesEncoder v.data )
+        , ( 9, tree_KindEncoder 9 v.kind )
+        , ( 10, tree_KindEncoder 10 v.kind )
+        ]
+
+
+type Tree_Kind
+    = Tree_KindUnspecified
+    | Tree_Age Int -- 9
+    | Tree_Species String -- 10
+
+
+tree_KindDecoder : List ( Int, Decode.Decoder Tree_Kind )
+tree_KindDecoder =
+    [ ( 9, Decode.map Tree_Age Decode.int32 )
+    , ( 10, Decode.map Tree_Species Decode.string )
+    ]
+
+
+tree_KindEncoder : Int -> Tree_Kind -> Encode.Encoder
+tree_KindEncoder idx v =
+    case v of
+        Tree_KindUnspecified ->
+            Encode.none
+
+        Tree_Age x ->
+            if idx == 9 then Encode.int32 x else Encode.none
+
+        Tree_Species x ->
+            if idx == 10 then Encode.string x else Encode.none
+
+
+type alias Tree_GraftsEntry =
+    { key : String -- 1
+    , value : Maybe Tree -- 2
+    }
+
+
+defaultTree_GraftsEntry : Tree_GraftsEntry
+defaultTree_GraftsEntry =
+  {key = ""
+  , value = Nothing
+  }
+
+
+-- tree_GraftsEntryDecoder is used to decode protobuf messages from the binary wire format.
+tree_GraftsEntryDecoder : Decode.Decoder Tree_GraftsEntry
+tree_GraftsEntryDecoder =
+    Decode.lazy <| \_ -> Decode.message defaultTree_GraftsEntry
+        [ Decode.optional 1 Decode.string (\a r -> { r | key = a })
+        , Decode.optional 2 (Decode.map Just treeDecoder) (\a r -> { r | value = a })
+        ]
+
+
+-- tree_GraftsEntryEncoder is used to encode protobuf messages to the binary wire format.
+tree_GraftsEntryEncoder : Tree_GraftsEntry -> Encode.Encoder
+tree_GraftsEntryEncoder v =
+    Encode.message
+        [ ( 1, Encode.string v.key )
+        , ( 2, Maybe.withDefault Encode.none (Maybe.map treeEncoder v.value) )
+        ]
@@ -0,0 +1,268 @@
+module Builders exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: builders.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Person =
+    { name : String -- 1
+    , age : Int -- 2
+    , nickname : Maybe String -- 3
+    , addresses : List Person_Address -- 4
+    , type_ : String -- 5
+    , contact : Person_Contact
+    }
+
+
+defaultPerson : Person
+defaultPerson =
+  {name = ""
+  , age = 0
+  , nickname = Nothing
+  , addresses = []
+  , type_ = ""
+  , contact = Person_ContactUnspecified
+  }
+
+
+-- personPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+personPortDecoder : JD.Decoder Person
+personPortDecoder =
+    JD.lazy <| \_ -> decode Person
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+        |> maybeIdx 2 JD.string
+        |> idxWithDefault 3 (JD.list person_AddressPortDecoder) []
+        |> idxWithDefault 4 JD.string ""
+        |> custom person_ContactPortDecoder
+
+
+-- personPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+personPortEncoder : Person -> JE.Value
+personPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        , (JE.int v.age)
+        , (maybeEncoder JE.string v.nickname)
+        , (JE.list person_AddressPortEncoder v.addresses)
+        , (JE.string v.type_)
+        , (person_ContactPortEncoder 6 v.contact)
+        , (person_ContactPortEncoder 7 v.contact)
+        ]
+
+
+withPersonName : String -> Person -> Person
+withPersonName value v =
+    { v | name = value }
+
+
+withPersonAge : Int -> Person -> Person
+withPersonAge value v =
+    { v | age = value }
+
+
+withPersonNickname : Maybe String -> Person -> Person
+withPersonNickname value v =
+    { v | nickname = value }
+
+
+withPersonAddresses : List Person_Address -> Person -> Person
+withPersonAddresses value v =
+    { v | addresses = value }
+
+
+withPersonType_ : String -> Person -> Person
+withPersonType_ value v =
+    { v | type_ = value }
+
+
+withPersonContact : Person_Contact -> Person -> Person
+withPersonContact value v =
+    { v | contact = value }
+
+
+type Person_Contact
+    = Person_ContactUnspecified
+    | Person_Email String -- 6
+    | Person_Phone String -- 7
+
+
+person_ContactPortDecoder : JD.Decoder Person_Contact
+person_ContactPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Person_Email (JD.index 5 (failOnNull JD.string))
+        , JD.map Person_Phone (JD.index 6 (failOnNull JD.string))
+        , JD.succeed Person_ContactUnspecified
+        ]
+
+
+person_ContactPortEncoder : Int -> Person_Contact -> JE.Value
+person_ContactPortEncoder idx v =
+    case v of
+        Person_ContactUnspecified ->
+            JE.null
+
+        Person_Email x ->
+            if idx == 6 then JE.string x else JE.null
+
+        Person_Phone x ->
+            if idx == 7 then JE.string x else JE.null
+
+
+type alias Person_Address =
+    { street : String -- 1
+    }
+
+
+defaultPerson_Address : Person_Address
+defaultPerson_Address =
+  {street = ""
+  }
+
+
+-- person_AddressPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+person_AddressPortDecoder : JD.Decoder Person_Address
+person_AddressPortDecoder =
+    JD.lazy <| \_ -> decode Person_Address
+        |> idxWithDefault 0 JD.string ""
+
+
+-- person_AddressPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+person_AddressPortEncoder : Person_Address -> JE.Value
+person_AddressPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.street)
+        ]
+
+
+withPerson_AddressStreet : String -> Person_Address -> Person_Address
+withPerson_AddressStreet value v =
+    { v | street = value }
+
+
+type alias Empty =
+    { }
+
+
+defaultEmpty : Empty
+defaultEmpty =
+  {
+  }
+
+
+-- emptyPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+emptyPortDecoder : JD.Decoder Empty
+emptyPortDecoder =
+    JD.lazy <| \_ -> decode Empty
+
+
+-- emptyPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+emptyPortEncoder : Empty -> JE.Value
+emptyPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ 
+        ]
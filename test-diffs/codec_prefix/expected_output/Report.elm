@@ -0,0 +1,204 @@
+module Report exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: report.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+import Shared exposing (..)
+
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Report =
+    { level : Level -- 1
+    , tags : List Tag -- 2
+    , tagsByName : Dict.Dict String Tag -- 3
+    , subject : Report_Subject
+    }
+
+
+defaultReport : Report
+defaultReport =
+  {level = Shared.levelDefault
+  , tags = []
+  , tagsByName = Dict.empty
+  , subject = Report_SubjectUnspecified
+  }
+
+
+-- pbReportPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+pbReportPortDecoder : JD.Decoder Report
+pbReportPortDecoder =
+    JD.lazy <| \_ -> decode Report
+        |> idxWithDefault 0 pbLevelPortDecoder Shared.levelDefault
+        |> idxWithDefault 1 (JD.list pbTagPortDecoder) []
+        |> mapEntries 2 JD.string pbTagPortDecoder
+        |> custom pbReport_SubjectPortDecoder
+
+
+-- pbReportPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+pbReportPortEncoder : Report -> JE.Value
+pbReportPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (pbLevelPortEncoder v.level)
+        , (JE.list pbTagPortEncoder v.tags)
+        , (mapEntriesFieldEncoder JE.string pbTagPortEncoder v.tagsByName)
+        , (pbReport_SubjectPortEncoder 4 v.subject)
+        , (pbReport_SubjectPortEncoder 5 v.subject)
+        ]
+
+
+type Report_Subject
+    = Report_SubjectUnspecified
+    | Report_User String -- 4
+    | Report_Tag Tag -- 5
+
+
+pbReport_SubjectPortDecoder : JD.Decoder Report_Subject
+pbReport_SubjectPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Report_User (JD.index 3 (failOnNull JD.string))
+        , JD.map Report_Tag (JD.index 4 (failOnNull pbTagPortDecoder))
+        , JD.succeed Report_SubjectUnspecified
+        ]
+
+
+pbReport_SubjectPortEncoder : Int -> Report_Subject -> JE.Value
+pbReport_SubjectPortEncoder idx v =
+    case v of
+        Report_SubjectUnspecified ->
+            JE.null
+
+        Report_User x ->
+            if idx == 4 then JE.string x else JE.null
+
+        Report_Tag x ->
+            if idx == 5 then pbTagPortEncoder x else JE.null
+
+
+type alias Report_TagsByNameEntry =
+    { key : String -- 1
+    , value : Maybe Tag -- 2
+    }
+
+
+defaultReport_TagsByNameEntry : Report_TagsByNameEntry
+defaultReport_TagsByNameEntry =
+  {key = ""
+  , value = Nothing
+  }
+
+
+-- pbReport_TagsByNameEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+pbReport_TagsByNameEntryPortDecoder : JD.Decoder Report_TagsByNameEntry
+pbReport_TagsByNameEntryPortDecoder =
+    JD.lazy <| \_ -> decode Report_TagsByNameEntry
+        |> idxWithDefault 0 JD.string ""
+        |> maybeIdx 1 pbTagPortDecoder
+
+
+-- pbReport_TagsByNameEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+pbReport_TagsByNameEntryPortEncoder : Report_TagsByNameEntry -> JE.Value
+pbReport_TagsByNameEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (maybeEncoder pbTagPortEncoder v.value)
+        ]
@@ -0,0 +1,227 @@
+module Decoder_style_pipeline_json_both exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: decoder_style_pipeline_json_both.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Json.Decode.Pipeline as Pipeline
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- arrayMessage and objectMessage only run a message decoder on a value of
+the matching shape.  Otherwise a decoder for one format would succeed on the
+other, with every field missing and so set to its default.
+-}
+arrayMessage : JD.Decoder a -> JD.Decoder a
+arrayMessage decoder =
+    JD.list JD.value |> JD.andThen (\_ -> decoder)
+
+
+objectMessage : JD.Decoder a -> JD.Decoder a
+objectMessage decoder =
+    JD.keyValuePairs JD.value |> JD.andThen (\_ -> decoder)
+
+
+{- fieldWithDefault is the object format counterpart of idxWithDefault.
+-}
+fieldWithDefault : String -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+fieldWithDefault name decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.field name (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeField is the object format counterpart of maybeIdx.
+-}
+maybeField : String -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeField name decoder =
+    JD.map2 (|>)
+        (JD.maybe (JD.field name JD.value)
+            |> JD.andThen
+                (\value ->
+                    case value of
+                        Just _ ->
+                            JD.field name (JD.nullable decoder)
+
+                        Nothing ->
+                            JD.succeed Nothing
+                )
+        )
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Item =
+    { name : String -- 1
+    , count : Maybe Int -- 2
+    , tags : List String -- 3
+    , choice : Item_Choice
+    }
+
+
+defaultItem : Item
+defaultItem =
+  {name = ""
+  , count = Nothing
+  , tags = []
+  , choice = Item_ChoiceUnspecified
+  }
+
+
+-- itemPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+itemPortDecoder : JD.Decoder Item
+itemPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (JD.succeed Item
+                |> idxWithDefault 0 JD.string ""
+                |> maybeIdx 1 intDecoder
+                |> idxWithDefault 2 (JD.list JD.string) []
+                |> Pipeline.custom item_ChoicePortDecoder
+            )
+        , objectMessage
+            (JD.succeed Item
+                |> Pipeline.optional "name" JD.string ""
+                |> Pipeline.optional "count" (JD.nullable intDecoder) Nothing
+                |> Pipeline.optional "tags" (JD.list JD.string) []
+                |> Pipeline.custom item_ChoiceObjectDecoder
+            )
+        ]
+
+
+-- itemPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+itemPortEncoder : Item -> JE.Value
+itemPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        , (maybeEncoder JE.int v.count)
+        , (JE.list JE.string v.tags)
+        , (item_ChoicePortEncoder 4 v.choice)
+        , (item_ChoicePortEncoder 5 v.choice)
+        ]
+
+
+type Item_Choice
+    = Item_ChoiceUnspecified
+    | Item_Label String -- 4
+    | Item_Code Int -- 5
+
+
+item_ChoicePortDecoder : JD.Decoder Item_Choice
+item_ChoicePortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Item_Label (JD.index 3 (failOnNull JD.string))
+        , JD.map Item_Code (JD.index 4 (failOnNull intDecoder))
+        , JD.succeed Item_ChoiceUnspecified
+        ]
+
+
+item_ChoicePortEncoder : Int -> Item_Choice -> JE.Value
+item_ChoicePortEncoder idx v =
+    case v of
+        Item_ChoiceUnspecified ->
+            JE.null
+
+        Item_Label x ->
+            if idx == 4 then JE.string x else JE.null
+
+        Item_Code x ->
+            if idx == 5 then JE.int x else JE.null
+
+
+item_ChoiceObjectDecoder : JD.Decoder Item_Choice
+item_ChoiceObjectDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Item_Label (JD.field "label" (failOnNull JD.string))
+        , JD.map Item_Code (JD.field "code" (failOnNull intDecoder))
+        , JD.succeed Item_ChoiceUnspecified
+        ]
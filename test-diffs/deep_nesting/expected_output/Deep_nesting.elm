@@ -0,0 +1,291 @@
+module Deep_nesting exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: deep_nesting.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Outer =
+    { middle : Maybe Outer_Middle -- 1
+    , deepest : Maybe Outer220a1364_Deepest -- 2
+    }
+
+
+defaultOuter : Outer
+defaultOuter =
+  {middle = Nothing
+  , deepest = Nothing
+  }
+
+
+-- outerPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outerPortDecoder : JD.Decoder Outer
+outerPortDecoder =
+    JD.lazy <| \_ -> decode Outer
+        |> maybeIdx 0 outer_MiddlePortDecoder
+        |> maybeIdx 1 outer220a1364_DeepestPortDecoder
+
+
+-- outerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outerPortEncoder : Outer -> JE.Value
+outerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (maybeEncoder outer_MiddlePortEncoder v.middle)
+        , (maybeEncoder outer220a1364_DeepestPortEncoder v.deepest)
+        ]
+
+
+type alias Outer_Middle =
+    { inner : Maybe Outer_Middle_Inner -- 1
+    }
+
+
+defaultOuter_Middle : Outer_Middle
+defaultOuter_Middle =
+  {inner = Nothing
+  }
+
+
+-- outer_MiddlePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outer_MiddlePortDecoder : JD.Decoder Outer_Middle
+outer_MiddlePortDecoder =
+    JD.lazy <| \_ -> decode Outer_Middle
+        |> maybeIdx 0 outer_Middle_InnerPortDecoder
+
+
+-- outer_MiddlePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outer_MiddlePortEncoder : Outer_Middle -> JE.Value
+outer_MiddlePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (maybeEncoder outer_Middle_InnerPortEncoder v.inner)
+        ]
+
+
+type alias Outer_Middle_Inner =
+    { deeper : Maybe Outerc729b349_Deeper -- 1
+    }
+
+
+defaultOuter_Middle_Inner : Outer_Middle_Inner
+defaultOuter_Middle_Inner =
+  {deeper = Nothing
+  }
+
+
+-- outer_Middle_InnerPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outer_Middle_InnerPortDecoder : JD.Decoder Outer_Middle_Inner
+outer_Middle_InnerPortDecoder =
+    JD.lazy <| \_ -> decode Outer_Middle_Inner
+        |> maybeIdx 0 outerc729b349_DeeperPortDecoder
+
+
+-- outer_Middle_InnerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outer_Middle_InnerPortEncoder : Outer_Middle_Inner -> JE.Value
+outer_Middle_InnerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (maybeEncoder outerc729b349_DeeperPortEncoder v.deeper)
+        ]
+
+
+type alias Outerc729b349_Deeper =
+    { deepest : Maybe Outer220a1364_Deepest -- 1
+    }
+
+
+defaultOuterc729b349_Deeper : Outerc729b349_Deeper
+defaultOuterc729b349_Deeper =
+  {deepest = Nothing
+  }
+
+
+-- outerc729b349_DeeperPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outerc729b349_DeeperPortDecoder : JD.Decoder Outerc729b349_Deeper
+outerc729b349_DeeperPortDecoder =
+    JD.lazy <| \_ -> decode Outerc729b349_Deeper
+        |> maybeIdx 0 outer220a1364_DeepestPortDecoder
+
+
+-- outerc729b349_DeeperPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outerc729b349_DeeperPortEncoder : Outerc729b349_Deeper -> JE.Value
+outerc729b349_DeeperPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (maybeEncoder outer220a1364_DeepestPortEncoder v.deepest)
+        ]
+
+
+type alias Outer220a1364_Deepest =
+    { kind : Outerd8578e7c_Kind -- 1
+    }
+
+
+defaultOuter220a1364_Deepest : Outer220a1364_Deepest
+defaultOuter220a1364_Deepest =
+  {kind = outerd8578e7c_KindDefault
+  }
+
+
+-- outer220a1364_DeepestPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outer220a1364_DeepestPortDecoder : JD.Decoder Outer220a1364_Deepest
+outer220a1364_DeepestPortDecoder =
+    JD.lazy <| \_ -> decode Outer220a1364_Deepest
+        |> idxWithDefault 0 outerd8578e7c_KindPortDecoder outerd8578e7c_KindDefault
+
+
+-- outer220a1364_DeepestPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outer220a1364_DeepestPortEncoder : Outer220a1364_Deepest -> JE.Value
+outer220a1364_DeepestPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (outerd8578e7c_KindPortEncoder v.kind)
+        ]
+
+
+type Outerd8578e7c_Kind
+    = Outerd8578e7c_KindUnknown -- 0
+    | Outerd8578e7c_KindDeep -- 1
+
+
+outerd8578e7c_KindPortDecoder : JD.Decoder Outerd8578e7c_Kind
+outerd8578e7c_KindPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    Outerd8578e7c_KindUnknown
+
+                1 ->
+                    Outerd8578e7c_KindDeep
+
+                _ ->
+                    Outerd8578e7c_KindUnknown
+    in
+        JD.map lookup JD.int
+
+
+outerd8578e7c_KindDefault : Outerd8578e7c_Kind
+outerd8578e7c_KindDefault = Outerd8578e7c_KindUnknown
+
+
+outerd8578e7c_KindPortEncoder : Outerd8578e7c_Kind -> JE.Value
+outerd8578e7c_KindPortEncoder v =
+    let
+        lookup s =
+            case s of
+                Outerd8578e7c_KindUnknown ->
+                    0
+
+                Outerd8578e7c_KindDeep ->
+                    1
+
+    in
+        JE.int <| lookup v
@@ -0,0 +1,279 @@
+module Tree exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: tree.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Outer =
+    { }
+
+
+defaultOuter : Outer
+defaultOuter =
+  {
+  }
+
+
+-- outerPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outerPortDecoder : JD.Decoder Outer
+outerPortDecoder =
+    JD.lazy <| \_ -> decode Outer
+
+
+-- outerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outerPortEncoder : Outer -> JE.Value
+outerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ 
+        ]
+
+
+type alias Outer_Middle =
+    { }
+
+
+defaultOuter_Middle : Outer_Middle
+defaultOuter_Middle =
+  {
+  }
+
+
+-- outer_MiddlePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outer_MiddlePortDecoder : JD.Decoder Outer_Middle
+outer_MiddlePortDecoder =
+    JD.lazy <| \_ -> decode Outer_Middle
+
+
+-- outer_MiddlePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outer_MiddlePortEncoder : Outer_Middle -> JE.Value
+outer_MiddlePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ 
+        ]
+
+
+type alias Outer_Middle_Inner =
+    { }
+
+
+defaultOuter_Middle_Inner : Outer_Middle_Inner
+defaultOuter_Middle_Inner =
+  {
+  }
+
+
+-- outer_Middle_InnerPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outer_Middle_InnerPortDecoder : JD.Decoder Outer_Middle_Inner
+outer_Middle_InnerPortDecoder =
+    JD.lazy <| \_ -> decode Outer_Middle_Inner
+
+
+-- outer_Middle_InnerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outer_Middle_InnerPortEncoder : Outer_Middle_Inner -> JE.Value
+outer_Middle_InnerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ 
+        ]
+
+
+type alias Outerc729b349_Deeper =
+    { }
+
+
+defaultOuterc729b349_Deeper : Outerc729b349_Deeper
+defaultOuterc729b349_Deeper =
+  {
+  }
+
+
+-- outerc729b349_DeeperPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outerc729b349_DeeperPortDecoder : JD.Decoder Outerc729b349_Deeper
+outerc729b349_DeeperPortDecoder =
+    JD.lazy <| \_ -> decode Outerc729b349_Deeper
+
+
+-- outerc729b349_DeeperPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outerc729b349_DeeperPortEncoder : Outerc729b349_Deeper -> JE.Value
+outerc729b349_DeeperPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ 
+        ]
+
+
+type alias Outer220a1364_LeafNode =
+    { kind : Outer6dabf0ff_Kind -- 1
+    }
+
+
+defaultOuter220a1364_LeafNode : Outer220a1364_LeafNode
+defaultOuter220a1364_LeafNode =
+  {kind = outer6dabf0ff_KindDefault
+  }
+
+
+-- outer220a1364_LeafNodePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outer220a1364_LeafNodePortDecoder : JD.Decoder Outer220a1364_LeafNode
+outer220a1364_LeafNodePortDecoder =
+    JD.lazy <| \_ -> decode Outer220a1364_LeafNode
+        |> idxWithDefault 0 outer6dabf0ff_KindPortDecoder outer6dabf0ff_KindDefault
+
+
+-- outer220a1364_LeafNodePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outer220a1364_LeafNodePortEncoder : Outer220a1364_LeafNode -> JE.Value
+outer220a1364_LeafNodePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (outer6dabf0ff_KindPortEncoder v.kind)
+        ]
+
+
+type Outer6dabf0ff_Kind
+    = Outer6dabf0ff_KindUnknown -- 0
+    | Outer6dabf0ff_KindDeep -- 1
+
+
+outer6dabf0ff_KindPortDecoder : JD.Decoder Outer6dabf0ff_Kind
+outer6dabf0ff_KindPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    Outer6dabf0ff_KindUnknown
+
+                1 ->
+                    Outer6dabf0ff_KindDeep
+
+                _ ->
+                    Outer6dabf0ff_KindUnknown
+    in
+        JD.map lookup JD.int
+
+
+outer6dabf0ff_KindDefault : Outer6dabf0ff_Kind
+outer6dabf0ff_KindDefault = Outer6dabf0ff_KindUnknown
+
+
+outer6dabf0ff_KindPortEncoder : Outer6dabf0ff_Kind -> JE.Value
+outer6dabf0ff_KindPortEncoder v =
+    let
+        lookup s =
+            case s of
+                Outer6dabf0ff_KindUnknown ->
+                    0
+
+                Outer6dabf0ff_KindDeep ->
+                    1
+
+    in
+        JE.int <| lookup v
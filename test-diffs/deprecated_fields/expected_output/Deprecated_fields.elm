@@ -11,7 +11,89 @@ import Json.Decode as JD
 import Json.Encode as JE
 
 
-uselessDeclarationToPreventErrorDueToEmptyOutputFile = 42
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
 
 
 type EnumBar
@@ -19,40 +101,40 @@ type EnumBar
     | EnumbarValue1 -- 1
 
 
-enumBarDecoder : JD.Decoder EnumBar
-enumBarDecoder =
+enumBarPortDecoder : JD.Decoder EnumBar
+enumBarPortDecoder =
     let
-        lookup s =
-            case s of
-                "ENUMBAR_VALUE_DEFAULT" ->
+        lookup v =
+            case v of
+                0 ->
                     EnumbarValueDefault
 
-                "ENUMBAR_VALUE_1" ->
+                1 ->
                     EnumbarValue1
 
                 _ ->
                     EnumbarValueDefault
     in
-        JD.map lookup JD.string
+        JD.map lookup JD.int
 
 
 enumBarDefault : EnumBar
 enumBarDefault = EnumbarValueDefault
 
 
-enumBarEncoder : EnumBar -> JE.Value
-enumBarEncoder v =
+enumBarPortEncoder : EnumBar -> JE.Value
+enumBarPortEncoder v =
     let
         lookup s =
             case s of
                 EnumbarValueDefault ->
-                    "ENUMBAR_VALUE_DEFAULT"
+                    0
 
                 EnumbarValue1 ->
-                    "ENUMBAR_VALUE_1"
+                    1
 
     in
-        JE.string <| lookup v
+        JE.int <| lookup v
 
 
 type alias Bar =
@@ -60,14 +142,26 @@ type alias Bar =
     }
 
 
-barDecoder : JD.Decoder Bar
-barDecoder =
+defaultBar : Bar
+defaultBar =
+  {field = False
+  }
+
+
+-- barPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+barPortDecoder : JD.Decoder Bar
+barPortDecoder =
     JD.lazy <| \_ -> decode Bar
-        |> required "field" JD.bool False
+        |> idxWithDefault 0 JD.bool False
 
 
-barEncoder : Bar -> JE.Value
-barEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (requiredFieldEncoder "field" JE.bool False v.field)
+-- barPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+barPortEncoder : Bar -> JE.Value
+barPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.bool v.field)
         ]
@@ -0,0 +1,262 @@
+module Elm_codec exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: elm_codec.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Codec exposing (Codec)
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+intCodec : Codec Int
+intCodec =
+    Codec.build JE.int intDecoder
+
+
+int64Codec : Codec Int
+int64Codec =
+    Codec.build numericStringEncoder intDecoder
+
+
+bytesCodec : Codec Bytes
+bytesCodec =
+    Codec.build bytesFieldEncoder bytesFieldDecoder
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type Status
+    = StatusUnknown -- 0
+    | StatusActive -- 1
+
+
+statusCodec : Codec Status
+statusCodec =
+    let
+        fromInt v =
+            case v of
+                0 ->
+                    StatusUnknown
+
+                1 ->
+                    StatusActive
+
+                _ ->
+                    StatusUnknown
+
+        toInt s =
+            case s of
+                StatusUnknown ->
+                    0
+
+                StatusActive ->
+                    1
+
+    in
+        Codec.map fromInt toInt Codec.int
+
+
+statusDefault : Status
+statusDefault = StatusUnknown
+
+
+type alias Tree =
+    { name : String -- 1
+    , size : Int -- 2
+    , status : Status -- 3
+    , children : List Tree -- 4
+    , weights : Dict.Dict String Float -- 5
+    , planted : Maybe Timestamp -- 7
+    , data : Bytes -- 8
+    , kind : Tree_Kind
+    }
+
+
+defaultTree : Tree
+defaultTree =
+  {name = ""
+  , size = 0
+  , status = statusDefault
+  , children = []
+  , weights = Dict.empty
+  , planted = Nothing
+  , data = []
+  , kind = Tree_KindUnspecified
+  }
+
+
+-- treeCodec is used to decode and encode protobuf messages for ports, following the
+-- javascript array format.
+treeCodec : Codec Tree
+treeCodec =
+    Codec.lazy <| \_ -> Codec.build
+        (\v ->
+            valueList
+                [ ((Codec.encoder Codec.string) v.name)
+                , ((Codec.encoder int64Codec) v.size)
+                , ((Codec.encoder statusCodec) v.status)
+                , (JE.list (Codec.encoder treeCodec) v.children)
+                , (mapEntriesFieldEncoder (Codec.encoder Codec.string) (Codec.encoder Codec.float) v.weights)
+                , JE.null
+                , (maybeEncoder (Codec.encoder (Codec.build timestampEncoder timestampDecoder)) v.planted)
+                , ((Codec.encoder bytesCodec) v.data)
+                , (tree_KindPortEncoder 9 v.kind)
+                , (tree_KindPortEncoder 10 v.kind)
+                ]
+        )
+        (decode Tree
+            |> idxWithDefault 0 (Codec.decoder Codec.string) ""
+            |> idxWithDefault 1 (Codec.decoder int64Codec) 0
+            |> idxWithDefault 2 (Codec.decoder statusCodec) statusDefault
+            |> idxWithDefault 3 (JD.list (Codec.decoder treeCodec)) []
+            |> mapEntries 4 (Codec.decoder Codec.string) (Codec.decoder Codec.float)
+            |> maybeIdx 6 (Codec.decoder (Codec.build timestampEncoder timestampDecoder))
+            |> idxWithDefault 7 (Codec.decoder bytesCodec) []
+            |> custom tree_KindPortDecoder
+        )
+
+
+type Tree_Kind
+    = Tree_KindUnspecified
+    | Tree_Age Int -- 9
+    | Tree_Species String -- 10
+
+
+tree_KindPortDecoder : JD.Decoder Tree_Kind
+tree_KindPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Tree_Age (JD.index 8 (failOnNull (Codec.decoder intCodec)))
+        , JD.map Tree_Species (JD.index 9 (failOnNull (Codec.decoder Codec.string)))
+        , JD.succeed Tree_KindUnspecified
+        ]
+
+
+tree_KindPortEncoder : Int -> Tree_Kind -> JE.Value
+tree_KindPortEncoder idx v =
+    case v of
+        Tree_KindUnspecified ->
+            JE.null
+
+        Tree_Age x ->
+            if idx == 9 then (Codec.encoder intCodec) x else JE.null
+
+        Tree_Species x ->
+            if idx == 10 then (Codec.encoder Codec.string) x else JE.null
+
+
+type alias Tree_WeightsEntry =
+    { key : String -- 1
+    , value : Float -- 2
+    }
+
+
+defaultTree_WeightsEntry : Tree_WeightsEntry
+defaultTree_WeightsEntry =
+  {key = ""
+  , value = 0
+  }
+
+
+-- tree_WeightsEntryCodec is used to decode and encode protobuf messages for ports, following the
+-- javascript array format.
+tree_WeightsEntryCodec : Codec Tree_WeightsEntry
+tree_WeightsEntryCodec =
+    Codec.lazy <| \_ -> Codec.build
+        (\v ->
+            valueList
+                [ ((Codec.encoder Codec.string) v.key)
+                , ((Codec.encoder Codec.float) v.value)
+                ]
+        )
+        (decode Tree_WeightsEntry
+            |> idxWithDefault 0 (Codec.decoder Codec.string) ""
+            |> idxWithDefault 1 (Codec.decoder Codec.float) 0
+        )
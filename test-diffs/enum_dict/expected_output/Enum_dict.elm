@@ -0,0 +1,290 @@
+module Enum_dict exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: enum_dict.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type Country
+    = CountryUnspecified -- 0
+    | CountryAr -- 1
+    | CountryBr -- 2
+    | CountryCa -- 3
+    | CountryDe -- 4
+
+
+countryByValue : Dict.Dict Int Country
+countryByValue =
+    Dict.fromList
+        [ ( 0, CountryUnspecified )
+        , ( 1, CountryAr )
+        , ( 2, CountryBr )
+        , ( 3, CountryCa )
+        , ( 4, CountryDe )
+        ]
+
+
+countryPortDecoder : JD.Decoder Country
+countryPortDecoder =
+    let
+        lookup v =
+            Dict.get v countryByValue
+                |> Maybe.withDefault CountryUnspecified
+    in
+        JD.map lookup JD.int
+
+
+countryDefault : Country
+countryDefault = CountryUnspecified
+
+
+countryPortEncoder : Country -> JE.Value
+countryPortEncoder v =
+    let
+        lookup s =
+            case s of
+                CountryUnspecified ->
+                    0
+
+                CountryAr ->
+                    1
+
+                CountryBr ->
+                    2
+
+                CountryCa ->
+                    3
+
+                CountryDe ->
+                    4
+
+    in
+        JE.int <| lookup v
+
+
+type Flag
+    = FlagUnspecified -- 0
+    | FlagOn -- 1
+
+
+flagPortDecoder : JD.Decoder Flag
+flagPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    FlagUnspecified
+
+                1 ->
+                    FlagOn
+
+                _ ->
+                    FlagUnspecified
+    in
+        JD.map lookup JD.int
+
+
+flagDefault : Flag
+flagDefault = FlagUnspecified
+
+
+flagPortEncoder : Flag -> JE.Value
+flagPortEncoder v =
+    let
+        lookup s =
+            case s of
+                FlagUnspecified ->
+                    0
+
+                FlagOn ->
+                    1
+
+    in
+        JE.int <| lookup v
+
+
+type alias Address =
+    { country : Country -- 1
+    , flag : Flag -- 2
+    , kind : Address_Kind -- 3
+    }
+
+
+defaultAddress : Address
+defaultAddress =
+  {country = countryDefault
+  , flag = flagDefault
+  , kind = address_KindDefault
+  }
+
+
+-- addressPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+addressPortDecoder : JD.Decoder Address
+addressPortDecoder =
+    JD.lazy <| \_ -> decode Address
+        |> idxWithDefault 0 countryPortDecoder countryDefault
+        |> idxWithDefault 1 flagPortDecoder flagDefault
+        |> idxWithDefault 2 address_KindPortDecoder address_KindDefault
+
+
+-- addressPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+addressPortEncoder : Address -> JE.Value
+addressPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (countryPortEncoder v.country)
+        , (flagPortEncoder v.flag)
+        , (address_KindPortEncoder v.kind)
+        ]
+
+
+type Address_Kind
+    = Address_KindUnspecified -- 0
+    | Address_KindHome -- 1
+    | Address_KindWork -- 2
+    | Address_KindOther -- 3
+    | Address_KindBilling -- 4
+
+
+address_KindByValue : Dict.Dict Int Address_Kind
+address_KindByValue =
+    Dict.fromList
+        [ ( 0, Address_KindUnspecified )
+        , ( 1, Address_KindHome )
+        , ( 2, Address_KindWork )
+        , ( 3, Address_KindOther )
+        , ( 4, Address_KindBilling )
+        ]
+
+
+address_KindPortDecoder : JD.Decoder Address_Kind
+address_KindPortDecoder =
+    let
+        lookup v =
+            Dict.get v address_KindByValue
+                |> Maybe.withDefault Address_KindUnspecified
+    in
+        JD.map lookup JD.int
+
+
+address_KindDefault : Address_Kind
+address_KindDefault = Address_KindUnspecified
+
+
+address_KindPortEncoder : Address_Kind -> JE.Value
+address_KindPortEncoder v =
+    let
+        lookup s =
+            case s of
+                Address_KindUnspecified ->
+                    0
+
+                Address_KindHome ->
+                    1
+
+                Address_KindWork ->
+                    2
+
+                Address_KindOther ->
+                    3
+
+                Address_KindBilling ->
+                    4
+
+    in
+        JE.int <| lookup v
@@ -0,0 +1,285 @@
+module Enum_strings exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: enum_strings.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type Color
+    = Unspecified -- 0
+    | Red -- 1
+    | DarkBlue -- 2
+    | UnrecognizedColor Int
+
+
+colorPortDecoder : JD.Decoder Color
+colorPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    Unspecified
+
+                1 ->
+                    Red
+
+                2 ->
+                    DarkBlue
+
+                _ ->
+                    UnrecognizedColor v
+    in
+        JD.map lookup JD.int
+
+
+colorDefault : Color
+colorDefault = Unspecified
+
+
+colorPortEncoder : Color -> JE.Value
+colorPortEncoder v =
+    let
+        lookup s =
+            case s of
+                Unspecified ->
+                    0
+
+                Red ->
+                    1
+
+                DarkBlue ->
+                    2
+
+                UnrecognizedColor n ->
+                    n
+
+    in
+        JE.int <| lookup v
+
+
+colorToString : Color -> String
+colorToString v =
+    case v of
+        Unspecified ->
+            "COLOR_UNSPECIFIED"
+
+        Red ->
+            "COLOR_RED"
+
+        DarkBlue ->
+            "COLOR_DARK_BLUE"
+
+        UnrecognizedColor n ->
+            String.fromInt n
+
+
+colorFromString : String -> Maybe Color
+colorFromString s =
+    case s of
+        "COLOR_UNSPECIFIED" ->
+            Just Unspecified
+
+        "COLOR_RED" ->
+            Just Red
+
+        "COLOR_DARK_BLUE" ->
+            Just DarkBlue
+
+        _ ->
+            Nothing
+
+
+type alias Paint =
+    { color : Color -- 1
+    , finish : Paint_Finish -- 2
+    }
+
+
+defaultPaint : Paint
+defaultPaint =
+  {color = colorDefault
+  , finish = paint_FinishDefault
+  }
+
+
+-- paintPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+paintPortDecoder : JD.Decoder Paint
+paintPortDecoder =
+    JD.lazy <| \_ -> decode Paint
+        |> idxWithDefault 0 colorPortDecoder colorDefault
+        |> idxWithDefault 1 paint_FinishPortDecoder paint_FinishDefault
+
+
+-- paintPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+paintPortEncoder : Paint -> JE.Value
+paintPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (colorPortEncoder v.color)
+        , (paint_FinishPortEncoder v.finish)
+        ]
+
+
+type Paint_Finish
+    = Paint_Matte -- 0
+    | Paint_Gloss -- 1
+    | UnrecognizedPaint_Finish Int
+
+
+paint_FinishPortDecoder : JD.Decoder Paint_Finish
+paint_FinishPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    Paint_Matte
+
+                1 ->
+                    Paint_Gloss
+
+                _ ->
+                    UnrecognizedPaint_Finish v
+    in
+        JD.map lookup JD.int
+
+
+paint_FinishDefault : Paint_Finish
+paint_FinishDefault = Paint_Matte
+
+
+paint_FinishPortEncoder : Paint_Finish -> JE.Value
+paint_FinishPortEncoder v =
+    let
+        lookup s =
+            case s of
+                Paint_Matte ->
+                    0
+
+                Paint_Gloss ->
+                    1
+
+                UnrecognizedPaint_Finish n ->
+                    n
+
+    in
+        JE.int <| lookup v
+
+
+paint_FinishToString : Paint_Finish -> String
+paint_FinishToString v =
+    case v of
+        Paint_Matte ->
+            "MATTE"
+
+        Paint_Gloss ->
+            "GLOSS"
+
+        UnrecognizedPaint_Finish n ->
+            String.fromInt n
+
+
+paint_FinishFromString : String -> Maybe Paint_Finish
+paint_FinishFromString s =
+    case s of
+        "MATTE" ->
+            Just Paint_Matte
+
+        "GLOSS" ->
+            Just Paint_Gloss
+
+        _ ->
+            Nothing
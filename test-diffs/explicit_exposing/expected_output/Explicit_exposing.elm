@@ -0,0 +1,297 @@
+module Explicit_exposing exposing
+    ( Status(..)
+    , statusPortDecoder
+    , statusDefault
+    , statusPortEncoder
+    , Account
+    , defaultAccount
+    , accountPortDecoder
+    , accountPortEncoder
+    , Account_Contact(..)
+    , account_ContactPortDecoder
+    , account_ContactPortEncoder
+    , Account_ScoresEntry
+    , defaultAccount_ScoresEntry
+    , account_ScoresEntryPortDecoder
+    , account_ScoresEntryPortEncoder
+    , Account_Owner
+    , defaultAccount_Owner
+    , account_OwnerPortDecoder
+    , account_OwnerPortEncoder
+    )
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: explicit_exposing.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type Status
+    = StatusUnspecified -- 0
+    | StatusActive -- 1
+
+
+statusPortDecoder : JD.Decoder Status
+statusPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    StatusUnspecified
+
+                1 ->
+                    StatusActive
+
+                _ ->
+                    StatusUnspecified
+    in
+        JD.map lookup JD.int
+
+
+statusDefault : Status
+statusDefault = StatusUnspecified
+
+
+statusPortEncoder : Status -> JE.Value
+statusPortEncoder v =
+    let
+        lookup s =
+            case s of
+                StatusUnspecified ->
+                    0
+
+                StatusActive ->
+                    1
+
+    in
+        JE.int <| lookup v
+
+
+type alias Account =
+    { name : String -- 1
+    , status : Status -- 2
+    , scores : Dict.Dict String Int -- 3
+    , limit : Maybe Int -- 4
+    , contact : Account_Contact
+    }
+
+
+defaultAccount : Account
+defaultAccount =
+  {name = ""
+  , status = statusDefault
+  , scores = Dict.empty
+  , limit = Nothing
+  , contact = Account_ContactUnspecified
+  }
+
+
+-- accountPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+accountPortDecoder : JD.Decoder Account
+accountPortDecoder =
+    JD.lazy <| \_ -> decode Account
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 statusPortDecoder statusDefault
+        |> mapEntries 2 JD.string intDecoder
+        |> maybeIdx 3 intValueDecoder
+        |> custom account_ContactPortDecoder
+
+
+-- accountPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+accountPortEncoder : Account -> JE.Value
+accountPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        , (statusPortEncoder v.status)
+        , (mapEntriesFieldEncoder JE.string JE.int v.scores)
+        , (maybeEncoder intValueEncoder v.limit)
+        , (account_ContactPortEncoder 5 v.contact)
+        , (account_ContactPortEncoder 6 v.contact)
+        ]
+
+
+type Account_Contact
+    = Account_ContactUnspecified
+    | Account_Email String -- 5
+    | Account_Phone String -- 6
+
+
+account_ContactPortDecoder : JD.Decoder Account_Contact
+account_ContactPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Account_Email (JD.index 4 (failOnNull JD.string))
+        , JD.map Account_Phone (JD.index 5 (failOnNull JD.string))
+        , JD.succeed Account_ContactUnspecified
+        ]
+
+
+account_ContactPortEncoder : Int -> Account_Contact -> JE.Value
+account_ContactPortEncoder idx v =
+    case v of
+        Account_ContactUnspecified ->
+            JE.null
+
+        Account_Email x ->
+            if idx == 5 then JE.string x else JE.null
+
+        Account_Phone x ->
+            if idx == 6 then JE.string x else JE.null
+
+
+type alias Account_ScoresEntry =
+    { key : String -- 1
+    , value : Int -- 2
+    }
+
+
+defaultAccount_ScoresEntry : Account_ScoresEntry
+defaultAccount_ScoresEntry =
+  {key = ""
+  , value = 0
+  }
+
+
+-- account_ScoresEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+account_ScoresEntryPortDecoder : JD.Decoder Account_ScoresEntry
+account_ScoresEntryPortDecoder =
+    JD.lazy <| \_ -> decode Account_ScoresEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+
+
+-- account_ScoresEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+account_ScoresEntryPortEncoder : Account_ScoresEntry -> JE.Value
+account_ScoresEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (JE.int v.value)
+        ]
+
+
+type alias Account_Owner =
+    { name : String -- 1
+    }
+
+
+defaultAccount_Owner : Account_Owner
+defaultAccount_Owner =
+  {name = ""
+  }
+
+
+-- account_OwnerPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+account_OwnerPortDecoder : JD.Decoder Account_Owner
+account_OwnerPortDecoder =
+    JD.lazy <| \_ -> decode Account_Owner
+        |> idxWithDefault 0 JD.string ""
+
+
+-- account_OwnerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+account_OwnerPortEncoder : Account_Owner -> JE.Value
+account_OwnerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        ]
@@ -0,0 +1,229 @@
+module Extensions exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: extensions.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- extensionFields decodes the object that javascript keeps after the last
+field of messages with extensions, which maps extension field numbers to their
+values.
+-}
+extensionFields : JD.Decoder (Dict.Dict Int JD.Value)
+extensionFields =
+    JD.list JD.value
+        |> JD.map
+            (\values ->
+                case List.reverse values of
+                    last :: _ ->
+                        JD.decodeValue (JD.keyValuePairs JD.value) last
+                            |> Result.withDefault []
+                            |> List.filterMap (\( key, value ) -> Maybe.map (\n -> ( n, value )) (String.toInt key))
+                            |> Dict.fromList
+
+                    [] ->
+                        Dict.empty
+            )
+
+
+extensionObject : Dict.Dict Int JE.Value -> JE.Value
+extensionObject extensions =
+    if Dict.isEmpty extensions then
+        JE.null
+
+    else
+        JE.object (List.map (\( n, value ) -> ( String.fromInt n, value )) (Dict.toList extensions))
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Resource =
+    { name : String -- 1
+    , version : Int -- 2
+    , extensions : Dict.Dict Int JD.Value
+    }
+
+
+defaultResource : Resource
+defaultResource =
+  {name = ""
+  , version = 0
+  , extensions = Dict.empty
+  }
+
+
+-- resourcePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+resourcePortDecoder : JD.Decoder Resource
+resourcePortDecoder =
+    JD.lazy <| \_ -> decode Resource
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+        |> custom extensionFields
+
+
+-- resourcePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+resourcePortEncoder : Resource -> JE.Value
+resourcePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        , (JE.int v.version)
+        , (extensionObject v.extensions)
+        ]
+
+
+type alias Resource_Annotation =
+    { text : String -- 1
+    , extensions : Dict.Dict Int JD.Value
+    }
+
+
+defaultResource_Annotation : Resource_Annotation
+defaultResource_Annotation =
+  {text = ""
+  , extensions = Dict.empty
+  }
+
+
+-- resource_AnnotationPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+resource_AnnotationPortDecoder : JD.Decoder Resource_Annotation
+resource_AnnotationPortDecoder =
+    JD.lazy <| \_ -> decode Resource_Annotation
+        |> idxWithDefault 0 JD.string ""
+        |> custom extensionFields
+
+
+-- resource_AnnotationPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+resource_AnnotationPortEncoder : Resource_Annotation -> JE.Value
+resource_AnnotationPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.text)
+        , (extensionObject v.extensions)
+        ]
+
+
+type alias Plain =
+    { name : String -- 1
+    }
+
+
+defaultPlain : Plain
+defaultPlain =
+  {name = ""
+  }
+
+
+-- plainPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+plainPortDecoder : JD.Decoder Plain
+plainPortDecoder =
+    JD.lazy <| \_ -> decode Plain
+        |> idxWithDefault 0 JD.string ""
+
+
+-- plainPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+plainPortEncoder : Plain -> JE.Value
+plainPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        ]
@@ -0,0 +1,246 @@
+module Field_name_casing exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: field_name_casing.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- arrayMessage and objectMessage only run a message decoder on a value of
+the matching shape.  Otherwise a decoder for one format would succeed on the
+other, with every field missing and so set to its default.
+-}
+arrayMessage : JD.Decoder a -> JD.Decoder a
+arrayMessage decoder =
+    JD.list JD.value |> JD.andThen (\_ -> decoder)
+
+
+objectMessage : JD.Decoder a -> JD.Decoder a
+objectMessage decoder =
+    JD.keyValuePairs JD.value |> JD.andThen (\_ -> decoder)
+
+
+{- fieldWithDefault is the object format counterpart of idxWithDefault.
+-}
+fieldWithDefault : String -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+fieldWithDefault name decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.field name (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeField is the object format counterpart of maybeIdx.
+-}
+maybeField : String -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeField name decoder =
+    JD.map2 (|>)
+        (JD.maybe (JD.field name JD.value)
+            |> JD.andThen
+                (\value ->
+                    case value of
+                        Just _ ->
+                            JD.field name (JD.nullable decoder)
+
+                        Nothing ->
+                            JD.succeed Nothing
+                )
+        )
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Response =
+    { httpStatus : Int -- 1
+    , httpCode : Int -- 2
+    , userID : String -- 3
+    , field2 : String -- 4
+    , field3 : String -- 5
+    , id : String -- 6
+    , urlV2Path : String -- 7
+    , urlChoice : Response_URLChoice
+    }
+
+
+defaultResponse : Response
+defaultResponse =
+  {httpStatus = 0
+  , httpCode = 0
+  , userID = ""
+  , field2 = ""
+  , field3 = ""
+  , id = ""
+  , urlV2Path = ""
+  , urlChoice = Response_URLChoiceUnspecified
+  }
+
+
+-- responsePortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+responsePortDecoder : JD.Decoder Response
+responsePortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Response
+                |> idxWithDefault 0 intDecoder 0
+                |> idxWithDefault 1 intDecoder 0
+                |> idxWithDefault 2 JD.string ""
+                |> idxWithDefault 3 JD.string ""
+                |> idxWithDefault 4 JD.string ""
+                |> idxWithDefault 5 JD.string ""
+                |> idxWithDefault 6 JD.string ""
+                |> custom response_URLChoicePortDecoder
+            )
+        , objectMessage
+            (decode Response
+                |> fieldWithDefault "HTTPStatus" intDecoder 0
+                |> fieldWithDefault "HTTPCode" intDecoder 0
+                |> fieldWithDefault "userID" JD.string ""
+                |> fieldWithDefault "field2" JD.string ""
+                |> fieldWithDefault "field3" JD.string ""
+                |> fieldWithDefault "ID" JD.string ""
+                |> fieldWithDefault "urlV2Path" JD.string ""
+                |> custom response_URLChoiceObjectDecoder
+            )
+        ]
+
+
+-- responsePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+responsePortEncoder : Response -> JE.Value
+responsePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.int v.httpStatus)
+        , (JE.int v.httpCode)
+        , (JE.string v.userID)
+        , (JE.string v.field2)
+        , (JE.string v.field3)
+        , (JE.string v.id)
+        , (JE.string v.urlV2Path)
+        , (response_URLChoicePortEncoder 8 v.urlChoice)
+        , (response_URLChoicePortEncoder 9 v.urlChoice)
+        ]
+
+
+type Response_URLChoice
+    = Response_URLChoiceUnspecified
+    | Response_AbsoluteUrl String -- 8
+    | Response_Relative String -- 9
+
+
+response_URLChoicePortDecoder : JD.Decoder Response_URLChoice
+response_URLChoicePortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Response_AbsoluteUrl (JD.index 7 (failOnNull JD.string))
+        , JD.map Response_Relative (JD.index 8 (failOnNull JD.string))
+        , JD.succeed Response_URLChoiceUnspecified
+        ]
+
+
+response_URLChoicePortEncoder : Int -> Response_URLChoice -> JE.Value
+response_URLChoicePortEncoder idx v =
+    case v of
+        Response_URLChoiceUnspecified ->
+            JE.null
+
+        Response_AbsoluteUrl x ->
+            if idx == 8 then JE.string x else JE.null
+
+        Response_Relative x ->
+            if idx == 9 then JE.string x else JE.null
+
+
+response_URLChoiceObjectDecoder : JD.Decoder Response_URLChoice
+response_URLChoiceObjectDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Response_AbsoluteUrl (JD.field "absoluteURL" (failOnNull JD.string))
+        , JD.map Response_Relative (JD.field "relative" (failOnNull JD.string))
+        , JD.succeed Response_URLChoiceUnspecified
+        ]
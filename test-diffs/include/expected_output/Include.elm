@@ -0,0 +1,240 @@
+module Include exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: include.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type Currency
+    = CurrencyUnspecified -- 0
+    | CurrencyEur -- 1
+
+
+currencyPortDecoder : JD.Decoder Currency
+currencyPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    CurrencyUnspecified
+
+                1 ->
+                    CurrencyEur
+
+                _ ->
+                    CurrencyUnspecified
+    in
+        JD.map lookup JD.int
+
+
+currencyDefault : Currency
+currencyDefault = CurrencyUnspecified
+
+
+currencyPortEncoder : Currency -> JE.Value
+currencyPortEncoder v =
+    let
+        lookup s =
+            case s of
+                CurrencyUnspecified ->
+                    0
+
+                CurrencyEur ->
+                    1
+
+    in
+        JE.int <| lookup v
+
+
+type alias Money =
+    { currency : Currency -- 1
+    , cents : Int -- 2
+    }
+
+
+defaultMoney : Money
+defaultMoney =
+  {currency = currencyDefault
+  , cents = 0
+  }
+
+
+-- moneyPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+moneyPortDecoder : JD.Decoder Money
+moneyPortDecoder =
+    JD.lazy <| \_ -> decode Money
+        |> idxWithDefault 0 currencyPortDecoder currencyDefault
+        |> idxWithDefault 1 intDecoder 0
+
+
+-- moneyPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+moneyPortEncoder : Money -> JE.Value
+moneyPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (currencyPortEncoder v.currency)
+        , (numericStringEncoder v.cents)
+        ]
+
+
+type alias Order =
+    { id : String -- 1
+    , lines : List Order_Line -- 2
+    }
+
+
+defaultOrder : Order
+defaultOrder =
+  {id = ""
+  , lines = []
+  }
+
+
+-- orderPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+orderPortDecoder : JD.Decoder Order
+orderPortDecoder =
+    JD.lazy <| \_ -> decode Order
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 (JD.list order_LinePortDecoder) []
+
+
+-- orderPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+orderPortEncoder : Order -> JE.Value
+orderPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.id)
+        , (JE.list order_LinePortEncoder v.lines)
+        ]
+
+
+type alias Order_Line =
+    { sku : String -- 1
+    , price : Maybe Money -- 2
+    }
+
+
+defaultOrder_Line : Order_Line
+defaultOrder_Line =
+  {sku = ""
+  , price = Nothing
+  }
+
+
+-- order_LinePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+order_LinePortDecoder : JD.Decoder Order_Line
+order_LinePortDecoder =
+    JD.lazy <| \_ -> decode Order_Line
+        |> idxWithDefault 0 JD.string ""
+        |> maybeIdx 1 moneyPortDecoder
+
+
+-- order_LinePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+order_LinePortEncoder : Order_Line -> JE.Value
+order_LinePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.sku)
+        , (maybeEncoder moneyPortEncoder v.price)
+        ]
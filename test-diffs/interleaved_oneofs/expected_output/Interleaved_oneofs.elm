@@ -0,0 +1,197 @@
+module Interleaved_oneofs exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: interleaved_oneofs.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Payment =
+    { id : String -- 1
+    , amount : Int -- 5
+    , source : Payment_Source
+    , destination : Payment_Destination
+    }
+
+
+defaultPayment : Payment
+defaultPayment =
+  {id = ""
+  , amount = 0
+  , source = Payment_SourceUnspecified
+  , destination = Payment_DestinationUnspecified
+  }
+
+
+-- paymentPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+paymentPortDecoder : JD.Decoder Payment
+paymentPortDecoder =
+    JD.lazy <| \_ -> decode Payment
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 4 intDecoder 0
+        |> custom payment_SourcePortDecoder
+        |> custom payment_DestinationPortDecoder
+
+
+-- paymentPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+paymentPortEncoder : Payment -> JE.Value
+paymentPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.id)
+        , (payment_SourcePortEncoder 2 v.source)
+        , (payment_DestinationPortEncoder 3 v.destination)
+        , (payment_SourcePortEncoder 4 v.source)
+        , (JE.int v.amount)
+        , JE.null
+        , (payment_DestinationPortEncoder 7 v.destination)
+        ]
+
+
+type Payment_Source
+    = Payment_SourceUnspecified
+    | Payment_Card String -- 2
+    | Payment_Account String -- 4
+
+
+payment_SourcePortDecoder : JD.Decoder Payment_Source
+payment_SourcePortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Payment_Card (JD.index 1 (failOnNull JD.string))
+        , JD.map Payment_Account (JD.index 3 (failOnNull JD.string))
+        , JD.succeed Payment_SourceUnspecified
+        ]
+
+
+payment_SourcePortEncoder : Int -> Payment_Source -> JE.Value
+payment_SourcePortEncoder idx v =
+    case v of
+        Payment_SourceUnspecified ->
+            JE.null
+
+        Payment_Card x ->
+            if idx == 2 then JE.string x else JE.null
+
+        Payment_Account x ->
+            if idx == 4 then JE.string x else JE.null
+
+
+type Payment_Destination
+    = Payment_DestinationUnspecified
+    | Payment_Iban String -- 3
+    | Payment_Wallet Int -- 7
+
+
+payment_DestinationPortDecoder : JD.Decoder Payment_Destination
+payment_DestinationPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Payment_Iban (JD.index 2 (failOnNull JD.string))
+        , JD.map Payment_Wallet (JD.index 6 (failOnNull intDecoder))
+        , JD.succeed Payment_DestinationUnspecified
+        ]
+
+
+payment_DestinationPortEncoder : Int -> Payment_Destination -> JE.Value
+payment_DestinationPortEncoder idx v =
+    case v of
+        Payment_DestinationUnspecified ->
+            JE.null
+
+        Payment_Iban x ->
+            if idx == 3 then JE.string x else JE.null
+
+        Payment_Wallet x ->
+            if idx == 7 then JE.int x else JE.null
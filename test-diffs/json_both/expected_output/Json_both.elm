@@ -0,0 +1,360 @@
+module Json_both exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: json_both.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- arrayMessage and objectMessage only run a message decoder on a value of
+the matching shape.  Otherwise a decoder for one format would succeed on the
+other, with every field missing and so set to its default.
+-}
+arrayMessage : JD.Decoder a -> JD.Decoder a
+arrayMessage decoder =
+    JD.list JD.value |> JD.andThen (\_ -> decoder)
+
+
+objectMessage : JD.Decoder a -> JD.Decoder a
+objectMessage decoder =
+    JD.keyValuePairs JD.value |> JD.andThen (\_ -> decoder)
+
+
+{- fieldWithDefault is the object format counterpart of idxWithDefault.
+-}
+fieldWithDefault : String -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+fieldWithDefault name decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.field name (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeField is the object format counterpart of maybeIdx.
+-}
+maybeField : String -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeField name decoder =
+    JD.map2 (|>)
+        (JD.maybe (JD.field name JD.value)
+            |> JD.andThen
+                (\value ->
+                    case value of
+                        Just _ ->
+                            JD.field name (JD.nullable decoder)
+
+                        Nothing ->
+                            JD.succeed Nothing
+                )
+        )
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Account =
+    { displayName : String -- 1
+    , balance : Int -- 2
+    , status : Account_Status -- 3
+    , tags : List String -- 4
+    , owner : Maybe Owner -- 5
+    , verified : Maybe Bool -- 6
+    , createdAt : Maybe Timestamp -- 7
+    , contact : Account_Contact
+    }
+
+
+defaultAccount : Account
+defaultAccount =
+  {displayName = ""
+  , balance = 0
+  , status = account_StatusDefault
+  , tags = []
+  , owner = Nothing
+  , verified = Nothing
+  , createdAt = Nothing
+  , contact = Account_ContactUnspecified
+  }
+
+
+-- accountPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+accountPortDecoder : JD.Decoder Account
+accountPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Account
+                |> idxWithDefault 0 JD.string ""
+                |> idxWithDefault 1 intDecoder 0
+                |> idxWithDefault 2 account_StatusPortDecoder account_StatusDefault
+                |> idxWithDefault 3 (JD.list JD.string) []
+                |> maybeIdx 4 ownerPortDecoder
+                |> maybeIdx 5 JD.bool
+                |> maybeIdx 6 timestampDecoder
+                |> custom account_ContactPortDecoder
+            )
+        , objectMessage
+            (decode Account
+                |> fieldWithDefault "displayName" JD.string ""
+                |> fieldWithDefault "balance" intDecoder 0
+                |> fieldWithDefault "status" account_StatusPortDecoder account_StatusDefault
+                |> fieldWithDefault "tags" (JD.list JD.string) []
+                |> maybeField "owner" ownerPortDecoder
+                |> maybeField "verified" JD.bool
+                |> maybeField "createdAt" timestampDecoder
+                |> custom account_ContactObjectDecoder
+            )
+        ]
+
+
+-- accountPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+accountPortEncoder : Account -> JE.Value
+accountPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.displayName)
+        , (numericStringEncoder v.balance)
+        , (account_StatusPortEncoder v.status)
+        , (JE.list JE.string v.tags)
+        , (maybeEncoder ownerPortEncoder v.owner)
+        , (maybeEncoder JE.bool v.verified)
+        , (maybeEncoder timestampEncoder v.createdAt)
+        , (account_ContactPortEncoder 8 v.contact)
+        , (account_ContactPortEncoder 9 v.contact)
+        ]
+
+
+type Account_Contact
+    = Account_ContactUnspecified
+    | Account_EmailAddress String -- 8
+    | Account_Delegate Owner -- 9
+
+
+account_ContactPortDecoder : JD.Decoder Account_Contact
+account_ContactPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Account_EmailAddress (JD.index 7 (failOnNull JD.string))
+        , JD.map Account_Delegate (JD.index 8 (failOnNull ownerPortDecoder))
+        , JD.succeed Account_ContactUnspecified
+        ]
+
+
+account_ContactPortEncoder : Int -> Account_Contact -> JE.Value
+account_ContactPortEncoder idx v =
+    case v of
+        Account_ContactUnspecified ->
+            JE.null
+
+        Account_EmailAddress x ->
+            if idx == 8 then JE.string x else JE.null
+
+        Account_Delegate x ->
+            if idx == 9 then ownerPortEncoder x else JE.null
+
+
+account_ContactObjectDecoder : JD.Decoder Account_Contact
+account_ContactObjectDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Account_EmailAddress (JD.field "emailAddress" (failOnNull JD.string))
+        , JD.map Account_Delegate (JD.field "delegate" (failOnNull ownerPortDecoder))
+        , JD.succeed Account_ContactUnspecified
+        ]
+
+
+type Account_Status
+    = Account_StatusUnspecified -- 0
+    | Account_StatusActive -- 1
+
+
+account_StatusPortDecoder : JD.Decoder Account_Status
+account_StatusPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    Account_StatusUnspecified
+
+                1 ->
+                    Account_StatusActive
+
+                _ ->
+                    Account_StatusUnspecified
+    in
+        JD.map lookup JD.int
+
+
+account_StatusDefault : Account_Status
+account_StatusDefault = Account_StatusUnspecified
+
+
+account_StatusPortEncoder : Account_Status -> JE.Value
+account_StatusPortEncoder v =
+    let
+        lookup s =
+            case s of
+                Account_StatusUnspecified ->
+                    0
+
+                Account_StatusActive ->
+                    1
+
+    in
+        JE.int <| lookup v
+
+
+type alias Owner =
+    { name : String -- 1
+    }
+
+
+defaultOwner : Owner
+defaultOwner =
+  {name = ""
+  }
+
+
+-- ownerPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+ownerPortDecoder : JD.Decoder Owner
+ownerPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Owner
+                |> idxWithDefault 0 JD.string ""
+            )
+        , objectMessage
+            (decode Owner
+                |> fieldWithDefault "name" JD.string ""
+            )
+        ]
+
+
+-- ownerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+ownerPortEncoder : Owner -> JE.Value
+ownerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        ]
+
+
+type alias Empty =
+    { }
+
+
+defaultEmpty : Empty
+defaultEmpty =
+  {
+  }
+
+
+-- emptyPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+emptyPortDecoder : JD.Decoder Empty
+emptyPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Empty
+            )
+        , objectMessage
+            (decode Empty
+            )
+        ]
+
+
+-- emptyPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+emptyPortEncoder : Empty -> JE.Value
+emptyPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ 
+        ]
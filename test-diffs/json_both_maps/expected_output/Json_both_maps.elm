@@ -0,0 +1,368 @@
+module Json_both_maps exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: json_both_maps.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- arrayMessage and objectMessage only run a message decoder on a value of
+the matching shape.  Otherwise a decoder for one format would succeed on the
+other, with every field missing and so set to its default.
+-}
+arrayMessage : JD.Decoder a -> JD.Decoder a
+arrayMessage decoder =
+    JD.list JD.value |> JD.andThen (\_ -> decoder)
+
+
+objectMessage : JD.Decoder a -> JD.Decoder a
+objectMessage decoder =
+    JD.keyValuePairs JD.value |> JD.andThen (\_ -> decoder)
+
+
+{- fieldWithDefault is the object format counterpart of idxWithDefault.
+-}
+fieldWithDefault : String -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+fieldWithDefault name decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.field name (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeField is the object format counterpart of maybeIdx.
+-}
+maybeField : String -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeField name decoder =
+    JD.map2 (|>)
+        (JD.maybe (JD.field name JD.value)
+            |> JD.andThen
+                (\value ->
+                    case value of
+                        Just _ ->
+                            JD.field name (JD.nullable decoder)
+
+                        Nothing ->
+                            JD.succeed Nothing
+                )
+        )
+
+
+{- intKeyDict decodes a map field with integer keys from the object format,
+where keys are always strings.
+-}
+intKeyDict : JD.Decoder a -> JD.Decoder (Dict.Dict Int a)
+intKeyDict decoder =
+    JD.keyValuePairs decoder
+        |> JD.andThen
+            (\pairs ->
+                List.foldr
+                    (\( key, value ) result ->
+                        case String.toInt key of
+                            Just n ->
+                                JD.map (Dict.insert n value) result
+
+                            Nothing ->
+                                JD.fail ("invalid map key " ++ key)
+                    )
+                    (JD.succeed Dict.empty)
+                    pairs
+            )
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Inventory =
+    { counts : Dict.Dict String Int -- 1
+    , names : Dict.Dict Int String -- 2
+    , itemsById : Dict.Dict Int Item -- 3
+    }
+
+
+defaultInventory : Inventory
+defaultInventory =
+  {counts = Dict.empty
+  , names = Dict.empty
+  , itemsById = Dict.empty
+  }
+
+
+-- inventoryPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+inventoryPortDecoder : JD.Decoder Inventory
+inventoryPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Inventory
+                |> mapEntries 0 JD.string intDecoder
+                |> mapEntries 1 intDecoder JD.string
+                |> mapEntries 2 intDecoder itemPortDecoder
+            )
+        , objectMessage
+            (decode Inventory
+                |> fieldWithDefault "counts" (JD.dict intDecoder) Dict.empty
+                |> fieldWithDefault "names" (intKeyDict JD.string) Dict.empty
+                |> fieldWithDefault "itemsById" (intKeyDict itemPortDecoder) Dict.empty
+            )
+        ]
+
+
+-- inventoryPortEncoder is used to encode protobuf messages for ports, following the canonical
+-- JSON object format.
+inventoryPortEncoder : Inventory -> JE.Value
+inventoryPortEncoder v =
+    JE.object <|
+        List.concat
+            [ [ ( "counts", JE.dict identity JE.int v.counts ) ]
+            , [ ( "names", JE.dict String.fromInt JE.string v.names ) ]
+            , [ ( "itemsById", JE.dict String.fromInt itemPortEncoder v.itemsById ) ]
+            ]
+
+
+type alias Inventory_CountsEntry =
+    { key : String -- 1
+    , value : Int -- 2
+    }
+
+
+defaultInventory_CountsEntry : Inventory_CountsEntry
+defaultInventory_CountsEntry =
+  {key = ""
+  , value = 0
+  }
+
+
+-- inventory_CountsEntryPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+inventory_CountsEntryPortDecoder : JD.Decoder Inventory_CountsEntry
+inventory_CountsEntryPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Inventory_CountsEntry
+                |> idxWithDefault 0 JD.string ""
+                |> idxWithDefault 1 intDecoder 0
+            )
+        , objectMessage
+            (decode Inventory_CountsEntry
+                |> fieldWithDefault "key" JD.string ""
+                |> fieldWithDefault "value" intDecoder 0
+            )
+        ]
+
+
+-- inventory_CountsEntryPortEncoder is used to encode protobuf messages for ports, following the canonical
+-- JSON object format.
+inventory_CountsEntryPortEncoder : Inventory_CountsEntry -> JE.Value
+inventory_CountsEntryPortEncoder v =
+    JE.object <|
+        List.concat
+            [ [ ( "key", JE.string v.key ) ]
+            , [ ( "value", JE.int v.value ) ]
+            ]
+
+
+type alias Inventory_NamesEntry =
+    { key : Int -- 1
+    , value : String -- 2
+    }
+
+
+defaultInventory_NamesEntry : Inventory_NamesEntry
+defaultInventory_NamesEntry =
+  {key = 0
+  , value = ""
+  }
+
+
+-- inventory_NamesEntryPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+inventory_NamesEntryPortDecoder : JD.Decoder Inventory_NamesEntry
+inventory_NamesEntryPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Inventory_NamesEntry
+                |> idxWithDefault 0 intDecoder 0
+                |> idxWithDefault 1 JD.string ""
+            )
+        , objectMessage
+            (decode Inventory_NamesEntry
+                |> fieldWithDefault "key" intDecoder 0
+                |> fieldWithDefault "value" JD.string ""
+            )
+        ]
+
+
+-- inventory_NamesEntryPortEncoder is used to encode protobuf messages for ports, following the canonical
+-- JSON object format.
+inventory_NamesEntryPortEncoder : Inventory_NamesEntry -> JE.Value
+inventory_NamesEntryPortEncoder v =
+    JE.object <|
+        List.concat
+            [ [ ( "key", JE.int v.key ) ]
+            , [ ( "value", JE.string v.value ) ]
+            ]
+
+
+type alias Inventory_ItemsByIdEntry =
+    { key : Int -- 1
+    , value : Maybe Item -- 2
+    }
+
+
+defaultInventory_ItemsByIdEntry : Inventory_ItemsByIdEntry
+defaultInventory_ItemsByIdEntry =
+  {key = 0
+  , value = Nothing
+  }
+
+
+-- inventory_ItemsByIdEntryPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+inventory_ItemsByIdEntryPortDecoder : JD.Decoder Inventory_ItemsByIdEntry
+inventory_ItemsByIdEntryPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Inventory_ItemsByIdEntry
+                |> idxWithDefault 0 intDecoder 0
+                |> maybeIdx 1 itemPortDecoder
+            )
+        , objectMessage
+            (decode Inventory_ItemsByIdEntry
+                |> fieldWithDefault "key" intDecoder 0
+                |> maybeField "value" itemPortDecoder
+            )
+        ]
+
+
+-- inventory_ItemsByIdEntryPortEncoder is used to encode protobuf messages for ports, following the canonical
+-- JSON object format.
+inventory_ItemsByIdEntryPortEncoder : Inventory_ItemsByIdEntry -> JE.Value
+inventory_ItemsByIdEntryPortEncoder v =
+    JE.object <|
+        List.concat
+            [ [ ( "key", numericStringEncoder v.key ) ]
+            , [ ( "value", maybeEncoder itemPortEncoder v.value ) ]
+            ]
+
+
+type alias Item =
+    { sku : String -- 1
+    }
+
+
+defaultItem : Item
+defaultItem =
+  {sku = ""
+  }
+
+
+-- itemPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+itemPortDecoder : JD.Decoder Item
+itemPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Item
+                |> idxWithDefault 0 JD.string ""
+            )
+        , objectMessage
+            (decode Item
+                |> fieldWithDefault "sku" JD.string ""
+            )
+        ]
+
+
+-- itemPortEncoder is used to encode protobuf messages for ports, following the canonical
+-- JSON object format.
+itemPortEncoder : Item -> JE.Value
+itemPortEncoder v =
+    JE.object <|
+        List.concat
+            [ [ ( "sku", JE.string v.sku ) ]
+            ]
@@ -0,0 +1,173 @@
+module Kept_deprecated_fields exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: kept_deprecated_fields.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Account =
+    { id : String -- 1
+    , legacyName : String -- 2 -- DEPRECATED
+    , oldTags : List String -- 3 -- DEPRECATED
+    , labels : Dict.Dict String String -- 4
+    }
+
+
+defaultAccount : Account
+defaultAccount =
+  {id = ""
+  , legacyName = ""
+  , oldTags = []
+  , labels = Dict.empty
+  }
+
+
+-- accountPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+accountPortDecoder : JD.Decoder Account
+accountPortDecoder =
+    JD.lazy <| \_ -> decode Account
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 JD.string ""
+        |> idxWithDefault 2 (JD.list JD.string) []
+        |> mapEntries 3 JD.string JD.string
+
+
+-- accountPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+accountPortEncoder : Account -> JE.Value
+accountPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.id)
+        , (JE.string v.legacyName)
+        , (JE.list JE.string v.oldTags)
+        , (mapEntriesFieldEncoder JE.string JE.string v.labels)
+        ]
+
+
+type alias Account_LabelsEntry =
+    { key : String -- 1
+    , value : String -- 2
+    }
+
+
+defaultAccount_LabelsEntry : Account_LabelsEntry
+defaultAccount_LabelsEntry =
+  {key = ""
+  , value = ""
+  }
+
+
+-- account_LabelsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+account_LabelsEntryPortDecoder : JD.Decoder Account_LabelsEntry
+account_LabelsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Account_LabelsEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 JD.string ""
+
+
+-- account_LabelsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+account_LabelsEntryPortEncoder : Account_LabelsEntry -> JE.Value
+account_LabelsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (JE.string v.value)
+        ]
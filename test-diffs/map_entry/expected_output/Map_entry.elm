@@ -12,7 +12,89 @@ import Json.Encode as JE
 import Dict
 
 
-uselessDeclarationToPreventErrorDueToEmptyOutputFile = 42
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
 
 
 type alias Bar =
@@ -20,16 +102,28 @@ type alias Bar =
     }
 
 
-barDecoder : JD.Decoder Bar
-barDecoder =
+defaultBar : Bar
+defaultBar =
+  {field = False
+  }
+
+
+-- barPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+barPortDecoder : JD.Decoder Bar
+barPortDecoder =
     JD.lazy <| \_ -> decode Bar
-        |> required "field" JD.bool False
+        |> idxWithDefault 0 JD.bool False
 
 
-barEncoder : Bar -> JE.Value
-barEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (requiredFieldEncoder "field" JE.bool False v.field)
+-- barPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+barPortEncoder : Bar -> JE.Value
+barPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.bool v.field)
         ]
 
 
@@ -39,18 +133,37 @@ type alias Foo =
     }
 
 
-fooDecoder : JD.Decoder Foo
-fooDecoder =
-    JD.lazy <| \_ -> decode Foo
-        |> mapEntries "stringToBars" barDecoder
-        |> mapEntries "stringToStrings" JD.string
+defaultFoo : Foo
+defaultFoo =
+  {stringToBars = Dict.empty
+  , stringToStrings = Dict.empty
+  }
 
 
-fooEncoder : Foo -> JE.Value
-fooEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (mapEntriesFieldEncoder "stringToBars" barEncoder v.stringToBars)
-        , (mapEntriesFieldEncoder "stringToStrings" JE.string v.stringToStrings)
+-- fooPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+fooPortDecoder : JD.Decoder Foo
+fooPortDecoder =
+    JD.lazy <| \_ -> decode Foo
+        |> mapEntries 7 JD.string barPortDecoder
+        |> mapEntries 6 JD.string JD.string
+
+
+-- fooPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+fooPortEncoder : Foo -> JE.Value
+fooPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ JE.null
+        , JE.null
+        , JE.null
+        , JE.null
+        , JE.null
+        , JE.null
+        , (mapEntriesFieldEncoder JE.string JE.string v.stringToStrings)
+        , (mapEntriesFieldEncoder JE.string barPortEncoder v.stringToBars)
         ]
 
 
@@ -60,18 +173,31 @@ type alias Foo_StringToBarsEntry =
     }
 
 
-foo_StringToBarsEntryDecoder : JD.Decoder Foo_StringToBarsEntry
-foo_StringToBarsEntryDecoder =
-    JD.lazy <| \_ -> decode Foo_StringToBarsEntry
-        |> required "key" JD.string ""
-        |> optional "value" barDecoder
+defaultFoo_StringToBarsEntry : Foo_StringToBarsEntry
+defaultFoo_StringToBarsEntry =
+  {key = ""
+  , value = Nothing
+  }
 
 
-foo_StringToBarsEntryEncoder : Foo_StringToBarsEntry -> JE.Value
-foo_StringToBarsEntryEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (requiredFieldEncoder "key" JE.string "" v.key)
-        , (optionalEncoder "value" barEncoder v.value)
+-- foo_StringToBarsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+foo_StringToBarsEntryPortDecoder : JD.Decoder Foo_StringToBarsEntry
+foo_StringToBarsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Foo_StringToBarsEntry
+        |> idxWithDefault 0 JD.string ""
+        |> maybeIdx 1 barPortDecoder
+
+
+-- foo_StringToBarsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+foo_StringToBarsEntryPortEncoder : Foo_StringToBarsEntry -> JE.Value
+foo_StringToBarsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (maybeEncoder barPortEncoder v.value)
         ]
 
 
@@ -81,16 +207,29 @@ type alias Foo_StringToStringsEntry =
     }
 
 
-foo_StringToStringsEntryDecoder : JD.Decoder Foo_StringToStringsEntry
-foo_StringToStringsEntryDecoder =
-    JD.lazy <| \_ -> decode Foo_StringToStringsEntry
-        |> required "key" JD.string ""
-        |> required "value" JD.string ""
+defaultFoo_StringToStringsEntry : Foo_StringToStringsEntry
+defaultFoo_StringToStringsEntry =
+  {key = ""
+  , value = ""
+  }
 
 
-foo_StringToStringsEntryEncoder : Foo_StringToStringsEntry -> JE.Value
-foo_StringToStringsEntryEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (requiredFieldEncoder "key" JE.string "" v.key)
-        , (requiredFieldEncoder "value" JE.string "" v.value)
+-- foo_StringToStringsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+foo_StringToStringsEntryPortDecoder : JD.Decoder Foo_StringToStringsEntry
+foo_StringToStringsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Foo_StringToStringsEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 JD.string ""
+
+
+-- foo_StringToStringsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+foo_StringToStringsEntryPortEncoder : Foo_StringToStringsEntry -> JE.Value
+foo_StringToStringsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (JE.string v.value)
         ]
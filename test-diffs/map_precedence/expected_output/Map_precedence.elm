@@ -0,0 +1,199 @@
+module Map_precedence exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: map_precedence.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Counter =
+    { values : List Counter_ValuesEntry -- 1
+    , counts : Dict.Dict String Int -- 2
+    }
+
+
+defaultCounter : Counter
+defaultCounter =
+  {values = []
+  , counts = Dict.empty
+  }
+
+
+-- counterPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+counterPortDecoder : JD.Decoder Counter
+counterPortDecoder =
+    JD.lazy <| \_ -> decode Counter
+        |> idxWithDefault 0 (JD.list counter_ValuesEntryPortDecoder) []
+        |> mapEntries 1 JD.string intDecoder
+
+
+-- counterPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+counterPortEncoder : Counter -> JE.Value
+counterPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.list counter_ValuesEntryPortEncoder v.values)
+        , (mapEntriesFieldEncoder JE.string JE.int v.counts)
+        ]
+
+
+type alias Counter_ValuesEntry =
+    { key : String -- 1
+    , value : Int -- 2
+    }
+
+
+defaultCounter_ValuesEntry : Counter_ValuesEntry
+defaultCounter_ValuesEntry =
+  {key = ""
+  , value = 0
+  }
+
+
+-- counter_ValuesEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+counter_ValuesEntryPortDecoder : JD.Decoder Counter_ValuesEntry
+counter_ValuesEntryPortDecoder =
+    JD.lazy <| \_ -> decode Counter_ValuesEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+
+
+-- counter_ValuesEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+counter_ValuesEntryPortEncoder : Counter_ValuesEntry -> JE.Value
+counter_ValuesEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (JE.int v.value)
+        ]
+
+
+type alias Counter_CountsEntry =
+    { key : String -- 1
+    , value : Int -- 2
+    }
+
+
+defaultCounter_CountsEntry : Counter_CountsEntry
+defaultCounter_CountsEntry =
+  {key = ""
+  , value = 0
+  }
+
+
+-- counter_CountsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+counter_CountsEntryPortDecoder : JD.Decoder Counter_CountsEntry
+counter_CountsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Counter_CountsEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+
+
+-- counter_CountsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+counter_CountsEntryPortEncoder : Counter_CountsEntry -> JE.Value
+counter_CountsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (JE.int v.value)
+        ]
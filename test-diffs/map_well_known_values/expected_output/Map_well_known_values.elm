@@ -0,0 +1,199 @@
+module Map_well_known_values exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: map_well_known_values.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Schedule =
+    { starts : Dict.Dict String Timestamp -- 1
+    , labels : Dict.Dict Int String -- 2
+    }
+
+
+defaultSchedule : Schedule
+defaultSchedule =
+  {starts = Dict.empty
+  , labels = Dict.empty
+  }
+
+
+-- schedulePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+schedulePortDecoder : JD.Decoder Schedule
+schedulePortDecoder =
+    JD.lazy <| \_ -> decode Schedule
+        |> mapEntries 0 JD.string timestampDecoder
+        |> mapEntries 1 intDecoder stringValueDecoder
+
+
+-- schedulePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+schedulePortEncoder : Schedule -> JE.Value
+schedulePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (mapEntriesFieldEncoder JE.string timestampEncoder v.starts)
+        , (mapEntriesFieldEncoder JE.int stringValueEncoder v.labels)
+        ]
+
+
+type alias Schedule_StartsEntry =
+    { key : String -- 1
+    , value : Maybe Timestamp -- 2
+    }
+
+
+defaultSchedule_StartsEntry : Schedule_StartsEntry
+defaultSchedule_StartsEntry =
+  {key = ""
+  , value = Nothing
+  }
+
+
+-- schedule_StartsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+schedule_StartsEntryPortDecoder : JD.Decoder Schedule_StartsEntry
+schedule_StartsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Schedule_StartsEntry
+        |> idxWithDefault 0 JD.string ""
+        |> maybeIdx 1 timestampDecoder
+
+
+-- schedule_StartsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+schedule_StartsEntryPortEncoder : Schedule_StartsEntry -> JE.Value
+schedule_StartsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (maybeEncoder timestampEncoder v.value)
+        ]
+
+
+type alias Schedule_LabelsEntry =
+    { key : Int -- 1
+    , value : Maybe String -- 2
+    }
+
+
+defaultSchedule_LabelsEntry : Schedule_LabelsEntry
+defaultSchedule_LabelsEntry =
+  {key = 0
+  , value = Nothing
+  }
+
+
+-- schedule_LabelsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+schedule_LabelsEntryPortDecoder : JD.Decoder Schedule_LabelsEntry
+schedule_LabelsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Schedule_LabelsEntry
+        |> idxWithDefault 0 intDecoder 0
+        |> maybeIdx 1 stringValueDecoder
+
+
+-- schedule_LabelsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+schedule_LabelsEntryPortEncoder : Schedule_LabelsEntry -> JE.Value
+schedule_LabelsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.int v.key)
+        , (maybeEncoder stringValueEncoder v.value)
+        ]
@@ -0,0 +1,248 @@
+module Maybe_oneofs exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: maybe_oneofs.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- maybeOneof decodes a oneof as Nothing when the array ends before the oneof's
+first field index, idx, rather than as its Unspecified variant.
+-}
+maybeOneof : Int -> JD.Decoder a -> JD.Decoder (Maybe a)
+maybeOneof idx decoder =
+    JD.list JD.value
+        |> JD.andThen
+            (\values ->
+                if List.length values > idx then
+                    JD.map Just decoder
+
+                else
+                    JD.succeed Nothing
+            )
+
+
+{- maybeOneofField decodes a oneof as Nothing when none of its fields' names
+are keys of the object.
+-}
+maybeOneofField : List String -> JD.Decoder a -> JD.Decoder (Maybe a)
+maybeOneofField names decoder =
+    JD.keyValuePairs JD.value
+        |> JD.andThen
+            (\pairs ->
+                if List.any (\( key, _ ) -> List.member key names) pairs then
+                    JD.map Just decoder
+
+                else
+                    JD.succeed Nothing
+            )
+
+
+{- arrayMessage and objectMessage only run a message decoder on a value of
+the matching shape.  Otherwise a decoder for one format would succeed on the
+other, with every field missing and so set to its default.
+-}
+arrayMessage : JD.Decoder a -> JD.Decoder a
+arrayMessage decoder =
+    JD.list JD.value |> JD.andThen (\_ -> decoder)
+
+
+objectMessage : JD.Decoder a -> JD.Decoder a
+objectMessage decoder =
+    JD.keyValuePairs JD.value |> JD.andThen (\_ -> decoder)
+
+
+{- fieldWithDefault is the object format counterpart of idxWithDefault.
+-}
+fieldWithDefault : String -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+fieldWithDefault name decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.field name (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeField is the object format counterpart of maybeIdx.
+-}
+maybeField : String -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeField name decoder =
+    JD.map2 (|>)
+        (JD.maybe (JD.field name JD.value)
+            |> JD.andThen
+                (\value ->
+                    case value of
+                        Just _ ->
+                            JD.field name (JD.nullable decoder)
+
+                        Nothing ->
+                            JD.succeed Nothing
+                )
+        )
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Shipment =
+    { id : String -- 1
+    , destination : Maybe Shipment_Destination
+    }
+
+
+defaultShipment : Shipment
+defaultShipment =
+  {id = ""
+  , destination = Nothing
+  }
+
+
+-- shipmentPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+shipmentPortDecoder : JD.Decoder Shipment
+shipmentPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Shipment
+                |> idxWithDefault 0 JD.string ""
+                |> custom (maybeOneof 1 shipment_DestinationPortDecoder)
+            )
+        , objectMessage
+            (decode Shipment
+                |> fieldWithDefault "id" JD.string ""
+                |> custom (maybeOneofField [ "locker", "address" ] shipment_DestinationObjectDecoder)
+            )
+        ]
+
+
+-- shipmentPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+shipmentPortEncoder : Shipment -> JE.Value
+shipmentPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.id)
+        , (shipment_DestinationPortEncoder 2 (Maybe.withDefault Shipment_DestinationUnspecified v.destination))
+        , (shipment_DestinationPortEncoder 3 (Maybe.withDefault Shipment_DestinationUnspecified v.destination))
+        ]
+
+
+type Shipment_Destination
+    = Shipment_DestinationUnspecified
+    | Shipment_Locker Int -- 2
+    | Shipment_Address String -- 3
+
+
+shipment_DestinationPortDecoder : JD.Decoder Shipment_Destination
+shipment_DestinationPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Shipment_Locker (JD.index 1 (failOnNull intDecoder))
+        , JD.map Shipment_Address (JD.index 2 (failOnNull JD.string))
+        , JD.succeed Shipment_DestinationUnspecified
+        ]
+
+
+shipment_DestinationPortEncoder : Int -> Shipment_Destination -> JE.Value
+shipment_DestinationPortEncoder idx v =
+    case v of
+        Shipment_DestinationUnspecified ->
+            JE.null
+
+        Shipment_Locker x ->
+            if idx == 2 then JE.int x else JE.null
+
+        Shipment_Address x ->
+            if idx == 3 then JE.string x else JE.null
+
+
+shipment_DestinationObjectDecoder : JD.Decoder Shipment_Destination
+shipment_DestinationObjectDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Shipment_Locker (JD.field "locker" (failOnNull intDecoder))
+        , JD.map Shipment_Address (JD.field "address" (failOnNull JD.string))
+        , JD.succeed Shipment_DestinationUnspecified
+        ]
@@ -0,0 +1,413 @@
+module Merge exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: merge.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+{- mergeField keeps the left value unless the right one differs from the
+field's default.
+-}
+mergeField : a -> a -> a -> a
+mergeField default left right =
+    if right == default then
+        left
+
+    else
+        right
+
+
+{- mergeMaybe keeps the left value unless the right one is set.  It doesn't
+compare the values, so it also merges the JD.Value of Struct, Value and
+ListValue fields, which (==) doesn't support.
+-}
+mergeMaybe : Maybe a -> Maybe a -> Maybe a
+mergeMaybe left right =
+    case right of
+        Just _ ->
+            right
+
+        Nothing ->
+            left
+
+
+{- mergeUnless keeps the left value if the right one is empty, like mergeMaybe
+for collections.
+-}
+mergeUnless : (a -> Bool) -> a -> a -> a
+mergeUnless isEmpty left right =
+    if isEmpty right then
+        left
+
+    else
+        right
+
+
+type alias Settings =
+    { title : String -- 1
+    , offset : Int -- 2
+    , theme : Settings_Theme -- 3
+    , tags : List String -- 4
+    , counts : Dict.Dict String Int -- 5
+    , parent : Maybe Settings -- 6
+    , metadata : Maybe JD.Value -- 9
+    , extra : Maybe JD.Value -- 10
+    , history : List JD.Value -- 11
+    , labels : Dict.Dict String JD.Value -- 12
+    , target : Settings_Target
+    }
+
+
+defaultSettings : Settings
+defaultSettings =
+  {title = "Untitled item"
+  , offset = -5
+  , theme = settings_ThemeDefault
+  , tags = []
+  , counts = Dict.empty
+  , parent = Nothing
+  , metadata = Nothing
+  , extra = Nothing
+  , history = []
+  , labels = Dict.empty
+  , target = Settings_TargetUnspecified
+  }
+
+
+-- settingsPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+settingsPortDecoder : JD.Decoder Settings
+settingsPortDecoder =
+    JD.lazy <| \_ -> decode Settings
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+        |> idxWithDefault 2 settings_ThemePortDecoder settings_ThemeDefault
+        |> idxWithDefault 3 (JD.list JD.string) []
+        |> mapEntries 4 JD.string intDecoder
+        |> maybeIdx 5 settingsPortDecoder
+        |> maybeIdx 8 JD.value
+        |> maybeIdx 9 JD.value
+        |> idxWithDefault 10 (JD.list JD.value) []
+        |> mapEntries 11 JD.string JD.value
+        |> custom settings_TargetPortDecoder
+
+
+-- settingsPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+settingsPortEncoder : Settings -> JE.Value
+settingsPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.title)
+        , (JE.int v.offset)
+        , (settings_ThemePortEncoder v.theme)
+        , (JE.list JE.string v.tags)
+        , (mapEntriesFieldEncoder JE.string JE.int v.counts)
+        , (maybeEncoder settingsPortEncoder v.parent)
+        , (settings_TargetPortEncoder 7 v.target)
+        , (settings_TargetPortEncoder 8 v.target)
+        , (maybeEncoder identity v.metadata)
+        , (maybeEncoder identity v.extra)
+        , (JE.list identity v.history)
+        , (mapEntriesFieldEncoder JE.string identity v.labels)
+        ]
+
+
+-- mergeSettings overlays the fields of right that are not set to their default onto left.
+mergeSettings : Settings -> Settings -> Settings
+mergeSettings left right =
+    { left
+        | title = mergeField "Untitled item" left.title right.title
+        , offset = mergeField (-5) left.offset right.offset
+        , theme = mergeField settings_ThemeDefault left.theme right.theme
+        , tags = mergeField [] left.tags right.tags
+        , counts = mergeField Dict.empty left.counts right.counts
+        , parent = mergeField Nothing left.parent right.parent
+        , metadata = mergeMaybe left.metadata right.metadata
+        , extra = mergeMaybe left.extra right.extra
+        , history = mergeUnless List.isEmpty left.history right.history
+        , labels = mergeUnless Dict.isEmpty left.labels right.labels
+        , target = mergeField Settings_TargetUnspecified left.target right.target
+    }
+
+
+type Settings_Target
+    = Settings_TargetUnspecified
+    | Settings_Url String -- 7
+    | Settings_Page Int -- 8
+
+
+settings_TargetPortDecoder : JD.Decoder Settings_Target
+settings_TargetPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Settings_Url (JD.index 6 (failOnNull JD.string))
+        , JD.map Settings_Page (JD.index 7 (failOnNull intDecoder))
+        , JD.succeed Settings_TargetUnspecified
+        ]
+
+
+settings_TargetPortEncoder : Int -> Settings_Target -> JE.Value
+settings_TargetPortEncoder idx v =
+    case v of
+        Settings_TargetUnspecified ->
+            JE.null
+
+        Settings_Url x ->
+            if idx == 7 then JE.string x else JE.null
+
+        Settings_Page x ->
+            if idx == 8 then JE.int x else JE.null
+
+
+type Settings_Theme
+    = Settings_ThemeLight -- 0
+    | Settings_ThemeDark -- 1
+
+
+settings_ThemePortDecoder : JD.Decoder Settings_Theme
+settings_ThemePortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    Settings_ThemeLight
+
+                1 ->
+                    Settings_ThemeDark
+
+                _ ->
+                    Settings_ThemeLight
+    in
+        JD.map lookup JD.int
+
+
+settings_ThemeDefault : Settings_Theme
+settings_ThemeDefault = Settings_ThemeLight
+
+
+settings_ThemePortEncoder : Settings_Theme -> JE.Value
+settings_ThemePortEncoder v =
+    let
+        lookup s =
+            case s of
+                Settings_ThemeLight ->
+                    0
+
+                Settings_ThemeDark ->
+                    1
+
+    in
+        JE.int <| lookup v
+
+
+type alias Settings_CountsEntry =
+    { key : String -- 1
+    , value : Int -- 2
+    }
+
+
+defaultSettings_CountsEntry : Settings_CountsEntry
+defaultSettings_CountsEntry =
+  {key = ""
+  , value = 0
+  }
+
+
+-- settings_CountsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+settings_CountsEntryPortDecoder : JD.Decoder Settings_CountsEntry
+settings_CountsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Settings_CountsEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+
+
+-- settings_CountsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+settings_CountsEntryPortEncoder : Settings_CountsEntry -> JE.Value
+settings_CountsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (JE.int v.value)
+        ]
+
+
+-- mergeSettings_CountsEntry overlays the fields of right that are not set to their default onto left.
+mergeSettings_CountsEntry : Settings_CountsEntry -> Settings_CountsEntry -> Settings_CountsEntry
+mergeSettings_CountsEntry left right =
+    { left
+        | key = mergeField "" left.key right.key
+        , value = mergeField 0 left.value right.value
+    }
+
+
+type alias Settings_LabelsEntry =
+    { key : String -- 1
+    , value : Maybe JD.Value -- 2
+    }
+
+
+defaultSettings_LabelsEntry : Settings_LabelsEntry
+defaultSettings_LabelsEntry =
+  {key = ""
+  , value = Nothing
+  }
+
+
+-- settings_LabelsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+settings_LabelsEntryPortDecoder : JD.Decoder Settings_LabelsEntry
+settings_LabelsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Settings_LabelsEntry
+        |> idxWithDefault 0 JD.string ""
+        |> maybeIdx 1 JD.value
+
+
+-- settings_LabelsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+settings_LabelsEntryPortEncoder : Settings_LabelsEntry -> JE.Value
+settings_LabelsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (maybeEncoder identity v.value)
+        ]
+
+
+-- mergeSettings_LabelsEntry overlays the fields of right that are not set to their default onto left.
+mergeSettings_LabelsEntry : Settings_LabelsEntry -> Settings_LabelsEntry -> Settings_LabelsEntry
+mergeSettings_LabelsEntry left right =
+    { left
+        | key = mergeField "" left.key right.key
+        , value = mergeMaybe left.value right.value
+    }
+
+
+type alias Blank =
+    { }
+
+
+defaultBlank : Blank
+defaultBlank =
+  {
+  }
+
+
+-- blankPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+blankPortDecoder : JD.Decoder Blank
+blankPortDecoder =
+    JD.lazy <| \_ -> decode Blank
+
+
+-- blankPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+blankPortEncoder : Blank -> JE.Value
+blankPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ 
+        ]
+
+
+-- mergeBlank overlays the fields of right that are not set to their default onto left.
+mergeBlank : Blank -> Blank -> Blank
+mergeBlank left _ =
+    left
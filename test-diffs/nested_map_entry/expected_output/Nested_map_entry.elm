@@ -0,0 +1,271 @@
+module Nested_map_entry exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: nested_map_entry.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Outer =
+    { counts : Dict.Dict String Int -- 1
+    , inner : Maybe Outer_Inner -- 2
+    , inners : List Outer_Inner -- 3
+    }
+
+
+defaultOuter : Outer
+defaultOuter =
+  {counts = Dict.empty
+  , inner = Nothing
+  , inners = []
+  }
+
+
+-- outerPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outerPortDecoder : JD.Decoder Outer
+outerPortDecoder =
+    JD.lazy <| \_ -> decode Outer
+        |> mapEntries 0 JD.string intDecoder
+        |> maybeIdx 1 outer_InnerPortDecoder
+        |> idxWithDefault 2 (JD.list outer_InnerPortDecoder) []
+
+
+-- outerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outerPortEncoder : Outer -> JE.Value
+outerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (mapEntriesFieldEncoder JE.string JE.int v.counts)
+        , (maybeEncoder outer_InnerPortEncoder v.inner)
+        , (JE.list outer_InnerPortEncoder v.inners)
+        ]
+
+
+type alias Outer_CountsEntry =
+    { key : String -- 1
+    , value : Int -- 2
+    }
+
+
+defaultOuter_CountsEntry : Outer_CountsEntry
+defaultOuter_CountsEntry =
+  {key = ""
+  , value = 0
+  }
+
+
+-- outer_CountsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outer_CountsEntryPortDecoder : JD.Decoder Outer_CountsEntry
+outer_CountsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Outer_CountsEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+
+
+-- outer_CountsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outer_CountsEntryPortEncoder : Outer_CountsEntry -> JE.Value
+outer_CountsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (JE.int v.value)
+        ]
+
+
+type alias Outer_Inner =
+    { labels : Dict.Dict String String -- 1
+    , children : Dict.Dict Int Outer -- 2
+    }
+
+
+defaultOuter_Inner : Outer_Inner
+defaultOuter_Inner =
+  {labels = Dict.empty
+  , children = Dict.empty
+  }
+
+
+-- outer_InnerPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outer_InnerPortDecoder : JD.Decoder Outer_Inner
+outer_InnerPortDecoder =
+    JD.lazy <| \_ -> decode Outer_Inner
+        |> mapEntries 0 JD.string JD.string
+        |> mapEntries 1 intDecoder outerPortDecoder
+
+
+-- outer_InnerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outer_InnerPortEncoder : Outer_Inner -> JE.Value
+outer_InnerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (mapEntriesFieldEncoder JE.string JE.string v.labels)
+        , (mapEntriesFieldEncoder JE.int outerPortEncoder v.children)
+        ]
+
+
+type alias Outer_Inner_LabelsEntry =
+    { key : String -- 1
+    , value : String -- 2
+    }
+
+
+defaultOuter_Inner_LabelsEntry : Outer_Inner_LabelsEntry
+defaultOuter_Inner_LabelsEntry =
+  {key = ""
+  , value = ""
+  }
+
+
+-- outer_Inner_LabelsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outer_Inner_LabelsEntryPortDecoder : JD.Decoder Outer_Inner_LabelsEntry
+outer_Inner_LabelsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Outer_Inner_LabelsEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 JD.string ""
+
+
+-- outer_Inner_LabelsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outer_Inner_LabelsEntryPortEncoder : Outer_Inner_LabelsEntry -> JE.Value
+outer_Inner_LabelsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (JE.string v.value)
+        ]
+
+
+type alias Outer_Inner_ChildrenEntry =
+    { key : Int -- 1
+    , value : Maybe Outer -- 2
+    }
+
+
+defaultOuter_Inner_ChildrenEntry : Outer_Inner_ChildrenEntry
+defaultOuter_Inner_ChildrenEntry =
+  {key = 0
+  , value = Nothing
+  }
+
+
+-- outer_Inner_ChildrenEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+outer_Inner_ChildrenEntryPortDecoder : JD.Decoder Outer_Inner_ChildrenEntry
+outer_Inner_ChildrenEntryPortDecoder =
+    JD.lazy <| \_ -> decode Outer_Inner_ChildrenEntry
+        |> idxWithDefault 0 intDecoder 0
+        |> maybeIdx 1 outerPortDecoder
+
+
+-- outer_Inner_ChildrenEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+outer_Inner_ChildrenEntryPortEncoder : Outer_Inner_ChildrenEntry -> JE.Value
+outer_Inner_ChildrenEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.int v.key)
+        , (maybeEncoder outerPortEncoder v.value)
+        ]
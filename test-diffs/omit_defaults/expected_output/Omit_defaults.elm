@@ -0,0 +1,273 @@
+module Omit_defaults exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: omit_defaults.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- omitWhen encodes null in place of default values.  The javascript
+message constructor treats empty slots in the backing array as unset,
+which keeps them out of the serialized message.
+-}
+omitWhen : (a -> Bool) -> (a -> JE.Value) -> a -> JE.Value
+omitWhen isDefault enc v =
+    if isDefault v then
+        JE.null
+
+    else
+        enc v
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type Level
+    = LevelLow -- 0
+    | LevelHigh -- 1
+
+
+levelPortDecoder : JD.Decoder Level
+levelPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    LevelLow
+
+                1 ->
+                    LevelHigh
+
+                _ ->
+                    LevelLow
+    in
+        JD.map lookup JD.int
+
+
+levelDefault : Level
+levelDefault = LevelLow
+
+
+levelPortEncoder : Level -> JE.Value
+levelPortEncoder v =
+    let
+        lookup s =
+            case s of
+                LevelLow ->
+                    0
+
+                LevelHigh ->
+                    1
+
+    in
+        JE.int <| lookup v
+
+
+type alias Child =
+    { name : String -- 1
+    }
+
+
+defaultChild : Child
+defaultChild =
+  {name = ""
+  }
+
+
+-- childPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+childPortDecoder : JD.Decoder Child
+childPortDecoder =
+    JD.lazy <| \_ -> decode Child
+        |> idxWithDefault 0 JD.string ""
+
+
+-- childPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+childPortEncoder : Child -> JE.Value
+childPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (omitWhen ((==) "") JE.string v.name)
+        ]
+
+
+type alias Settings =
+    { name : String -- 1
+    , count : Int -- 2
+    , ratio : Float -- 3
+    , level : Level -- 4
+    , tags : List String -- 5
+    , limits : Dict.Dict String Int -- 6
+    , child : Maybe Child -- 7
+    , data : Bytes -- 8
+    }
+
+
+defaultSettings : Settings
+defaultSettings =
+  {name = ""
+  , count = 0
+  , ratio = 0
+  , level = levelDefault
+  , tags = []
+  , limits = Dict.empty
+  , child = Nothing
+  , data = []
+  }
+
+
+-- settingsPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+settingsPortDecoder : JD.Decoder Settings
+settingsPortDecoder =
+    JD.lazy <| \_ -> decode Settings
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+        |> idxWithDefault 2 JD.float 0
+        |> idxWithDefault 3 levelPortDecoder levelDefault
+        |> idxWithDefault 4 (JD.list JD.string) []
+        |> mapEntries 5 JD.string intDecoder
+        |> maybeIdx 6 childPortDecoder
+        |> idxWithDefault 7 bytesFieldDecoder []
+
+
+-- settingsPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+settingsPortEncoder : Settings -> JE.Value
+settingsPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (omitWhen ((==) "") JE.string v.name)
+        , (omitWhen ((==) 0) numericStringEncoder v.count)
+        , (omitWhen ((==) 0) JE.float v.ratio)
+        , (omitWhen ((==) levelDefault) levelPortEncoder v.level)
+        , (omitWhen List.isEmpty (JE.list JE.string) v.tags)
+        , (omitWhen Dict.isEmpty (mapEntriesFieldEncoder JE.string JE.int) v.limits)
+        , (maybeEncoder childPortEncoder v.child)
+        , (omitWhen ((==) []) bytesFieldEncoder v.data)
+        ]
+
+
+type alias Settings_LimitsEntry =
+    { key : String -- 1
+    , value : Int -- 2
+    }
+
+
+defaultSettings_LimitsEntry : Settings_LimitsEntry
+defaultSettings_LimitsEntry =
+  {key = ""
+  , value = 0
+  }
+
+
+-- settings_LimitsEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+settings_LimitsEntryPortDecoder : JD.Decoder Settings_LimitsEntry
+settings_LimitsEntryPortDecoder =
+    JD.lazy <| \_ -> decode Settings_LimitsEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+
+
+-- settings_LimitsEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+settings_LimitsEntryPortEncoder : Settings_LimitsEntry -> JE.Value
+settings_LimitsEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (omitWhen ((==) "") JE.string v.key)
+        , (omitWhen ((==) 0) JE.int v.value)
+        ]
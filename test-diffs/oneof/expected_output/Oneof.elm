@@ -11,127 +11,237 @@ import Json.Decode as JD
 import Json.Encode as JE
 
 
-uselessDeclarationToPreventErrorDueToEmptyOutputFile = 42
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
 
 
 type alias Foo =
-    { firstOneof : FirstOneof
-    , secondOneof : SecondOneof
+    { firstOneof : Foo_FirstOneof
+    , secondOneof : Foo_SecondOneof
     }
 
 
-fooDecoder : JD.Decoder Foo
-fooDecoder =
-    JD.lazy <| \_ -> decode Foo
-        |> field firstOneofDecoder
-        |> field secondOneofDecoder
+defaultFoo : Foo
+defaultFoo =
+  {firstOneof = Foo_FirstOneofUnspecified
+  , secondOneof = Foo_SecondOneofUnspecified
+  }
 
 
-fooEncoder : Foo -> JE.Value
-fooEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (firstOneofEncoder v.firstOneof)
-        , (secondOneofEncoder v.secondOneof)
+-- fooPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+fooPortDecoder : JD.Decoder Foo
+fooPortDecoder =
+    JD.lazy <| \_ -> decode Foo
+        |> custom foo_FirstOneofPortDecoder
+        |> custom foo_SecondOneofPortDecoder
+
+
+-- fooPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+fooPortEncoder : Foo -> JE.Value
+fooPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (foo_FirstOneofPortEncoder 1 v.firstOneof)
+        , (foo_FirstOneofPortEncoder 2 v.firstOneof)
+        , (foo_SecondOneofPortEncoder 3 v.secondOneof)
+        , (foo_SecondOneofPortEncoder 4 v.secondOneof)
         ]
 
 
-type FirstOneof
-    = FirstOneofUnspecified
-    | StringField String
-    | IntField Int
+type Foo_FirstOneof
+    = Foo_FirstOneofUnspecified
+    | Foo_StringField String -- 1
+    | Foo_IntField Int -- 2
 
 
-firstOneofDecoder : JD.Decoder FirstOneof
-firstOneofDecoder =
+foo_FirstOneofPortDecoder : JD.Decoder Foo_FirstOneof
+foo_FirstOneofPortDecoder =
     JD.lazy <| \_ -> JD.oneOf
-        [ JD.map StringField (JD.field "stringField" JD.string)
-        , JD.map IntField (JD.field "intField" intDecoder)
-        , JD.succeed FirstOneofUnspecified
+        [ JD.map Foo_StringField (JD.index 0 (failOnNull JD.string))
+        , JD.map Foo_IntField (JD.index 1 (failOnNull intDecoder))
+        , JD.succeed Foo_FirstOneofUnspecified
         ]
 
 
-firstOneofEncoder : FirstOneof -> Maybe ( String, JE.Value )
-firstOneofEncoder v =
+foo_FirstOneofPortEncoder : Int -> Foo_FirstOneof -> JE.Value
+foo_FirstOneofPortEncoder idx v =
     case v of
-        FirstOneofUnspecified ->
-            Nothing
+        Foo_FirstOneofUnspecified ->
+            JE.null
 
-        StringField x ->
-            Just ( "stringField", JE.string x )
+        Foo_StringField x ->
+            if idx == 1 then JE.string x else JE.null
 
-        IntField x ->
-            Just ( "intField", JE.int x )
+        Foo_IntField x ->
+            if idx == 2 then JE.int x else JE.null
 
 
-type SecondOneof
-    = SecondOneofUnspecified
-    | BoolField Bool
-    | OtherStringField String
+type Foo_SecondOneof
+    = Foo_SecondOneofUnspecified
+    | Foo_BoolField Bool -- 3
+    | Foo_OtherStringField String -- 4
 
 
-secondOneofDecoder : JD.Decoder SecondOneof
-secondOneofDecoder =
+foo_SecondOneofPortDecoder : JD.Decoder Foo_SecondOneof
+foo_SecondOneofPortDecoder =
     JD.lazy <| \_ -> JD.oneOf
-        [ JD.map BoolField (JD.field "boolField" JD.bool)
-        , JD.map OtherStringField (JD.field "otherStringField" JD.string)
-        , JD.succeed SecondOneofUnspecified
+        [ JD.map Foo_BoolField (JD.index 2 (failOnNull JD.bool))
+        , JD.map Foo_OtherStringField (JD.index 3 (failOnNull JD.string))
+        , JD.succeed Foo_SecondOneofUnspecified
         ]
 
 
-secondOneofEncoder : SecondOneof -> Maybe ( String, JE.Value )
-secondOneofEncoder v =
+foo_SecondOneofPortEncoder : Int -> Foo_SecondOneof -> JE.Value
+foo_SecondOneofPortEncoder idx v =
     case v of
-        SecondOneofUnspecified ->
-            Nothing
+        Foo_SecondOneofUnspecified ->
+            JE.null
 
-        BoolField x ->
-            Just ( "boolField", JE.bool x )
+        Foo_BoolField x ->
+            if idx == 3 then JE.bool x else JE.null
 
-        OtherStringField x ->
-            Just ( "otherStringField", JE.string x )
+        Foo_OtherStringField x ->
+            if idx == 4 then JE.string x else JE.null
 
 
 type alias Foo2 =
-    { firstOneof : FirstOneof
+    { firstOneof : Foo2_FirstOneof
     }
 
 
-foo2Decoder : JD.Decoder Foo2
-foo2Decoder =
-    JD.lazy <| \_ -> decode Foo2
-        |> field firstOneofDecoder
+defaultFoo2 : Foo2
+defaultFoo2 =
+  {firstOneof = Foo2_FirstOneofUnspecified
+  }
 
 
-foo2Encoder : Foo2 -> JE.Value
-foo2Encoder v =
-    JE.object <| List.filterMap identity <|
-        [ (firstOneofEncoder v.firstOneof)
+-- foo2PortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+foo2PortDecoder : JD.Decoder Foo2
+foo2PortDecoder =
+    JD.lazy <| \_ -> decode Foo2
+        |> custom foo2_FirstOneofPortDecoder
+
+
+-- foo2PortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+foo2PortEncoder : Foo2 -> JE.Value
+foo2PortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (foo2_FirstOneofPortEncoder 1 v.firstOneof)
+        , (foo2_FirstOneofPortEncoder 2 v.firstOneof)
         ]
 
 
-type FirstOneof
-    = FirstOneofUnspecified
-    | StringField String
-    | IntField Int
+type Foo2_FirstOneof
+    = Foo2_FirstOneofUnspecified
+    | Foo2_StringField String -- 1
+    | Foo2_IntField Int -- 2
 
 
-firstOneofDecoder : JD.Decoder FirstOneof
-firstOneofDecoder =
+foo2_FirstOneofPortDecoder : JD.Decoder Foo2_FirstOneof
+foo2_FirstOneofPortDecoder =
     JD.lazy <| \_ -> JD.oneOf
-        [ JD.map StringField (JD.field "stringField" JD.string)
-        , JD.map IntField (JD.field "intField" intDecoder)
-        , JD.succeed FirstOneofUnspecified
+        [ JD.map Foo2_StringField (JD.index 0 (failOnNull JD.string))
+        , JD.map Foo2_IntField (JD.index 1 (failOnNull intDecoder))
+        , JD.succeed Foo2_FirstOneofUnspecified
         ]
 
 
-firstOneofEncoder : FirstOneof -> Maybe ( String, JE.Value )
-firstOneofEncoder v =
+foo2_FirstOneofPortEncoder : Int -> Foo2_FirstOneof -> JE.Value
+foo2_FirstOneofPortEncoder idx v =
     case v of
-        FirstOneofUnspecified ->
-            Nothing
+        Foo2_FirstOneofUnspecified ->
+            JE.null
 
-        StringField x ->
-            Just ( "stringField", JE.string x )
+        Foo2_StringField x ->
+            if idx == 1 then JE.string x else JE.null
 
-        IntField x ->
-            Just ( "intField", JE.int x )
+        Foo2_IntField x ->
+            if idx == 2 then JE.int x else JE.null
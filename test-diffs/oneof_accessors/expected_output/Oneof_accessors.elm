@@ -0,0 +1,339 @@
+module Oneof_accessors exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: oneof_accessors.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Shape =
+    { kind : Shape_Kind
+    , measure : Shape_Measure
+    }
+
+
+defaultShape : Shape
+defaultShape =
+  {kind = Shape_KindUnspecified
+  , measure = Shape_MeasureUnspecified
+  }
+
+
+-- shapePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+shapePortDecoder : JD.Decoder Shape
+shapePortDecoder =
+    JD.lazy <| \_ -> decode Shape
+        |> custom shape_KindPortDecoder
+        |> custom shape_MeasurePortDecoder
+
+
+-- shapePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+shapePortEncoder : Shape -> JE.Value
+shapePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (shape_KindPortEncoder 1 v.kind)
+        , (shape_KindPortEncoder 2 v.kind)
+        , (shape_KindPortEncoder 3 v.kind)
+        , (shape_MeasurePortEncoder 4 v.measure)
+        ]
+
+
+type Shape_Kind
+    = Shape_KindUnspecified
+    | Shape_Round Shape_Circle -- 1
+    | Shape_Side Int -- 2
+    | Shape_Corners Int -- 3
+
+
+shape_KindPortDecoder : JD.Decoder Shape_Kind
+shape_KindPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Shape_Round (JD.index 0 (failOnNull shape_CirclePortDecoder))
+        , JD.map Shape_Side (JD.index 1 (failOnNull intDecoder))
+        , JD.map Shape_Corners (JD.index 2 (failOnNull intValueDecoder))
+        , JD.succeed Shape_KindUnspecified
+        ]
+
+
+shape_KindPortEncoder : Int -> Shape_Kind -> JE.Value
+shape_KindPortEncoder idx v =
+    case v of
+        Shape_KindUnspecified ->
+            JE.null
+
+        Shape_Round x ->
+            if idx == 1 then shape_CirclePortEncoder x else JE.null
+
+        Shape_Side x ->
+            if idx == 2 then JE.int x else JE.null
+
+        Shape_Corners x ->
+            if idx == 3 then intValueEncoder x else JE.null
+
+
+getShape_Round : Shape_Kind -> Maybe Shape_Circle
+getShape_Round v =
+    case v of
+        Shape_Round x ->
+            Just x
+
+        _ ->
+            Nothing
+
+
+mapShape_Round : (Shape_Circle -> Shape_Circle) -> Shape_Kind -> Shape_Kind
+mapShape_Round f v =
+    case v of
+        Shape_Round x ->
+            Shape_Round (f x)
+
+        _ ->
+            v
+
+
+getShape_Side : Shape_Kind -> Maybe Int
+getShape_Side v =
+    case v of
+        Shape_Side x ->
+            Just x
+
+        _ ->
+            Nothing
+
+
+mapShape_Side : (Int -> Int) -> Shape_Kind -> Shape_Kind
+mapShape_Side f v =
+    case v of
+        Shape_Side x ->
+            Shape_Side (f x)
+
+        _ ->
+            v
+
+
+getShape_Corners : Shape_Kind -> Maybe Int
+getShape_Corners v =
+    case v of
+        Shape_Corners x ->
+            Just x
+
+        _ ->
+            Nothing
+
+
+mapShape_Corners : (Int -> Int) -> Shape_Kind -> Shape_Kind
+mapShape_Corners f v =
+    case v of
+        Shape_Corners x ->
+            Shape_Corners (f x)
+
+        _ ->
+            v
+
+
+type Shape_Measure
+    = Shape_MeasureUnspecified
+    | Shape_Unit Shape_Unit -- 4
+
+
+shape_MeasurePortDecoder : JD.Decoder Shape_Measure
+shape_MeasurePortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Shape_Unit (JD.index 3 (failOnNull shape_UnitPortDecoder))
+        , JD.succeed Shape_MeasureUnspecified
+        ]
+
+
+shape_MeasurePortEncoder : Int -> Shape_Measure -> JE.Value
+shape_MeasurePortEncoder idx v =
+    case v of
+        Shape_MeasureUnspecified ->
+            JE.null
+
+        Shape_Unit x ->
+            if idx == 4 then shape_UnitPortEncoder x else JE.null
+
+
+getShape_Unit : Shape_Measure -> Maybe Shape_Unit
+getShape_Unit v =
+    case v of
+        Shape_Unit x ->
+            Just x
+
+        _ ->
+            Nothing
+
+
+mapShape_Unit : (Shape_Unit -> Shape_Unit) -> Shape_Measure -> Shape_Measure
+mapShape_Unit f v =
+    case v of
+        Shape_Unit x ->
+            Shape_Unit (f x)
+
+        _ ->
+            v
+
+
+type Shape_Unit
+    = Shape_UnitUnspecified -- 0
+    | Shape_UnitMeters -- 1
+
+
+shape_UnitPortDecoder : JD.Decoder Shape_Unit
+shape_UnitPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    Shape_UnitUnspecified
+
+                1 ->
+                    Shape_UnitMeters
+
+                _ ->
+                    Shape_UnitUnspecified
+    in
+        JD.map lookup JD.int
+
+
+shape_UnitDefault : Shape_Unit
+shape_UnitDefault = Shape_UnitUnspecified
+
+
+shape_UnitPortEncoder : Shape_Unit -> JE.Value
+shape_UnitPortEncoder v =
+    let
+        lookup s =
+            case s of
+                Shape_UnitUnspecified ->
+                    0
+
+                Shape_UnitMeters ->
+                    1
+
+    in
+        JE.int <| lookup v
+
+
+type alias Shape_Circle =
+    { radius : Float -- 1
+    }
+
+
+defaultShape_Circle : Shape_Circle
+defaultShape_Circle =
+  {radius = 0
+  }
+
+
+-- shape_CirclePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+shape_CirclePortDecoder : JD.Decoder Shape_Circle
+shape_CirclePortDecoder =
+    JD.lazy <| \_ -> decode Shape_Circle
+        |> idxWithDefault 0 JD.float 0
+
+
+-- shape_CirclePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+shape_CirclePortEncoder : Shape_Circle -> JE.Value
+shape_CirclePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.float v.radius)
+        ]
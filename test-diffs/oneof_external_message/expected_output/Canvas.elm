@@ -0,0 +1,169 @@
+module Canvas exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: canvas.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Shapes exposing (..)
+
+import Legacy.Square exposing (..)
+
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Layer =
+    { name : String -- 1
+    , shape : Layer_Shape
+    }
+
+
+defaultLayer : Layer
+defaultLayer =
+  {name = ""
+  , shape = Layer_ShapeUnspecified
+  }
+
+
+-- layerPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+layerPortDecoder : JD.Decoder Layer
+layerPortDecoder =
+    JD.lazy <| \_ -> decode Layer
+        |> idxWithDefault 0 JD.string ""
+        |> custom layer_ShapePortDecoder
+
+
+-- layerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+layerPortEncoder : Layer -> JE.Value
+layerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        , (layer_ShapePortEncoder 2 v.shape)
+        , (layer_ShapePortEncoder 3 v.shape)
+        , (layer_ShapePortEncoder 4 v.shape)
+        ]
+
+
+type Layer_Shape
+    = Layer_ShapeUnspecified
+    | Layer_Circle Circle -- 2
+    | Layer_Square Shapes.Square -- 3
+    | Layer_LegacySquare Legacy.Square.Square -- 4
+
+
+layer_ShapePortDecoder : JD.Decoder Layer_Shape
+layer_ShapePortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Layer_Circle (JD.index 1 (failOnNull circlePortDecoder))
+        , JD.map Layer_Square (JD.index 2 (failOnNull Shapes.squarePortDecoder))
+        , JD.map Layer_LegacySquare (JD.index 3 (failOnNull Legacy.Square.squarePortDecoder))
+        , JD.succeed Layer_ShapeUnspecified
+        ]
+
+
+layer_ShapePortEncoder : Int -> Layer_Shape -> JE.Value
+layer_ShapePortEncoder idx v =
+    case v of
+        Layer_ShapeUnspecified ->
+            JE.null
+
+        Layer_Circle x ->
+            if idx == 2 then circlePortEncoder x else JE.null
+
+        Layer_Square x ->
+            if idx == 3 then Shapes.squarePortEncoder x else JE.null
+
+        Layer_LegacySquare x ->
+            if idx == 4 then Legacy.Square.squarePortEncoder x else JE.null
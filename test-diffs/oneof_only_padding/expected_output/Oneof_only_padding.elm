@@ -0,0 +1,158 @@
+module Oneof_only_padding exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: oneof_only_padding.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Event =
+    { payload : Event_Payload
+    }
+
+
+defaultEvent : Event
+defaultEvent =
+  {payload = Event_PayloadUnspecified
+  }
+
+
+-- eventPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+eventPortDecoder : JD.Decoder Event
+eventPortDecoder =
+    JD.lazy <| \_ -> decode Event
+        |> custom event_PayloadPortDecoder
+
+
+-- eventPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+eventPortEncoder : Event -> JE.Value
+eventPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ JE.null
+        , JE.null
+        , (event_PayloadPortEncoder 3 v.payload)
+        , JE.null
+        , (event_PayloadPortEncoder 5 v.payload)
+        ]
+
+
+type Event_Payload
+    = Event_PayloadUnspecified
+    | Event_Created String -- 3
+    | Event_Deleted Int -- 5
+
+
+event_PayloadPortDecoder : JD.Decoder Event_Payload
+event_PayloadPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Event_Created (JD.index 2 (failOnNull JD.string))
+        , JD.map Event_Deleted (JD.index 4 (failOnNull intDecoder))
+        , JD.succeed Event_PayloadUnspecified
+        ]
+
+
+event_PayloadPortEncoder : Int -> Event_Payload -> JE.Value
+event_PayloadPortEncoder idx v =
+    case v of
+        Event_PayloadUnspecified ->
+            JE.null
+
+        Event_Created x ->
+            if idx == 3 then JE.string x else JE.null
+
+        Event_Deleted x ->
+            if idx == 5 then JE.int x else JE.null
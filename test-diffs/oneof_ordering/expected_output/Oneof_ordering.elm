@@ -0,0 +1,221 @@
+module Oneof_ordering exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: oneof_ordering.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Circle =
+    { radius : Int -- 1
+    }
+
+
+defaultCircle : Circle
+defaultCircle =
+  {radius = 0
+  }
+
+
+-- circlePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+circlePortDecoder : JD.Decoder Circle
+circlePortDecoder =
+    JD.lazy <| \_ -> decode Circle
+        |> idxWithDefault 0 intDecoder 0
+
+
+-- circlePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+circlePortEncoder : Circle -> JE.Value
+circlePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.int v.radius)
+        ]
+
+
+type alias Square =
+    { side : Int -- 1
+    }
+
+
+defaultSquare : Square
+defaultSquare =
+  {side = 0
+  }
+
+
+-- squarePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+squarePortDecoder : JD.Decoder Square
+squarePortDecoder =
+    JD.lazy <| \_ -> decode Square
+        |> idxWithDefault 0 intDecoder 0
+
+
+-- squarePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+squarePortEncoder : Square -> JE.Value
+squarePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.int v.side)
+        ]
+
+
+type alias Shape =
+    { kind : Shape_Kind
+    }
+
+
+defaultShape : Shape
+defaultShape =
+  {kind = Shape_KindUnspecified
+  }
+
+
+-- shapePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+shapePortDecoder : JD.Decoder Shape
+shapePortDecoder =
+    JD.lazy <| \_ -> decode Shape
+        |> custom shape_KindPortDecoder
+
+
+-- shapePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+shapePortEncoder : Shape -> JE.Value
+shapePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (shape_KindPortEncoder 1 v.kind)
+        , (shape_KindPortEncoder 2 v.kind)
+        , (shape_KindPortEncoder 3 v.kind)
+        ]
+
+
+type Shape_Kind
+    = Shape_KindUnspecified
+    | Shape_Name String -- 1
+    | Shape_Circle Circle -- 2
+    | Shape_Square Square -- 3
+
+
+shape_KindPortDecoder : JD.Decoder Shape_Kind
+shape_KindPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Shape_Name (JD.index 0 (failOnNull JD.string))
+        , JD.map Shape_Circle (JD.index 1 (failOnNull circlePortDecoder))
+        , JD.map Shape_Square (JD.index 2 (failOnNull squarePortDecoder))
+        , JD.succeed Shape_KindUnspecified
+        ]
+
+
+shape_KindPortEncoder : Int -> Shape_Kind -> JE.Value
+shape_KindPortEncoder idx v =
+    case v of
+        Shape_KindUnspecified ->
+            JE.null
+
+        Shape_Name x ->
+            if idx == 1 then JE.string x else JE.null
+
+        Shape_Circle x ->
+            if idx == 2 then circlePortEncoder x else JE.null
+
+        Shape_Square x ->
+            if idx == 3 then squarePortEncoder x else JE.null
@@ -0,0 +1,161 @@
+module Oneof_well_known_types exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: oneof_well_known_types.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Deadline =
+    { when : Deadline_When
+    }
+
+
+defaultDeadline : Deadline
+defaultDeadline =
+  {when = Deadline_WhenUnspecified
+  }
+
+
+-- deadlinePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+deadlinePortDecoder : JD.Decoder Deadline
+deadlinePortDecoder =
+    JD.lazy <| \_ -> decode Deadline
+        |> custom deadline_WhenPortDecoder
+
+
+-- deadlinePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+deadlinePortEncoder : Deadline -> JE.Value
+deadlinePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (deadline_WhenPortEncoder 1 v.when)
+        , (deadline_WhenPortEncoder 2 v.when)
+        , (deadline_WhenPortEncoder 3 v.when)
+        ]
+
+
+type Deadline_When
+    = Deadline_WhenUnspecified
+    | Deadline_At Timestamp -- 1
+    | Deadline_DaysFromNow Int -- 2
+    | Deadline_Description String -- 3
+
+
+deadline_WhenPortDecoder : JD.Decoder Deadline_When
+deadline_WhenPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Deadline_At (JD.index 0 (failOnNull timestampDecoder))
+        , JD.map Deadline_DaysFromNow (JD.index 1 (failOnNull intValueDecoder))
+        , JD.map Deadline_Description (JD.index 2 (failOnNull stringValueDecoder))
+        , JD.succeed Deadline_WhenUnspecified
+        ]
+
+
+deadline_WhenPortEncoder : Int -> Deadline_When -> JE.Value
+deadline_WhenPortEncoder idx v =
+    case v of
+        Deadline_WhenUnspecified ->
+            JE.null
+
+        Deadline_At x ->
+            if idx == 1 then timestampEncoder x else JE.null
+
+        Deadline_DaysFromNow x ->
+            if idx == 2 then intValueEncoder x else JE.null
+
+        Deadline_Description x ->
+            if idx == 3 then stringValueEncoder x else JE.null
@@ -0,0 +1,206 @@
+module Proto2_groups exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: proto2_groups.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias SearchResponse =
+    { result : List SearchResponse_Result -- 1
+    , paging : Maybe SearchResponse_Paging -- 4
+    , total : Int -- 6
+    }
+
+
+defaultSearchResponse : SearchResponse
+defaultSearchResponse =
+  {result = []
+  , paging = Nothing
+  , total = 0
+  }
+
+
+-- searchResponsePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+searchResponsePortDecoder : JD.Decoder SearchResponse
+searchResponsePortDecoder =
+    JD.lazy <| \_ -> decode SearchResponse
+        |> idxWithDefault 0 (JD.list searchResponse_ResultPortDecoder) []
+        |> maybeIdx 3 searchResponse_PagingPortDecoder
+        |> idxWithDefault 5 intDecoder 0
+
+
+-- searchResponsePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+searchResponsePortEncoder : SearchResponse -> JE.Value
+searchResponsePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.list searchResponse_ResultPortEncoder v.result)
+        , JE.null
+        , JE.null
+        , (maybeEncoder searchResponse_PagingPortEncoder v.paging)
+        , JE.null
+        , (JE.int v.total)
+        ]
+
+
+type alias SearchResponse_Result =
+    { url : String -- 2
+    , title : String -- 3
+    }
+
+
+defaultSearchResponse_Result : SearchResponse_Result
+defaultSearchResponse_Result =
+  {url = ""
+  , title = ""
+  }
+
+
+-- searchResponse_ResultPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+searchResponse_ResultPortDecoder : JD.Decoder SearchResponse_Result
+searchResponse_ResultPortDecoder =
+    JD.lazy <| \_ -> decode SearchResponse_Result
+        |> idxWithDefault 1 JD.string ""
+        |> idxWithDefault 2 JD.string ""
+
+
+-- searchResponse_ResultPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+searchResponse_ResultPortEncoder : SearchResponse_Result -> JE.Value
+searchResponse_ResultPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ JE.null
+        , (JE.string v.url)
+        , (JE.string v.title)
+        ]
+
+
+type alias SearchResponse_Paging =
+    { page : Int -- 5
+    }
+
+
+defaultSearchResponse_Paging : SearchResponse_Paging
+defaultSearchResponse_Paging =
+  {page = 0
+  }
+
+
+-- searchResponse_PagingPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+searchResponse_PagingPortDecoder : JD.Decoder SearchResponse_Paging
+searchResponse_PagingPortDecoder =
+    JD.lazy <| \_ -> decode SearchResponse_Paging
+        |> idxWithDefault 4 intDecoder 0
+
+
+-- searchResponse_PagingPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+searchResponse_PagingPortEncoder : SearchResponse_Paging -> JE.Value
+searchResponse_PagingPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ JE.null
+        , JE.null
+        , JE.null
+        , JE.null
+        , (JE.int v.page)
+        ]
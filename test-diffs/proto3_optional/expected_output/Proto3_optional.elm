@@ -0,0 +1,197 @@
+module Proto3_optional exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: proto3_optional.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias SomeMessage =
+    { name : String -- 1
+    }
+
+
+defaultSomeMessage : SomeMessage
+defaultSomeMessage =
+  {name = ""
+  }
+
+
+-- someMessagePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+someMessagePortDecoder : JD.Decoder SomeMessage
+someMessagePortDecoder =
+    JD.lazy <| \_ -> decode SomeMessage
+        |> idxWithDefault 0 JD.string ""
+
+
+-- someMessagePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+someMessagePortEncoder : SomeMessage -> JE.Value
+someMessagePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        ]
+
+
+type alias WithOptionals =
+    { count : Maybe Int -- 1
+    , message : Maybe SomeMessage -- 2
+    , plainMessage : Maybe SomeMessage -- 3
+    , choice : WithOptionals_Choice
+    }
+
+
+defaultWithOptionals : WithOptionals
+defaultWithOptionals =
+  {count = Nothing
+  , message = Nothing
+  , plainMessage = Nothing
+  , choice = WithOptionals_ChoiceUnspecified
+  }
+
+
+-- withOptionalsPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+withOptionalsPortDecoder : JD.Decoder WithOptionals
+withOptionalsPortDecoder =
+    JD.lazy <| \_ -> decode WithOptionals
+        |> maybeIdx 0 intDecoder
+        |> maybeIdx 1 someMessagePortDecoder
+        |> maybeIdx 2 someMessagePortDecoder
+        |> custom withOptionals_ChoicePortDecoder
+
+
+-- withOptionalsPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+withOptionalsPortEncoder : WithOptionals -> JE.Value
+withOptionalsPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (maybeEncoder JE.int v.count)
+        , (maybeEncoder someMessagePortEncoder v.message)
+        , (maybeEncoder someMessagePortEncoder v.plainMessage)
+        , (withOptionals_ChoicePortEncoder 4 v.choice)
+        , (withOptionals_ChoicePortEncoder 5 v.choice)
+        ]
+
+
+type WithOptionals_Choice
+    = WithOptionals_ChoiceUnspecified
+    | WithOptionals_Text String -- 4
+    | WithOptionals_Number Int -- 5
+
+
+withOptionals_ChoicePortDecoder : JD.Decoder WithOptionals_Choice
+withOptionals_ChoicePortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map WithOptionals_Text (JD.index 3 (failOnNull JD.string))
+        , JD.map WithOptionals_Number (JD.index 4 (failOnNull intDecoder))
+        , JD.succeed WithOptionals_ChoiceUnspecified
+        ]
+
+
+withOptionals_ChoicePortEncoder : Int -> WithOptionals_Choice -> JE.Value
+withOptionals_ChoicePortEncoder idx v =
+    case v of
+        WithOptionals_ChoiceUnspecified ->
+            JE.null
+
+        WithOptionals_Text x ->
+            if idx == 4 then JE.string x else JE.null
+
+        WithOptionals_Number x ->
+            if idx == 5 then JE.int x else JE.null
@@ -0,0 +1,32 @@
+module Proto.Registry exposing (messageDecoder, messageDecoders)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+
+import Dict
+import Json.Decode as JD
+import Json.Encode as JE
+import Shapes.Shapes
+import Registry
+
+
+{- messageDecoders maps the full proto name of each message to a decoder that
+checks that a value is the message, returning it re-encoded.
+-}
+messageDecoders : Dict.Dict String (JD.Decoder JE.Value)
+messageDecoders =
+    Dict.fromList
+        [ ( "registry.Drawing", JD.map Registry.drawingPortEncoder Registry.drawingPortDecoder )
+        , ( "shapes.Circle", JD.map Shapes.Shapes.circlePortEncoder Shapes.Shapes.circlePortDecoder )
+        , ( "shapes.Polygon", JD.map Shapes.Shapes.polygonPortEncoder Shapes.Shapes.polygonPortDecoder )
+        , ( "shapes.Polygon.Point", JD.map Shapes.Shapes.polygon_PointPortEncoder Shapes.Shapes.polygon_PointPortDecoder )
+        ]
+
+
+{- messageDecoder looks up the decoder of a message by its full proto name,
+e.g. "pkg.Foo".
+-}
+messageDecoder : String -> Maybe (JD.Decoder JE.Value)
+messageDecoder name =
+    Dict.get name messageDecoders
@@ -0,0 +1,190 @@
+module Shapes.Shapes exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: shapes/shapes.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Circle =
+    { radius : Float -- 1
+    }
+
+
+defaultCircle : Circle
+defaultCircle =
+  {radius = 0
+  }
+
+
+-- circlePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+circlePortDecoder : JD.Decoder Circle
+circlePortDecoder =
+    JD.lazy <| \_ -> decode Circle
+        |> idxWithDefault 0 JD.float 0
+
+
+-- circlePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+circlePortEncoder : Circle -> JE.Value
+circlePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.float v.radius)
+        ]
+
+
+type alias Polygon =
+    { points : List Polygon_Point -- 1
+    }
+
+
+defaultPolygon : Polygon
+defaultPolygon =
+  {points = []
+  }
+
+
+-- polygonPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+polygonPortDecoder : JD.Decoder Polygon
+polygonPortDecoder =
+    JD.lazy <| \_ -> decode Polygon
+        |> idxWithDefault 0 (JD.list polygon_PointPortDecoder) []
+
+
+-- polygonPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+polygonPortEncoder : Polygon -> JE.Value
+polygonPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.list polygon_PointPortEncoder v.points)
+        ]
+
+
+type alias Polygon_Point =
+    { x : Float -- 1
+    , y : Float -- 2
+    }
+
+
+defaultPolygon_Point : Polygon_Point
+defaultPolygon_Point =
+  {x = 0
+  , y = 0
+  }
+
+
+-- polygon_PointPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+polygon_PointPortDecoder : JD.Decoder Polygon_Point
+polygon_PointPortDecoder =
+    JD.lazy <| \_ -> decode Polygon_Point
+        |> idxWithDefault 0 JD.float 0
+        |> idxWithDefault 1 JD.float 0
+
+
+-- polygon_PointPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+polygon_PointPortEncoder : Polygon_Point -> JE.Value
+polygon_PointPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.float v.x)
+        , (JE.float v.y)
+        ]
@@ -11,7 +11,89 @@ import Json.Decode as JD
 import Json.Encode as JE
 
 
-uselessDeclarationToPreventErrorDueToEmptyOutputFile = 42
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
 
 
 type Enum
@@ -21,52 +103,52 @@ type Enum
     | EnumValue123 -- 123
 
 
-enumDecoder : JD.Decoder Enum
-enumDecoder =
+enumPortDecoder : JD.Decoder Enum
+enumPortDecoder =
     let
-        lookup s =
-            case s of
-                "ENUM_VALUE_DEFAULT" ->
+        lookup v =
+            case v of
+                0 ->
                     EnumValueDefault
 
-                "ENUM_VALUE_1" ->
+                1 ->
                     EnumValue1
 
-                "ENUM_VALUE_2" ->
+                2 ->
                     EnumValue2
 
-                "ENUM_VALUE_123" ->
+                123 ->
                     EnumValue123
 
                 _ ->
                     EnumValueDefault
     in
-        JD.map lookup JD.string
+        JD.map lookup JD.int
 
 
 enumDefault : Enum
 enumDefault = EnumValueDefault
 
 
-enumEncoder : Enum -> JE.Value
-enumEncoder v =
+enumPortEncoder : Enum -> JE.Value
+enumPortEncoder v =
     let
         lookup s =
             case s of
                 EnumValueDefault ->
-                    "ENUM_VALUE_DEFAULT"
+                    0
 
                 EnumValue1 ->
-                    "ENUM_VALUE_1"
+                    1
 
                 EnumValue2 ->
-                    "ENUM_VALUE_2"
+                    2
 
                 EnumValue123 ->
-                    "ENUM_VALUE_123"
+                    123
 
     in
-        JE.string <| lookup v
+        JE.int <| lookup v
 
 
 type alias SubMessage =
@@ -74,16 +156,28 @@ type alias SubMessage =
     }
 
 
-subMessageDecoder : JD.Decoder SubMessage
-subMessageDecoder =
+defaultSubMessage : SubMessage
+defaultSubMessage =
+  {int32Field = 0
+  }
+
+
+-- subMessagePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+subMessagePortDecoder : JD.Decoder SubMessage
+subMessagePortDecoder =
     JD.lazy <| \_ -> decode SubMessage
-        |> required "int32Field" intDecoder 0
+        |> idxWithDefault 0 intDecoder 0
 
 
-subMessageEncoder : SubMessage -> JE.Value
-subMessageEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (requiredFieldEncoder "int32Field" JE.int 0 v.int32Field)
+-- subMessagePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+subMessagePortEncoder : SubMessage -> JE.Value
+subMessagePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.int v.int32Field)
         ]
 
 
@@ -111,54 +205,85 @@ type alias Foo =
     }
 
 
-fooDecoder : JD.Decoder Foo
-fooDecoder =
+defaultFoo : Foo
+defaultFoo =
+  {doubleField = 0
+  , floatField = 0
+  , int32Field = 0
+  , int64Field = 0
+  , uint32Field = 0
+  , uint64Field = 0
+  , sint32Field = 0
+  , sint64Field = 0
+  , fixed32Field = 0
+  , fixed64Field = 0
+  , sfixed32Field = 0
+  , sfixed64Field = 0
+  , boolField = False
+  , stringField = ""
+  , enumField = enumDefault
+  , subMessage = Nothing
+  , repeatedInt64Field = []
+  , repeatedEnumField = []
+  , nestedMessageField = Nothing
+  , nestedEnumField = foo_NestedEnumDefault
+  }
+
+
+-- fooPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+fooPortDecoder : JD.Decoder Foo
+fooPortDecoder =
     JD.lazy <| \_ -> decode Foo
-        |> required "doubleField" JD.float 0.0
-        |> required "floatField" JD.float 0.0
-        |> required "int32Field" intDecoder 0
-        |> required "int64Field" intDecoder 0
-        |> required "uint32Field" intDecoder 0
-        |> required "uint64Field" intDecoder 0
-        |> required "sint32Field" intDecoder 0
-        |> required "sint64Field" intDecoder 0
-        |> required "fixed32Field" intDecoder 0
-        |> required "fixed64Field" intDecoder 0
-        |> required "sfixed32Field" intDecoder 0
-        |> required "sfixed64Field" intDecoder 0
-        |> required "boolField" JD.bool False
-        |> required "stringField" JD.string ""
-        |> required "enumField" enumDecoder enumDefault
-        |> optional "subMessage" subMessageDecoder
-        |> repeated "repeatedInt64Field" intDecoder
-        |> repeated "repeatedEnumField" enumDecoder
-        |> optional "nestedMessageField" foo_NestedMessageDecoder
-        |> required "nestedEnumField" foo_NestedEnumDecoder foo_NestedEnumDefault
-
-
-fooEncoder : Foo -> JE.Value
-fooEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (requiredFieldEncoder "doubleField" JE.float 0.0 v.doubleField)
-        , (requiredFieldEncoder "floatField" JE.float 0.0 v.floatField)
-        , (requiredFieldEncoder "int32Field" JE.int 0 v.int32Field)
-        , (requiredFieldEncoder "int64Field" numericStringEncoder 0 v.int64Field)
-        , (requiredFieldEncoder "uint32Field" JE.int 0 v.uint32Field)
-        , (requiredFieldEncoder "uint64Field" numericStringEncoder 0 v.uint64Field)
-        , (requiredFieldEncoder "sint32Field" JE.int 0 v.sint32Field)
-        , (requiredFieldEncoder "sint64Field" numericStringEncoder 0 v.sint64Field)
-        , (requiredFieldEncoder "fixed32Field" JE.int 0 v.fixed32Field)
-        , (requiredFieldEncoder "fixed64Field" numericStringEncoder 0 v.fixed64Field)
-        , (requiredFieldEncoder "sfixed32Field" JE.int 0 v.sfixed32Field)
-        , (requiredFieldEncoder "sfixed64Field" numericStringEncoder 0 v.sfixed64Field)
-        , (requiredFieldEncoder "boolField" JE.bool False v.boolField)
-        , (requiredFieldEncoder "stringField" JE.string "" v.stringField)
-        , (requiredFieldEncoder "enumField" enumEncoder enumDefault v.enumField)
-        , (optionalEncoder "subMessage" subMessageEncoder v.subMessage)
-        , (repeatedFieldEncoder "repeatedInt64Field" numericStringEncoder v.repeatedInt64Field)
-        , (repeatedFieldEncoder "repeatedEnumField" enumEncoder v.repeatedEnumField)
-        , (optionalEncoder "nestedMessageField" foo_NestedMessageEncoder v.nestedMessageField)
-        , (requiredFieldEncoder "nestedEnumField" foo_NestedEnumEncoder foo_NestedEnumDefault v.nestedEnumField)
+        |> idxWithDefault 0 JD.float 0
+        |> idxWithDefault 1 JD.float 0
+        |> idxWithDefault 2 intDecoder 0
+        |> idxWithDefault 3 intDecoder 0
+        |> idxWithDefault 4 intDecoder 0
+        |> idxWithDefault 5 intDecoder 0
+        |> idxWithDefault 6 intDecoder 0
+        |> idxWithDefault 7 intDecoder 0
+        |> idxWithDefault 8 intDecoder 0
+        |> idxWithDefault 9 intDecoder 0
+        |> idxWithDefault 10 intDecoder 0
+        |> idxWithDefault 11 intDecoder 0
+        |> idxWithDefault 12 JD.bool False
+        |> idxWithDefault 13 JD.string ""
+        |> idxWithDefault 14 enumPortDecoder enumDefault
+        |> maybeIdx 15 subMessagePortDecoder
+        |> idxWithDefault 16 (JD.list intDecoder) []
+        |> idxWithDefault 17 (JD.list enumPortDecoder) []
+        |> maybeIdx 18 foo_NestedMessagePortDecoder
+        |> idxWithDefault 19 foo_NestedEnumPortDecoder foo_NestedEnumDefault
+
+
+-- fooPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+fooPortEncoder : Foo -> JE.Value
+fooPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.float v.doubleField)
+        , (JE.float v.floatField)
+        , (JE.int v.int32Field)
+        , (numericStringEncoder v.int64Field)
+        , (JE.int v.uint32Field)
+        , (numericStringEncoder v.uint64Field)
+        , (JE.int v.sint32Field)
+        , (numericStringEncoder v.sint64Field)
+        , (JE.int v.fixed32Field)
+        , (numericStringEncoder v.fixed64Field)
+        , (JE.int v.sfixed32Field)
+        , (numericStringEncoder v.sfixed64Field)
+        , (JE.bool v.boolField)
+        , (JE.string v.stringField)
+        , (enumPortEncoder v.enumField)
+        , (maybeEncoder subMessagePortEncoder v.subMessage)
+        , (JE.list numericStringEncoder v.repeatedInt64Field)
+        , (JE.list enumPortEncoder v.repeatedEnumField)
+        , (maybeEncoder foo_NestedMessagePortEncoder v.nestedMessageField)
+        , (foo_NestedEnumPortEncoder v.nestedEnumField)
         ]
 
 
@@ -166,34 +291,34 @@ type Foo_NestedEnum
     = Foo_EnumValueDefault -- 0
 
 
-foo_NestedEnumDecoder : JD.Decoder Foo_NestedEnum
-foo_NestedEnumDecoder =
+foo_NestedEnumPortDecoder : JD.Decoder Foo_NestedEnum
+foo_NestedEnumPortDecoder =
     let
-        lookup s =
-            case s of
-                "ENUM_VALUE_DEFAULT" ->
+        lookup v =
+            case v of
+                0 ->
                     Foo_EnumValueDefault
 
                 _ ->
                     Foo_EnumValueDefault
     in
-        JD.map lookup JD.string
+        JD.map lookup JD.int
 
 
 foo_NestedEnumDefault : Foo_NestedEnum
 foo_NestedEnumDefault = Foo_EnumValueDefault
 
 
-foo_NestedEnumEncoder : Foo_NestedEnum -> JE.Value
-foo_NestedEnumEncoder v =
+foo_NestedEnumPortEncoder : Foo_NestedEnum -> JE.Value
+foo_NestedEnumPortEncoder v =
     let
         lookup s =
             case s of
                 Foo_EnumValueDefault ->
-                    "ENUM_VALUE_DEFAULT"
+                    0
 
     in
-        JE.string <| lookup v
+        JE.int <| lookup v
 
 
 type alias Foo_NestedMessage =
@@ -201,16 +326,28 @@ type alias Foo_NestedMessage =
     }
 
 
-foo_NestedMessageDecoder : JD.Decoder Foo_NestedMessage
-foo_NestedMessageDecoder =
+defaultFoo_NestedMessage : Foo_NestedMessage
+defaultFoo_NestedMessage =
+  {int32Field = 0
+  }
+
+
+-- foo_NestedMessagePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+foo_NestedMessagePortDecoder : JD.Decoder Foo_NestedMessage
+foo_NestedMessagePortDecoder =
     JD.lazy <| \_ -> decode Foo_NestedMessage
-        |> required "int32Field" intDecoder 0
+        |> idxWithDefault 0 intDecoder 0
 
 
-foo_NestedMessageEncoder : Foo_NestedMessage -> JE.Value
-foo_NestedMessageEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (requiredFieldEncoder "int32Field" JE.int 0 v.int32Field)
+-- foo_NestedMessagePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+foo_NestedMessagePortEncoder : Foo_NestedMessage -> JE.Value
+foo_NestedMessagePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.int v.int32Field)
         ]
 
 
@@ -219,16 +356,28 @@ type alias Foo_NestedMessage_NestedNestedMessage =
     }
 
 
-foo_NestedMessage_NestedNestedMessageDecoder : JD.Decoder Foo_NestedMessage_NestedNestedMessage
-foo_NestedMessage_NestedNestedMessageDecoder =
+defaultFoo_NestedMessage_NestedNestedMessage : Foo_NestedMessage_NestedNestedMessage
+defaultFoo_NestedMessage_NestedNestedMessage =
+  {int32Field = 0
+  }
+
+
+-- foo_NestedMessage_NestedNestedMessagePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+foo_NestedMessage_NestedNestedMessagePortDecoder : JD.Decoder Foo_NestedMessage_NestedNestedMessage
+foo_NestedMessage_NestedNestedMessagePortDecoder =
     JD.lazy <| \_ -> decode Foo_NestedMessage_NestedNestedMessage
-        |> required "int32Field" intDecoder 0
+        |> idxWithDefault 0 intDecoder 0
 
 
-foo_NestedMessage_NestedNestedMessageEncoder : Foo_NestedMessage_NestedNestedMessage -> JE.Value
-foo_NestedMessage_NestedNestedMessageEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (requiredFieldEncoder "int32Field" JE.int 0 v.int32Field)
+-- foo_NestedMessage_NestedNestedMessagePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+foo_NestedMessage_NestedNestedMessagePortEncoder : Foo_NestedMessage_NestedNestedMessage -> JE.Value
+foo_NestedMessage_NestedNestedMessagePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.int v.int32Field)
         ]
 
 
@@ -252,44 +401,71 @@ type alias FooRepeated =
     }
 
 
-fooRepeatedDecoder : JD.Decoder FooRepeated
-fooRepeatedDecoder =
+defaultFooRepeated : FooRepeated
+defaultFooRepeated =
+  {doubleField = []
+  , floatField = []
+  , int32Field = []
+  , int64Field = []
+  , uint32Field = []
+  , uint64Field = []
+  , sint32Field = []
+  , sint64Field = []
+  , fixed32Field = []
+  , fixed64Field = []
+  , sfixed32Field = []
+  , sfixed64Field = []
+  , boolField = []
+  , stringField = []
+  , enumField = []
+  , subMessage = []
+  }
+
+
+-- fooRepeatedPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+fooRepeatedPortDecoder : JD.Decoder FooRepeated
+fooRepeatedPortDecoder =
     JD.lazy <| \_ -> decode FooRepeated
-        |> repeated "doubleField" JD.float
-        |> repeated "floatField" JD.float
-        |> repeated "int32Field" intDecoder
-        |> repeated "int64Field" intDecoder
-        |> repeated "uint32Field" intDecoder
-        |> repeated "uint64Field" intDecoder
-        |> repeated "sint32Field" intDecoder
-        |> repeated "sint64Field" intDecoder
-        |> repeated "fixed32Field" intDecoder
-        |> repeated "fixed64Field" intDecoder
-        |> repeated "sfixed32Field" intDecoder
-        |> repeated "sfixed64Field" intDecoder
-        |> repeated "boolField" JD.bool
-        |> repeated "stringField" JD.string
-        |> repeated "enumField" enumDecoder
-        |> repeated "subMessage" subMessageDecoder
-
-
-fooRepeatedEncoder : FooRepeated -> JE.Value
-fooRepeatedEncoder v =
-    JE.object <| List.filterMap identity <|
-        [ (repeatedFieldEncoder "doubleField" JE.float v.doubleField)
-        , (repeatedFieldEncoder "floatField" JE.float v.floatField)
-        , (repeatedFieldEncoder "int32Field" JE.int v.int32Field)
-        , (repeatedFieldEncoder "int64Field" numericStringEncoder v.int64Field)
-        , (repeatedFieldEncoder "uint32Field" JE.int v.uint32Field)
-        , (repeatedFieldEncoder "uint64Field" numericStringEncoder v.uint64Field)
-        , (repeatedFieldEncoder "sint32Field" JE.int v.sint32Field)
-        , (repeatedFieldEncoder "sint64Field" numericStringEncoder v.sint64Field)
-        , (repeatedFieldEncoder "fixed32Field" JE.int v.fixed32Field)
-        , (repeatedFieldEncoder "fixed64Field" numericStringEncoder v.fixed64Field)
-        , (repeatedFieldEncoder "sfixed32Field" JE.int v.sfixed32Field)
-        , (repeatedFieldEncoder "sfixed64Field" numericStringEncoder v.sfixed64Field)
-        , (repeatedFieldEncoder "boolField" JE.bool v.boolField)
-        , (repeatedFieldEncoder "stringField" JE.string v.stringField)
-        , (repeatedFieldEncoder "enumField" enumEncoder v.enumField)
-        , (repeatedFieldEncoder "subMessage" subMessageEncoder v.subMessage)
+        |> idxWithDefault 0 (JD.list JD.float) []
+        |> idxWithDefault 1 (JD.list JD.float) []
+        |> idxWithDefault 2 (JD.list intDecoder) []
+        |> idxWithDefault 3 (JD.list intDecoder) []
+        |> idxWithDefault 4 (JD.list intDecoder) []
+        |> idxWithDefault 5 (JD.list intDecoder) []
+        |> idxWithDefault 6 (JD.list intDecoder) []
+        |> idxWithDefault 7 (JD.list intDecoder) []
+        |> idxWithDefault 8 (JD.list intDecoder) []
+        |> idxWithDefault 9 (JD.list intDecoder) []
+        |> idxWithDefault 10 (JD.list intDecoder) []
+        |> idxWithDefault 11 (JD.list intDecoder) []
+        |> idxWithDefault 12 (JD.list JD.bool) []
+        |> idxWithDefault 13 (JD.list JD.string) []
+        |> idxWithDefault 14 (JD.list enumPortDecoder) []
+        |> idxWithDefault 15 (JD.list subMessagePortDecoder) []
+
+
+-- fooRepeatedPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+fooRepeatedPortEncoder : FooRepeated -> JE.Value
+fooRepeatedPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.list JE.float v.doubleField)
+        , (JE.list JE.float v.floatField)
+        , (JE.list JE.int v.int32Field)
+        , (JE.list numericStringEncoder v.int64Field)
+        , (JE.list JE.int v.uint32Field)
+        , (JE.list numericStringEncoder v.uint64Field)
+        , (JE.list JE.int v.sint32Field)
+        , (JE.list numericStringEncoder v.sint64Field)
+        , (JE.list JE.int v.fixed32Field)
+        , (JE.list numericStringEncoder v.fixed64Field)
+        , (JE.list JE.int v.sfixed32Field)
+        , (JE.list numericStringEncoder v.sfixed64Field)
+        , (JE.list JE.bool v.boolField)
+        , (JE.list JE.string v.stringField)
+        , (JE.list enumPortEncoder v.enumField)
+        , (JE.list subMessagePortEncoder v.subMessage)
         ]
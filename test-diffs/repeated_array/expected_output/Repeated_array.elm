@@ -0,0 +1,350 @@
+module Repeated_array exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: repeated_array.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Array
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- omitWhen encodes null in place of default values.  The javascript
+message constructor treats empty slots in the backing array as unset,
+which keeps them out of the serialized message.
+-}
+omitWhen : (a -> Bool) -> (a -> JE.Value) -> a -> JE.Value
+omitWhen isDefault enc v =
+    if isDefault v then
+        JE.null
+
+    else
+        enc v
+
+
+{- arrayMessage and objectMessage only run a message decoder on a value of
+the matching shape.  Otherwise a decoder for one format would succeed on the
+other, with every field missing and so set to its default.
+-}
+arrayMessage : JD.Decoder a -> JD.Decoder a
+arrayMessage decoder =
+    JD.list JD.value |> JD.andThen (\_ -> decoder)
+
+
+objectMessage : JD.Decoder a -> JD.Decoder a
+objectMessage decoder =
+    JD.keyValuePairs JD.value |> JD.andThen (\_ -> decoder)
+
+
+{- fieldWithDefault is the object format counterpart of idxWithDefault.
+-}
+fieldWithDefault : String -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+fieldWithDefault name decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.field name (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeField is the object format counterpart of maybeIdx.
+-}
+maybeField : String -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeField name decoder =
+    JD.map2 (|>)
+        (JD.maybe (JD.field name JD.value)
+            |> JD.andThen
+                (\value ->
+                    case value of
+                        Just _ ->
+                            JD.field name (JD.nullable decoder)
+
+                        Nothing ->
+                            JD.succeed Nothing
+                )
+        )
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+{- mergeField keeps the left value unless the right one differs from the
+field's default.
+-}
+mergeField : a -> a -> a -> a
+mergeField default left right =
+    if right == default then
+        left
+
+    else
+        right
+
+
+{- mergeMaybe keeps the left value unless the right one is set.  It doesn't
+compare the values, so it also merges the JD.Value of Struct, Value and
+ListValue fields, which (==) doesn't support.
+-}
+mergeMaybe : Maybe a -> Maybe a -> Maybe a
+mergeMaybe left right =
+    case right of
+        Just _ ->
+            right
+
+        Nothing ->
+            left
+
+
+{- mergeUnless keeps the left value if the right one is empty, like mergeMaybe
+for collections.
+-}
+mergeUnless : (a -> Bool) -> a -> a -> a
+mergeUnless isEmpty left right =
+    if isEmpty right then
+        left
+
+    else
+        right
+
+
+type Color
+    = ColorUnspecified -- 0
+    | ColorRed -- 1
+
+
+colorPortDecoder : JD.Decoder Color
+colorPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    ColorUnspecified
+
+                1 ->
+                    ColorRed
+
+                _ ->
+                    ColorUnspecified
+    in
+        JD.map lookup JD.int
+
+
+colorDefault : Color
+colorDefault = ColorUnspecified
+
+
+colorPortEncoder : Color -> JE.Value
+colorPortEncoder v =
+    let
+        lookup s =
+            case s of
+                ColorUnspecified ->
+                    0
+
+                ColorRed ->
+                    1
+
+    in
+        JE.int <| lookup v
+
+
+type alias Point =
+    { x : Int -- 1
+    , y : Int -- 2
+    }
+
+
+defaultPoint : Point
+defaultPoint =
+  {x = 0
+  , y = 0
+  }
+
+
+-- pointPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+pointPortDecoder : JD.Decoder Point
+pointPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Point
+                |> idxWithDefault 0 intDecoder 0
+                |> idxWithDefault 1 intDecoder 0
+            )
+        , objectMessage
+            (decode Point
+                |> fieldWithDefault "x" intDecoder 0
+                |> fieldWithDefault "y" intDecoder 0
+            )
+        ]
+
+
+-- pointPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+pointPortEncoder : Point -> JE.Value
+pointPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (omitWhen ((==) 0) JE.int v.x)
+        , (omitWhen ((==) 0) JE.int v.y)
+        ]
+
+
+-- mergePoint overlays the fields of right that are not set to their default onto left.
+mergePoint : Point -> Point -> Point
+mergePoint left right =
+    { left
+        | x = mergeField 0 left.x right.x
+        , y = mergeField 0 left.y right.y
+    }
+
+
+type alias Path =
+    { points : Array.Array Point -- 1
+    , labels : Array.Array String -- 2
+    , colors : Array.Array Color -- 3
+    , times : Array.Array Timestamp -- 4
+    , ids : Array.Array Int -- 5
+    }
+
+
+defaultPath : Path
+defaultPath =
+  {points = Array.empty
+  , labels = Array.empty
+  , colors = Array.empty
+  , times = Array.empty
+  , ids = Array.empty
+  }
+
+
+-- pathPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+pathPortDecoder : JD.Decoder Path
+pathPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (decode Path
+                |> idxWithDefault 0 (JD.array pointPortDecoder) Array.empty
+                |> idxWithDefault 1 (JD.array JD.string) Array.empty
+                |> idxWithDefault 2 (JD.array colorPortDecoder) Array.empty
+                |> idxWithDefault 3 (JD.array timestampDecoder) Array.empty
+                |> idxWithDefault 4 (JD.array intDecoder) Array.empty
+            )
+        , objectMessage
+            (decode Path
+                |> fieldWithDefault "points" (JD.array pointPortDecoder) Array.empty
+                |> fieldWithDefault "labels" (JD.array JD.string) Array.empty
+                |> fieldWithDefault "colors" (JD.array colorPortDecoder) Array.empty
+                |> fieldWithDefault "times" (JD.array timestampDecoder) Array.empty
+                |> fieldWithDefault "ids" (JD.array intDecoder) Array.empty
+            )
+        ]
+
+
+-- pathPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+pathPortEncoder : Path -> JE.Value
+pathPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (omitWhen Array.isEmpty (JE.array pointPortEncoder) v.points)
+        , (omitWhen Array.isEmpty (JE.array JE.string) v.labels)
+        , (omitWhen Array.isEmpty (JE.array colorPortEncoder) v.colors)
+        , (omitWhen Array.isEmpty (JE.array timestampEncoder) v.times)
+        , (omitWhen Array.isEmpty (JE.array numericStringEncoder) v.ids)
+        ]
+
+
+-- mergePath overlays the fields of right that are not set to their default onto left.
+mergePath : Path -> Path -> Path
+mergePath left right =
+    { left
+        | points = mergeField Array.empty left.points right.points
+        , labels = mergeField Array.empty left.labels right.labels
+        , colors = mergeField Array.empty left.colors right.colors
+        , times = mergeField Array.empty left.times right.times
+        , ids = mergeField Array.empty left.ids right.ids
+    }
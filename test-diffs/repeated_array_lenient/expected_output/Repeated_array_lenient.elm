@@ -0,0 +1,288 @@
+module Repeated_array_lenient exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: repeated_array_lenient.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Json.Decode.Pipeline as Pipeline
+import Array
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- lenientList decodes a list, or a single value as a list of one value, since
+some javascript serializers leave out the array for repeated fields with a
+single value.
+-}
+lenientList : JD.Decoder a -> JD.Decoder (List a)
+lenientList decoder =
+    JD.oneOf [ JD.list decoder, JD.map List.singleton decoder ]
+
+
+{- arrayMessage and objectMessage only run a message decoder on a value of
+the matching shape.  Otherwise a decoder for one format would succeed on the
+other, with every field missing and so set to its default.
+-}
+arrayMessage : JD.Decoder a -> JD.Decoder a
+arrayMessage decoder =
+    JD.list JD.value |> JD.andThen (\_ -> decoder)
+
+
+objectMessage : JD.Decoder a -> JD.Decoder a
+objectMessage decoder =
+    JD.keyValuePairs JD.value |> JD.andThen (\_ -> decoder)
+
+
+{- fieldWithDefault is the object format counterpart of idxWithDefault.
+-}
+fieldWithDefault : String -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+fieldWithDefault name decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.field name (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeField is the object format counterpart of maybeIdx.
+-}
+maybeField : String -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeField name decoder =
+    JD.map2 (|>)
+        (JD.maybe (JD.field name JD.value)
+            |> JD.andThen
+                (\value ->
+                    case value of
+                        Just _ ->
+                            JD.field name (JD.nullable decoder)
+
+                        Nothing ->
+                            JD.succeed Nothing
+                )
+        )
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type Color
+    = ColorUnspecified -- 0
+    | ColorRed -- 1
+
+
+colorPortDecoder : JD.Decoder Color
+colorPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    ColorUnspecified
+
+                1 ->
+                    ColorRed
+
+                _ ->
+                    ColorUnspecified
+    in
+        JD.map lookup JD.int
+
+
+colorDefault : Color
+colorDefault = ColorUnspecified
+
+
+colorPortEncoder : Color -> JE.Value
+colorPortEncoder v =
+    let
+        lookup s =
+            case s of
+                ColorUnspecified ->
+                    0
+
+                ColorRed ->
+                    1
+
+    in
+        JE.int <| lookup v
+
+
+type alias Point =
+    { x : Int -- 1
+    , y : Int -- 2
+    }
+
+
+defaultPoint : Point
+defaultPoint =
+  {x = 0
+  , y = 0
+  }
+
+
+-- pointPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+pointPortDecoder : JD.Decoder Point
+pointPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (JD.succeed Point
+                |> idxWithDefault 0 intDecoder 0
+                |> idxWithDefault 1 intDecoder 0
+            )
+        , objectMessage
+            (JD.succeed Point
+                |> Pipeline.optional "x" intDecoder 0
+                |> Pipeline.optional "y" intDecoder 0
+            )
+        ]
+
+
+-- pointPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+pointPortEncoder : Point -> JE.Value
+pointPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.int v.x)
+        , (JE.int v.y)
+        ]
+
+
+type alias Path =
+    { points : Array.Array Point -- 1
+    , labels : Array.Array String -- 2
+    , colors : Array.Array Color -- 3
+    , times : Array.Array Timestamp -- 4
+    , ids : Array.Array Int -- 5
+    }
+
+
+defaultPath : Path
+defaultPath =
+  {points = Array.empty
+  , labels = Array.empty
+  , colors = Array.empty
+  , times = Array.empty
+  , ids = Array.empty
+  }
+
+
+-- pathPortDecoder is used to decode protobuf messages from ports, following either the
+-- javascript array format or the canonical JSON object format.
+pathPortDecoder : JD.Decoder Path
+pathPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ arrayMessage
+            (JD.succeed Path
+                |> idxWithDefault 0 (JD.map Array.fromList (lenientList pointPortDecoder)) Array.empty
+                |> idxWithDefault 1 (JD.map Array.fromList (lenientList JD.string)) Array.empty
+                |> idxWithDefault 2 (JD.map Array.fromList (lenientList colorPortDecoder)) Array.empty
+                |> idxWithDefault 3 (JD.map Array.fromList (lenientList timestampDecoder)) Array.empty
+                |> idxWithDefault 4 (JD.map Array.fromList (lenientList intDecoder)) Array.empty
+            )
+        , objectMessage
+            (JD.succeed Path
+                |> Pipeline.optional "points" (JD.map Array.fromList (lenientList pointPortDecoder)) Array.empty
+                |> Pipeline.optional "labels" (JD.map Array.fromList (lenientList JD.string)) Array.empty
+                |> Pipeline.optional "colors" (JD.map Array.fromList (lenientList colorPortDecoder)) Array.empty
+                |> Pipeline.optional "times" (JD.map Array.fromList (lenientList timestampDecoder)) Array.empty
+                |> Pipeline.optional "ids" (JD.map Array.fromList (lenientList intDecoder)) Array.empty
+            )
+        ]
+
+
+-- pathPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+pathPortEncoder : Path -> JE.Value
+pathPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.array pointPortEncoder v.points)
+        , (JE.array JE.string v.labels)
+        , (JE.array colorPortEncoder v.colors)
+        , (JE.array timestampEncoder v.times)
+        , (JE.array numericStringEncoder v.ids)
+        ]
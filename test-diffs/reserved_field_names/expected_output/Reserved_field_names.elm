@@ -0,0 +1,207 @@
+module Reserved_field_names exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: reserved_field_names.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Dict
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Inner =
+    { name : String -- 1
+    }
+
+
+defaultInner : Inner
+defaultInner =
+  {name = ""
+  }
+
+
+-- innerPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+innerPortDecoder : JD.Decoder Inner
+innerPortDecoder =
+    JD.lazy <| \_ -> decode Inner
+        |> idxWithDefault 0 JD.string ""
+
+
+-- innerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+innerPortEncoder : Inner -> JE.Value
+innerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        ]
+
+
+type alias Keywords =
+    { type_ : String -- 1
+    , module_ : Int -- 2
+    , if_ : List String -- 3
+    , then_ : Maybe Inner -- 4
+    , let_ : Dict.Dict String String -- 5
+    }
+
+
+defaultKeywords : Keywords
+defaultKeywords =
+  {type_ = ""
+  , module_ = 0
+  , if_ = []
+  , then_ = Nothing
+  , let_ = Dict.empty
+  }
+
+
+-- keywordsPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+keywordsPortDecoder : JD.Decoder Keywords
+keywordsPortDecoder =
+    JD.lazy <| \_ -> decode Keywords
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+        |> idxWithDefault 2 (JD.list JD.string) []
+        |> maybeIdx 3 innerPortDecoder
+        |> mapEntries 4 JD.string JD.string
+
+
+-- keywordsPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+keywordsPortEncoder : Keywords -> JE.Value
+keywordsPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.type_)
+        , (JE.int v.module_)
+        , (JE.list JE.string v.if_)
+        , (maybeEncoder innerPortEncoder v.then_)
+        , (mapEntriesFieldEncoder JE.string JE.string v.let_)
+        ]
+
+
+type alias Keywords_LetEntry =
+    { key : String -- 1
+    , value : String -- 2
+    }
+
+
+defaultKeywords_LetEntry : Keywords_LetEntry
+defaultKeywords_LetEntry =
+  {key = ""
+  , value = ""
+  }
+
+
+-- keywords_LetEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+keywords_LetEntryPortDecoder : JD.Decoder Keywords_LetEntry
+keywords_LetEntryPortDecoder =
+    JD.lazy <| \_ -> decode Keywords_LetEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 JD.string ""
+
+
+-- keywords_LetEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+keywords_LetEntryPortEncoder : Keywords_LetEntry -> JE.Value
+keywords_LetEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.key)
+        , (JE.string v.value)
+        ]
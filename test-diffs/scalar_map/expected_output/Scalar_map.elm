@@ -0,0 +1,172 @@
+module Scalar_map exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: scalar_map.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Decimal
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Invoice =
+    { total : Decimal.Decimal -- 1
+    , lines : List Decimal.Decimal -- 2
+    , id : Id -- 3
+    , count : Int -- 4
+    , discount : Invoice_Discount
+    }
+
+
+defaultInvoice : Invoice
+defaultInvoice =
+  {total = Decimal.zero
+  , lines = []
+  , id = idDefault
+  , count = 0
+  , discount = Invoice_DiscountUnspecified
+  }
+
+
+-- invoicePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+invoicePortDecoder : JD.Decoder Invoice
+invoicePortDecoder =
+    JD.lazy <| \_ -> decode Invoice
+        |> idxWithDefault 0 Decimal.decoder Decimal.zero
+        |> idxWithDefault 1 (JD.list Decimal.decoder) []
+        |> idxWithDefault 2 idDecoder idDefault
+        |> idxWithDefault 3 intDecoder 0
+        |> custom invoice_DiscountPortDecoder
+
+
+-- invoicePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+invoicePortEncoder : Invoice -> JE.Value
+invoicePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (Decimal.encoder v.total)
+        , (JE.list Decimal.encoder v.lines)
+        , (idEncoder v.id)
+        , (JE.int v.count)
+        , (invoice_DiscountPortEncoder 5 v.discount)
+        , (invoice_DiscountPortEncoder 6 v.discount)
+        ]
+
+
+type Invoice_Discount
+    = Invoice_DiscountUnspecified
+    | Invoice_Amount Decimal.Decimal -- 5
+    | Invoice_Code String -- 6
+
+
+invoice_DiscountPortDecoder : JD.Decoder Invoice_Discount
+invoice_DiscountPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Invoice_Amount (JD.index 4 (failOnNull Decimal.decoder))
+        , JD.map Invoice_Code (JD.index 5 (failOnNull JD.string))
+        , JD.succeed Invoice_DiscountUnspecified
+        ]
+
+
+invoice_DiscountPortEncoder : Int -> Invoice_Discount -> JE.Value
+invoice_DiscountPortEncoder idx v =
+    case v of
+        Invoice_DiscountUnspecified ->
+            JE.null
+
+        Invoice_Amount x ->
+            if idx == 5 then Decimal.encoder x else JE.null
+
+        Invoice_Code x ->
+            if idx == 6 then JE.string x else JE.null
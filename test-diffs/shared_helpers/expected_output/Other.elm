@@ -0,0 +1,84 @@
+module Other exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: other.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Shared.Helpers exposing (..)
+
+
+type alias Customer =
+    { name : String -- 1
+    , contact : Customer_Contact
+    }
+
+
+defaultCustomer : Customer
+defaultCustomer =
+  {name = ""
+  , contact = Customer_ContactUnspecified
+  }
+
+
+-- customerPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+customerPortDecoder : JD.Decoder Customer
+customerPortDecoder =
+    JD.lazy <| \_ -> decode Customer
+        |> idxWithDefault 0 JD.string ""
+        |> custom customer_ContactPortDecoder
+
+
+-- customerPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+customerPortEncoder : Customer -> JE.Value
+customerPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        , (customer_ContactPortEncoder 2 v.contact)
+        , (customer_ContactPortEncoder 3 v.contact)
+        ]
+
+
+-- mergeCustomer overlays the fields of right that are not set to their default onto left.
+mergeCustomer : Customer -> Customer -> Customer
+mergeCustomer left right =
+    { left
+        | name = mergeField "" left.name right.name
+        , contact = mergeField Customer_ContactUnspecified left.contact right.contact
+    }
+
+
+type Customer_Contact
+    = Customer_ContactUnspecified
+    | Customer_Email String -- 2
+    | Customer_Phone String -- 3
+
+
+customer_ContactPortDecoder : JD.Decoder Customer_Contact
+customer_ContactPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Customer_Email (JD.index 1 (failOnNull JD.string))
+        , JD.map Customer_Phone (JD.index 2 (failOnNull JD.string))
+        , JD.succeed Customer_ContactUnspecified
+        ]
+
+
+customer_ContactPortEncoder : Int -> Customer_Contact -> JE.Value
+customer_ContactPortEncoder idx v =
+    case v of
+        Customer_ContactUnspecified ->
+            JE.null
+
+        Customer_Email x ->
+            if idx == 2 then JE.string x else JE.null
+
+        Customer_Phone x ->
+            if idx == 3 then JE.string x else JE.null
@@ -0,0 +1,62 @@
+module Shared_helpers exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: shared_helpers.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+import Other exposing (..)
+
+import Shared.Helpers exposing (..)
+
+
+type alias Order =
+    { id : String -- 1
+    , count : Maybe Int -- 2
+    , customer : Maybe Customer -- 3
+    }
+
+
+defaultOrder : Order
+defaultOrder =
+  {id = ""
+  , count = Nothing
+  , customer = Nothing
+  }
+
+
+-- orderPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+orderPortDecoder : JD.Decoder Order
+orderPortDecoder =
+    JD.lazy <| \_ -> decode Order
+        |> idxWithDefault 0 JD.string ""
+        |> maybeIdx 1 intDecoder
+        |> maybeIdx 2 customerPortDecoder
+
+
+-- orderPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+orderPortEncoder : Order -> JE.Value
+orderPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.id)
+        , (maybeEncoder JE.int v.count)
+        , (maybeEncoder customerPortEncoder v.customer)
+        ]
+
+
+-- mergeOrder overlays the fields of right that are not set to their default onto left.
+mergeOrder : Order -> Order -> Order
+mergeOrder left right =
+    { left
+        | id = mergeField "" left.id right.id
+        , count = mergeField Nothing left.count right.count
+        , customer = mergeField Nothing left.customer right.customer
+    }
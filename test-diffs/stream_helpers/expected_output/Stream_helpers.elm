@@ -0,0 +1,256 @@
+module Stream_helpers exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: stream_helpers.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- decodeStreamLine decodes one line of a newline-delimited JSON stream and
+adds it to the front of the values decoded so far, for folding over the lines
+of each chunk as it arrives.  Blank lines are skipped, and the first error is
+kept.
+-}
+decodeStreamLine : JD.Decoder a -> String -> Result JD.Error (List a) -> Result JD.Error (List a)
+decodeStreamLine decoder line decoded =
+    if String.isEmpty (String.trim line) then
+        decoded
+
+    else
+        decoded
+            |> Result.andThen (\values -> Result.map (\v -> v :: values) (JD.decodeString decoder line))
+
+
+{- decodeStream decodes every line of a newline-delimited JSON stream, in
+order.
+-}
+decodeStream : JD.Decoder a -> String -> Result JD.Error (List a)
+decodeStream decoder stream =
+    List.foldl (decodeStreamLine decoder) (Ok []) (String.lines stream)
+        |> Result.map List.reverse
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias LogEntry =
+    { message : String -- 1
+    , timestamp : Int -- 2
+    , level : LogEntry_Level -- 3
+    }
+
+
+defaultLogEntry : LogEntry
+defaultLogEntry =
+  {message = ""
+  , timestamp = 0
+  , level = logEntry_LevelDefault
+  }
+
+
+-- logEntryPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+logEntryPortDecoder : JD.Decoder LogEntry
+logEntryPortDecoder =
+    JD.lazy <| \_ -> decode LogEntry
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+        |> idxWithDefault 2 logEntry_LevelPortDecoder logEntry_LevelDefault
+
+
+-- logEntryPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+logEntryPortEncoder : LogEntry -> JE.Value
+logEntryPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.message)
+        , (numericStringEncoder v.timestamp)
+        , (logEntry_LevelPortEncoder v.level)
+        ]
+
+
+-- logEntryListPortDecoder decodes a top level JSON array of LogEntry, e.g. a page of
+-- results.  Decode newline-delimited streams with
+-- decodeStream logEntryPortDecoder.
+logEntryListPortDecoder : JD.Decoder (List LogEntry)
+logEntryListPortDecoder =
+    JD.list logEntryPortDecoder
+
+
+type LogEntry_Level
+    = LogEntry_LevelUnspecified -- 0
+    | LogEntry_LevelInfo -- 1
+    | LogEntry_LevelError -- 2
+
+
+logEntry_LevelPortDecoder : JD.Decoder LogEntry_Level
+logEntry_LevelPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    LogEntry_LevelUnspecified
+
+                1 ->
+                    LogEntry_LevelInfo
+
+                2 ->
+                    LogEntry_LevelError
+
+                _ ->
+                    LogEntry_LevelUnspecified
+    in
+        JD.map lookup JD.int
+
+
+logEntry_LevelDefault : LogEntry_Level
+logEntry_LevelDefault = LogEntry_LevelUnspecified
+
+
+logEntry_LevelPortEncoder : LogEntry_Level -> JE.Value
+logEntry_LevelPortEncoder v =
+    let
+        lookup s =
+            case s of
+                LogEntry_LevelUnspecified ->
+                    0
+
+                LogEntry_LevelInfo ->
+                    1
+
+                LogEntry_LevelError ->
+                    2
+
+    in
+        JE.int <| lookup v
+
+
+type alias Page =
+    { entries : List LogEntry -- 1
+    , nextPageToken : String -- 2
+    }
+
+
+defaultPage : Page
+defaultPage =
+  {entries = []
+  , nextPageToken = ""
+  }
+
+
+-- pagePortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+pagePortDecoder : JD.Decoder Page
+pagePortDecoder =
+    JD.lazy <| \_ -> decode Page
+        |> idxWithDefault 0 (JD.list logEntryPortDecoder) []
+        |> idxWithDefault 1 JD.string ""
+
+
+-- pagePortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+pagePortEncoder : Page -> JE.Value
+pagePortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.list logEntryPortEncoder v.entries)
+        , (JE.string v.nextPageToken)
+        ]
+
+
+-- pageListPortDecoder decodes a top level JSON array of Page, e.g. a page of
+-- results.  Decode newline-delimited streams with
+-- decodeStream pagePortDecoder.
+pageListPortDecoder : JD.Decoder (List Page)
+pageListPortDecoder =
+    JD.list pagePortDecoder
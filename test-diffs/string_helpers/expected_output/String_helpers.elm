@@ -0,0 +1,184 @@
+module String_helpers exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: string_helpers.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Greeting =
+    { text : String -- 1
+    , sender : Maybe Greeting_Sender -- 2
+    }
+
+
+defaultGreeting : Greeting
+defaultGreeting =
+  {text = ""
+  , sender = Nothing
+  }
+
+
+-- greetingPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+greetingPortDecoder : JD.Decoder Greeting
+greetingPortDecoder =
+    JD.lazy <| \_ -> decode Greeting
+        |> idxWithDefault 0 JD.string ""
+        |> maybeIdx 1 greeting_SenderPortDecoder
+
+
+-- greetingPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+greetingPortEncoder : Greeting -> JE.Value
+greetingPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.text)
+        , (maybeEncoder greeting_SenderPortEncoder v.sender)
+        ]
+
+
+-- encodeGreeting encodes a Greeting to a JSON string.
+encodeGreeting : Greeting -> String
+encodeGreeting v =
+    JE.encode 0 (greetingPortEncoder v)
+
+
+-- decodeGreeting decodes a Greeting from a JSON string.
+decodeGreeting : String -> Result JD.Error Greeting
+decodeGreeting s =
+    JD.decodeString greetingPortDecoder s
+
+
+type alias Greeting_Sender =
+    { name : String -- 1
+    }
+
+
+defaultGreeting_Sender : Greeting_Sender
+defaultGreeting_Sender =
+  {name = ""
+  }
+
+
+-- greeting_SenderPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+greeting_SenderPortDecoder : JD.Decoder Greeting_Sender
+greeting_SenderPortDecoder =
+    JD.lazy <| \_ -> decode Greeting_Sender
+        |> idxWithDefault 0 JD.string ""
+
+
+-- greeting_SenderPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+greeting_SenderPortEncoder : Greeting_Sender -> JE.Value
+greeting_SenderPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        ]
+
+
+-- encodeGreeting_Sender encodes a Greeting_Sender to a JSON string.
+encodeGreeting_Sender : Greeting_Sender -> String
+encodeGreeting_Sender v =
+    JE.encode 0 (greeting_SenderPortEncoder v)
+
+
+-- decodeGreeting_Sender decodes a Greeting_Sender from a JSON string.
+decodeGreeting_Sender : String -> Result JD.Error Greeting_Sender
+decodeGreeting_Sender s =
+    JD.decodeString greeting_SenderPortDecoder s
@@ -0,0 +1,267 @@
+module Strip_enum_prefix exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: strip_enum_prefix.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type Color
+    = Unspecified -- 0
+    | Red -- 1
+    | Green -- 2
+
+
+colorPortDecoder : JD.Decoder Color
+colorPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    Unspecified
+
+                1 ->
+                    Red
+
+                2 ->
+                    Green
+
+                _ ->
+                    Unspecified
+    in
+        JD.map lookup JD.int
+
+
+colorDefault : Color
+colorDefault = Unspecified
+
+
+colorPortEncoder : Color -> JE.Value
+colorPortEncoder v =
+    let
+        lookup s =
+            case s of
+                Unspecified ->
+                    0
+
+                Red ->
+                    1
+
+                Green ->
+                    2
+
+    in
+        JE.int <| lookup v
+
+
+type HTTPStatus
+    = Unknown -- 0
+    | Ok -- 200
+    | NotFound -- 404
+
+
+hTTPStatusPortDecoder : JD.Decoder HTTPStatus
+hTTPStatusPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    Unknown
+
+                200 ->
+                    Ok
+
+                404 ->
+                    NotFound
+
+                _ ->
+                    Unknown
+    in
+        JD.map lookup JD.int
+
+
+hTTPStatusDefault : HTTPStatus
+hTTPStatusDefault = Unknown
+
+
+hTTPStatusPortEncoder : HTTPStatus -> JE.Value
+hTTPStatusPortEncoder v =
+    let
+        lookup s =
+            case s of
+                Unknown ->
+                    0
+
+                Ok ->
+                    200
+
+                NotFound ->
+                    404
+
+    in
+        JE.int <| lookup v
+
+
+type alias Paint =
+    { color : Color -- 1
+    , finish : Paint_PaintFinish -- 2
+    }
+
+
+defaultPaint : Paint
+defaultPaint =
+  {color = colorDefault
+  , finish = paint_PaintFinishDefault
+  }
+
+
+-- paintPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+paintPortDecoder : JD.Decoder Paint
+paintPortDecoder =
+    JD.lazy <| \_ -> decode Paint
+        |> idxWithDefault 0 colorPortDecoder colorDefault
+        |> idxWithDefault 1 paint_PaintFinishPortDecoder paint_PaintFinishDefault
+
+
+-- paintPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+paintPortEncoder : Paint -> JE.Value
+paintPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (colorPortEncoder v.color)
+        , (paint_PaintFinishPortEncoder v.finish)
+        ]
+
+
+type Paint_PaintFinish
+    = Paint_Matte -- 0
+    | Paint_PaintFinish2D -- 1
+
+
+paint_PaintFinishPortDecoder : JD.Decoder Paint_PaintFinish
+paint_PaintFinishPortDecoder =
+    let
+        lookup v =
+            case v of
+                0 ->
+                    Paint_Matte
+
+                1 ->
+                    Paint_PaintFinish2D
+
+                _ ->
+                    Paint_Matte
+    in
+        JD.map lookup JD.int
+
+
+paint_PaintFinishDefault : Paint_PaintFinish
+paint_PaintFinishDefault = Paint_Matte
+
+
+paint_PaintFinishPortEncoder : Paint_PaintFinish -> JE.Value
+paint_PaintFinishPortEncoder v =
+    let
+        lookup s =
+            case s of
+                Paint_Matte ->
+                    0
+
+                Paint_PaintFinish2D ->
+                    1
+
+    in
+        JE.int <| lookup v
@@ -0,0 +1,188 @@
+module Struct_null_value exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: struct_null_value.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+{- omitWhen encodes null in place of default values.  The javascript
+message constructor treats empty slots in the backing array as unset,
+which keeps them out of the serialized message.
+-}
+omitWhen : (a -> Bool) -> (a -> JE.Value) -> a -> JE.Value
+omitWhen isDefault enc v =
+    if isDefault v then
+        JE.null
+
+    else
+        enc v
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+type alias Document =
+    { metadata : Maybe JD.Value -- 1
+    , value : Maybe JD.Value -- 2
+    , items : Maybe JD.Value -- 3
+    , nothing : () -- 4
+    , values : List JD.Value -- 5
+    , kind : Document_Kind
+    }
+
+
+defaultDocument : Document
+defaultDocument =
+  {metadata = Nothing
+  , value = Nothing
+  , items = Nothing
+  , nothing = ()
+  , values = []
+  , kind = Document_KindUnspecified
+  }
+
+
+-- documentPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+documentPortDecoder : JD.Decoder Document
+documentPortDecoder =
+    JD.lazy <| \_ -> decode Document
+        |> maybeIdx 0 JD.value
+        |> maybeIdx 1 JD.value
+        |> maybeIdx 2 JD.value
+        |> idxWithDefault 3 (JD.oneOf [ JD.null (), JD.map (always ()) JD.int ]) ()
+        |> idxWithDefault 4 (JD.list JD.value) []
+        |> custom document_KindPortDecoder
+
+
+-- documentPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+documentPortEncoder : Document -> JE.Value
+documentPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (maybeEncoder identity v.metadata)
+        , (maybeEncoder identity v.value)
+        , (maybeEncoder identity v.items)
+        , (omitWhen ((==) ()) (always (JE.int 0)) v.nothing)
+        , (omitWhen List.isEmpty (JE.list identity) v.values)
+        , (document_KindPortEncoder 6 v.kind)
+        , (document_KindPortEncoder 7 v.kind)
+        ]
+
+
+type Document_Kind
+    = Document_KindUnspecified
+    | Document_Empty () -- 6
+    | Document_Text String -- 7
+
+
+document_KindPortDecoder : JD.Decoder Document_Kind
+document_KindPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Document_Empty (JD.index 5 (failOnNull (JD.oneOf [ JD.null (), JD.map (always ()) JD.int ])))
+        , JD.map Document_Text (JD.index 6 (failOnNull JD.string))
+        , JD.succeed Document_KindUnspecified
+        ]
+
+
+document_KindPortEncoder : Int -> Document_Kind -> JE.Value
+document_KindPortEncoder idx v =
+    case v of
+        Document_KindUnspecified ->
+            JE.null
+
+        Document_Empty x ->
+            if idx == 6 then (always (JE.int 0)) x else JE.null
+
+        Document_Text x ->
+            if idx == 7 then JE.string x else JE.null
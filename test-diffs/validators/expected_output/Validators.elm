@@ -0,0 +1,296 @@
+module Validators exposing (..)
+
+-- DO NOT EDIT
+-- AUTOGENERATED BY THE ELM PROTOCOL BUFFER COMPILER
+-- https://github.com/tiziano88/elm-protobuf
+-- source file: validators.proto
+
+import Protobuf exposing (..)
+
+import Json.Decode as JD
+import Json.Encode as JE
+
+
+-- noop is here because I don't know elm well enough to know how to provide
+-- a (a -> a) function to JE.list without it.
+noop : JE.Value -> JE.Value
+noop v =
+    v
+
+
+valueList : List JE.Value -> JE.Value
+valueList l =
+    JE.list noop l
+
+
+custom : JD.Decoder a -> JD.Decoder (a -> b) -> JD.Decoder b
+custom =
+    JD.map2 (|>)
+
+
+{- idxWithDefault decodes a field as its default when the index is absent or
+holds an explicit null, which some javascript bridges write for unset fields.
+-}
+idxWithDefault : Int -> JD.Decoder a -> a -> JD.Decoder (a -> b) -> JD.Decoder b
+idxWithDefault idx decoder default =
+    JD.map2 (|>) (JD.oneOf [ JD.index idx (JD.oneOf [ JD.null default, decoder ]), JD.succeed default ])
+
+
+{- maybeIdx decodes an optional field.  An absent index or an explicit
+null decode to Nothing, but a present value that fails to decode is an
+error rather than being silently dropped (which is what JD.maybe does).
+-}
+maybeIdx : Int -> JD.Decoder a -> JD.Decoder (Maybe a -> b) -> JD.Decoder b
+maybeIdx idx decoder =
+    JD.map2 (|>)
+        (JD.list JD.value
+            |> JD.andThen
+                (\values ->
+                    if List.length values > idx then
+                        JD.index idx (JD.nullable decoder)
+
+                    else
+                        JD.succeed Nothing
+                )
+        )
+
+
+maybeEncoder : (a -> JE.Value) -> Maybe a -> JE.Value
+maybeEncoder enc v =
+    case v of
+        Nothing ->
+            JE.null
+
+        Just av ->
+            enc av
+
+
+type Field a
+  = Null
+  | Present a
+
+
+{- failOnNull helps us handle oneof fields.  In JS land,
+oneofs are presented with empty slots in the backing
+array.  We need the empty slots to be translated to null
+since elm doesn't know how to handle empty slots in a
+list (which is totally fair).
+
+Then, the decoder for a oneof variant needs to fail if
+the value is null.  That's what this function does.
+-}
+failOnNull : JD.Decoder a -> JD.Decoder a
+failOnNull decoder =
+  JD.oneOf
+    [ JD.null Null
+    , JD.map Present decoder
+    ]
+    |> JD.andThen
+      (\v ->
+        case v of
+          Null ->
+            JD.fail "received null value"
+
+          Present fv ->
+            JD.succeed fv
+      )
+
+
+{- validated collects the errors of the checks that failed.
+-}
+validated : a -> List ( Bool, String ) -> Result (List String) a
+validated value checks =
+    let
+        failed ( ok, error ) =
+            if ok then
+                Nothing
+
+            else
+                Just error
+    in
+    case List.filterMap failed checks of
+        [] ->
+            Ok value
+
+        errors ->
+            Err errors
+
+
+type alias Address =
+    { street : String -- 1
+    , countryCode : String -- 2
+    }
+
+
+defaultAddress : Address
+defaultAddress =
+  {street = ""
+  , countryCode = ""
+  }
+
+
+-- addressPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+addressPortDecoder : JD.Decoder Address
+addressPortDecoder =
+    JD.lazy <| \_ -> decode Address
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 JD.string ""
+
+
+-- addressPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+addressPortEncoder : Address -> JE.Value
+addressPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.street)
+        , (JE.string v.countryCode)
+        ]
+
+
+validateAddress : Address -> Result (List String) Address
+validateAddress v =
+    validated v
+        [ ( List.length (String.toList v.street) >= 1, "street must be at least 1 characters long" )
+        , ( List.length (String.toList v.countryCode) == 2, "countryCode must be exactly 2 characters long" )
+        ]
+
+
+type alias Person =
+    { name : String -- 1
+    , age : Int -- 2
+    , score : Float -- 3
+    , offset : Int -- 4
+    , visits : Int -- 5
+    , address : Maybe Address -- 6
+    , tags : List String -- 7
+    , active : Bool -- 8
+    , contact : Person_Contact
+    }
+
+
+defaultPerson : Person
+defaultPerson =
+  {name = ""
+  , age = 0
+  , score = 0
+  , offset = 0
+  , visits = 0
+  , address = Nothing
+  , tags = []
+  , active = False
+  , contact = Person_ContactUnspecified
+  }
+
+
+-- personPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+personPortDecoder : JD.Decoder Person
+personPortDecoder =
+    JD.lazy <| \_ -> decode Person
+        |> idxWithDefault 0 JD.string ""
+        |> idxWithDefault 1 intDecoder 0
+        |> idxWithDefault 2 JD.float 0
+        |> idxWithDefault 3 intDecoder 0
+        |> idxWithDefault 4 intDecoder 0
+        |> maybeIdx 5 addressPortDecoder
+        |> idxWithDefault 6 (JD.list JD.string) []
+        |> idxWithDefault 7 JD.bool False
+        |> custom person_ContactPortDecoder
+
+
+-- personPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+personPortEncoder : Person -> JE.Value
+personPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.name)
+        , (JE.int v.age)
+        , (JE.float v.score)
+        , (JE.int v.offset)
+        , (numericStringEncoder v.visits)
+        , (maybeEncoder addressPortEncoder v.address)
+        , (JE.list JE.string v.tags)
+        , (JE.bool v.active)
+        , (person_ContactPortEncoder 9 v.contact)
+        , (person_ContactPortEncoder 10 v.contact)
+        ]
+
+
+validatePerson : Person -> Result (List String) Person
+validatePerson v =
+    validated v
+        [ ( List.length (String.toList v.name) >= 1, "name must be at least 1 characters long" )
+        , ( List.length (String.toList v.name) <= 64, "name must be at most 64 characters long" )
+        , ( v.age >= 0, "age must be at least 0" )
+        , ( v.age < 150, "age must be less than 150" )
+        , ( v.score > -0.5, "score must be greater than -0.5" )
+        , ( v.score <= 1.5, "score must be at most 1.5" )
+        , ( v.offset >= -10, "offset must be at least -10" )
+        , ( v.visits <= 1000, "visits must be at most 1000" )
+        , ( v.address /= Nothing, "address is required" )
+        , ( List.length v.tags >= 1, "tags must have at least 1 items" )
+        , ( List.length v.tags <= 3, "tags must have at most 3 items" )
+        ]
+
+
+type Person_Contact
+    = Person_ContactUnspecified
+    | Person_Email String -- 9
+    | Person_Phone String -- 10
+
+
+person_ContactPortDecoder : JD.Decoder Person_Contact
+person_ContactPortDecoder =
+    JD.lazy <| \_ -> JD.oneOf
+        [ JD.map Person_Email (JD.index 8 (failOnNull JD.string))
+        , JD.map Person_Phone (JD.index 9 (failOnNull JD.string))
+        , JD.succeed Person_ContactUnspecified
+        ]
+
+
+person_ContactPortEncoder : Int -> Person_Contact -> JE.Value
+person_ContactPortEncoder idx v =
+    case v of
+        Person_ContactUnspecified ->
+            JE.null
+
+        Person_Email x ->
+            if idx == 9 then JE.string x else JE.null
+
+        Person_Phone x ->
+            if idx == 10 then JE.string x else JE.null
+
+
+type alias Unvalidated =
+    { note : String -- 1
+    }
+
+
+defaultUnvalidated : Unvalidated
+defaultUnvalidated =
+  {note = ""
+  }
+
+
+-- unvalidatedPortDecoder is used to decode protobuf messages from ports, following the javascript
+-- array format.
+unvalidatedPortDecoder : JD.Decoder Unvalidated
+unvalidatedPortDecoder =
+    JD.lazy <| \_ -> decode Unvalidated
+        |> idxWithDefault 0 JD.string ""
+
+
+-- unvalidatedPortEncoder is used to encode protobuf messages for ports, so that javascript code
+-- may use the value in the message constructor.
+unvalidatedPortEncoder : Unvalidated -> JE.Value
+unvalidatedPortEncoder v =
+    -- javascript uses the field number to index into arrays, so we need to ensure that
+    -- the list has empty values at indexes that don't have fields.
+    valueList
+        [ (JE.string v.note)
+        ]